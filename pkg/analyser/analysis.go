@@ -10,6 +10,7 @@ import (
 	"github.com/r3labs/diff/v2"
 
 	"github.com/cloudskiff/driftctl/pkg/alerter"
+	"github.com/cloudskiff/driftctl/pkg/filter"
 	"github.com/cloudskiff/driftctl/pkg/resource"
 )
 
@@ -32,6 +33,36 @@ type Summary struct {
 	TotalUnmanaged int `json:"total_unmanaged"`
 	TotalDeleted   int `json:"total_missing"`
 	TotalManaged   int `json:"total_managed"`
+	TotalPlanned   int `json:"total_planned,omitempty"`
+}
+
+// ProviderInfo identifies the terraform provider used to scan a resource type
+type ProviderInfo struct {
+	Name    string `json:"provider_name"`
+	Version string `json:"provider_version"`
+}
+
+// ProviderCoverage summarizes the resources of a single provider
+type ProviderCoverage struct {
+	ProviderInfo
+	Summary  Summary `json:"summary"`
+	Coverage int     `json:"coverage"`
+}
+
+// IgnoredResource is a resource, or a field of a resource, filtered out by a driftignore rule
+type IgnoredResource struct {
+	Res *resource.Resource
+	// Path is set when only a field of the resource was ignored
+	Path []string
+	Rule filter.IgnoreRule
+}
+
+// IgnoreRuleCount is the number of resources and fields a driftignore rule filtered out
+type IgnoreRuleCount struct {
+	filter.IgnoreRule
+	Count int `json:"count"`
+	// Types counts the resources and fields filtered out by the rule for each resource type
+	Types map[string]int `json:"types"`
 }
 
 type Analysis struct {
@@ -39,6 +70,8 @@ type Analysis struct {
 	managed         []*resource.Resource
 	deleted         []*resource.Resource
 	differences     []Difference
+	planned         []*resource.Resource
+	ignored         []IgnoredResource
 	options         AnalyzerOptions
 	summary         Summary
 	alerts          alerter.Alerts
@@ -46,6 +79,8 @@ type Analysis struct {
 	Date            time.Time
 	ProviderName    string
 	ProviderVersion string
+	// ResourceTypeProviders maps resource types to the provider they were scanned with
+	ResourceTypeProviders map[string]ProviderInfo
 }
 
 type serializableDifference struct {
@@ -53,16 +88,31 @@ type serializableDifference struct {
 	Changelog Changelog                     `json:"changelog"`
 }
 
+type serializableIgnoredResource struct {
+	Res  resource.SerializableResource `json:"res"`
+	Path []string                      `json:"path,omitempty"`
+	Rule filter.IgnoreRule             `json:"rule"`
+}
+
+type serializableIgnored struct {
+	Rules     []IgnoreRuleCount             `json:"rules"`
+	Resources []serializableIgnoredResource `json:"resources"`
+}
+
 type serializableAnalysis struct {
 	Summary         Summary                                `json:"summary"`
 	Managed         []resource.SerializableResource        `json:"managed"`
 	Unmanaged       []resource.SerializableResource        `json:"unmanaged"`
 	Deleted         []resource.SerializableResource        `json:"missing"`
 	Differences     []serializableDifference               `json:"differences"`
+	Planned         []resource.SerializableResource        `json:"planned,omitempty"`
 	Coverage        int                                    `json:"coverage"`
 	Alerts          map[string][]alerter.SerializableAlert `json:"alerts"`
 	ProviderName    string                                 `json:"provider_name"`
 	ProviderVersion string                                 `json:"provider_version"`
+	ResourceTypes   map[string]ProviderInfo                `json:"resource_types,omitempty"`
+	Providers       []ProviderCoverage                     `json:"providers,omitempty"`
+	Ignored         *serializableIgnored                   `json:"ignored,omitempty"`
 }
 
 type GenDriftIgnoreOptions struct {
@@ -83,11 +133,22 @@ func (a Analysis) MarshalJSON() ([]byte, error) {
 		bla.Managed = append(bla.Managed, *resource.NewSerializableResource(m))
 	}
 	for _, u := range a.unmanaged {
-		bla.Unmanaged = append(bla.Unmanaged, *resource.NewSerializableResource(u))
+		res := resource.NewSerializableResource(u)
+		// Attributes of unmanaged resources are required to generate matching IaC,
+		// without deep mode only the ones needed to import them are known
+		if a.options.Deep && u.Attributes() != nil && len(*u.Attributes()) > 0 {
+			res.Attributes = u.Attributes()
+		} else if attrs := resource.ImportAttributes(u); attrs != nil {
+			res.Attributes = attrs
+		}
+		bla.Unmanaged = append(bla.Unmanaged, *res)
 	}
 	for _, d := range a.deleted {
 		bla.Deleted = append(bla.Deleted, *resource.NewSerializableResource(d))
 	}
+	for _, p := range a.planned {
+		bla.Planned = append(bla.Planned, *resource.NewSerializableResource(p))
+	}
 	for _, di := range a.differences {
 		bla.Differences = append(bla.Differences, serializableDifference{
 			Res:       *resource.NewSerializableResource(di.Res),
@@ -106,6 +167,21 @@ func (a Analysis) MarshalJSON() ([]byte, error) {
 	bla.Coverage = a.Coverage()
 	bla.ProviderName = a.ProviderName
 	bla.ProviderVersion = a.ProviderVersion
+	bla.ResourceTypes = a.ResourceTypeProviders
+	bla.Providers = a.ProvidersCoverage()
+	if a.options.ReportIgnored {
+		bla.Ignored = &serializableIgnored{
+			Rules:     a.IgnoredRules(),
+			Resources: make([]serializableIgnoredResource, 0, len(a.ignored)),
+		}
+		for _, i := range a.ignored {
+			bla.Ignored.Resources = append(bla.Ignored.Resources, serializableIgnoredResource{
+				Res:  *resource.NewSerializableResource(i.Res),
+				Path: i.Path,
+				Rule: i.Rule,
+			})
+		}
+	}
 
 	return json.Marshal(bla)
 }
@@ -117,27 +193,43 @@ func (a *Analysis) UnmarshalJSON(bytes []byte) error {
 	}
 	for _, u := range bla.Unmanaged {
 		a.AddUnmanaged(&resource.Resource{
-			Id:   u.Id,
-			Type: u.Type,
+			Id:    u.Id,
+			Type:  u.Type,
+			Attrs: u.Attributes,
+			Scope: u.Scope,
 		})
 	}
 	for _, d := range bla.Deleted {
 		a.AddDeleted(&resource.Resource{
-			Id:   d.Id,
-			Type: d.Type,
+			Id:     d.Id,
+			Type:   d.Type,
+			Source: d.Src(),
+			Scope:  d.Scope,
 		})
 	}
 	for _, m := range bla.Managed {
 		a.AddManaged(&resource.Resource{
-			Id:   m.Id,
-			Type: m.Type,
+			Id:     m.Id,
+			Type:   m.Type,
+			Source: m.Src(),
+			Scope:  m.Scope,
+		})
+	}
+	for _, p := range bla.Planned {
+		a.AddPlanned(&resource.Resource{
+			Id:     p.Id,
+			Type:   p.Type,
+			Source: p.Src(),
+			Scope:  p.Scope,
 		})
 	}
 	for _, di := range bla.Differences {
 		a.AddDifference(Difference{
 			Res: &resource.Resource{
-				Id:   di.Res.Id,
-				Type: di.Res.Type,
+				Id:     di.Res.Id,
+				Type:   di.Res.Type,
+				Source: di.Res.Src(),
+				Scope:  di.Res.Scope,
 			},
 			Changelog: di.Changelog,
 		})
@@ -154,6 +246,22 @@ func (a *Analysis) UnmarshalJSON(bytes []byte) error {
 	}
 	a.ProviderName = bla.ProviderName
 	a.ProviderVersion = bla.ProviderVersion
+	a.ResourceTypeProviders = bla.ResourceTypes
+	if bla.Ignored != nil {
+		a.options.ReportIgnored = true
+		for _, i := range bla.Ignored.Resources {
+			a.AddIgnored(IgnoredResource{
+				Res: &resource.Resource{
+					Id:     i.Res.Id,
+					Type:   i.Res.Type,
+					Source: i.Res.Src(),
+					Scope:  i.Res.Scope,
+				},
+				Path: i.Path,
+				Rule: i.Rule,
+			})
+		}
+	}
 	return nil
 }
 
@@ -183,11 +291,23 @@ func (a *Analysis) AddManaged(resources ...*resource.Resource) {
 	a.summary.TotalManaged += len(resources)
 }
 
+// AddPlanned adds resources declared in IaC but never applied, they are not part of the total
+// since they are neither expected to exist on the cloud provider nor missing from it
+func (a *Analysis) AddPlanned(resources ...*resource.Resource) {
+	a.planned = append(a.planned, resources...)
+	a.summary.TotalPlanned += len(resources)
+}
+
 func (a *Analysis) AddDifference(diffs ...Difference) {
 	a.differences = append(a.differences, diffs...)
 	a.summary.TotalDrifted += len(diffs)
 }
 
+// AddIgnored records resources and fields filtered out by driftignore rules, they are not part of the total
+func (a *Analysis) AddIgnored(ignored ...IgnoredResource) {
+	a.ignored = append(a.ignored, ignored...)
+}
+
 func (a *Analysis) SetAlerts(alerts alerter.Alerts) {
 	a.alerts = alerts
 }
@@ -199,6 +319,68 @@ func (a *Analysis) Coverage() int {
 	return 0
 }
 
+// SetResourceTypeProvider records the provider a resource type was scanned with
+func (a *Analysis) SetResourceTypeProvider(resourceType, providerName, providerVersion string) {
+	if a.ResourceTypeProviders == nil {
+		a.ResourceTypeProviders = make(map[string]ProviderInfo)
+	}
+	a.ResourceTypeProviders[resourceType] = ProviderInfo{Name: providerName, Version: providerVersion}
+}
+
+// ProvidersCoverage computes the summary of each provider from the provider of resource types,
+// resources of a type without provider are only part of the global summary
+func (a *Analysis) ProvidersCoverage() []ProviderCoverage {
+	if len(a.ResourceTypeProviders) == 0 {
+		return nil
+	}
+
+	summaries := make(map[ProviderInfo]*Summary)
+	summaryOf := func(res *resource.Resource) *Summary {
+		provider, exists := a.ResourceTypeProviders[res.ResourceType()]
+		if !exists {
+			return &Summary{}
+		}
+		if _, exists := summaries[provider]; !exists {
+			summaries[provider] = &Summary{}
+		}
+		return summaries[provider]
+	}
+	for _, res := range a.managed {
+		s := summaryOf(res)
+		s.TotalResources++
+		s.TotalManaged++
+	}
+	for _, res := range a.unmanaged {
+		s := summaryOf(res)
+		s.TotalResources++
+		s.TotalUnmanaged++
+	}
+	for _, res := range a.deleted {
+		s := summaryOf(res)
+		s.TotalResources++
+		s.TotalDeleted++
+	}
+	for _, res := range a.planned {
+		summaryOf(res).TotalPlanned++
+	}
+	for _, d := range a.differences {
+		summaryOf(d.Res).TotalDrifted++
+	}
+
+	coverages := make([]ProviderCoverage, 0, len(summaries))
+	for provider, summary := range summaries {
+		coverage := ProviderCoverage{ProviderInfo: provider, Summary: *summary}
+		if summary.TotalResources > 0 {
+			coverage.Coverage = int((float32(summary.TotalManaged) / float32(summary.TotalResources)) * 100.0)
+		}
+		coverages = append(coverages, coverage)
+	}
+	sort.Slice(coverages, func(i, j int) bool {
+		return coverages[i].Name < coverages[j].Name
+	})
+	return coverages
+}
+
 func (a *Analysis) Managed() []*resource.Resource {
 	return a.managed
 }
@@ -211,10 +393,44 @@ func (a *Analysis) Deleted() []*resource.Resource {
 	return a.deleted
 }
 
+func (a *Analysis) Planned() []*resource.Resource {
+	return a.planned
+}
+
 func (a *Analysis) Differences() []Difference {
 	return a.differences
 }
 
+func (a *Analysis) Ignored() []IgnoredResource {
+	return a.ignored
+}
+
+// IgnoredRules counts the resources and fields filtered out by each driftignore rule,
+// rules are sorted by file and line
+func (a *Analysis) IgnoredRules() []IgnoreRuleCount {
+	counts := make(map[filter.IgnoreRule]*IgnoreRuleCount)
+	for _, i := range a.ignored {
+		count, exists := counts[i.Rule]
+		if !exists {
+			count = &IgnoreRuleCount{IgnoreRule: i.Rule, Types: make(map[string]int)}
+			counts[i.Rule] = count
+		}
+		count.Count++
+		count.Types[i.Res.ResourceType()]++
+	}
+	rules := make([]IgnoreRuleCount, 0, len(counts))
+	for _, count := range counts {
+		rules = append(rules, *count)
+	}
+	sort.Slice(rules, func(i, j int) bool {
+		if rules[i].File != rules[j].File {
+			return rules[i].File < rules[j].File
+		}
+		return rules[i].Line < rules[j].Line
+	})
+	return rules
+}
+
 func (a *Analysis) Summary() Summary {
 	return a.summary
 }
@@ -226,6 +442,16 @@ func (a *Analysis) Alerts() alerter.Alerts {
 func (a *Analysis) SortResources() {
 	a.unmanaged = resource.Sort(a.unmanaged)
 	a.deleted = resource.Sort(a.deleted)
+	a.planned = resource.Sort(a.planned)
+	sort.SliceStable(a.ignored, func(i, j int) bool {
+		if a.ignored[i].Res.ResourceType() != a.ignored[j].Res.ResourceType() {
+			return a.ignored[i].Res.ResourceType() < a.ignored[j].Res.ResourceType()
+		}
+		if a.ignored[i].Res.ResourceId() != a.ignored[j].Res.ResourceId() {
+			return a.ignored[i].Res.ResourceId() < a.ignored[j].Res.ResourceId()
+		}
+		return strings.Join(a.ignored[i].Path, ".") < strings.Join(a.ignored[j].Path, ".")
+	})
 	a.differences = SortDifferences(a.differences)
 }
 
@@ -236,7 +462,7 @@ func (a *Analysis) DriftIgnoreList(opts GenDriftIgnoreOptions) (int, string) {
 
 	addResources := func(res ...*resource.Resource) {
 		for _, r := range res {
-			list = append(list, fmt.Sprintf("%s.%s", r.ResourceType(), escapeKey(r.ResourceId())))
+			list = append(list, DriftIgnoreLine(r))
 		}
 		resourceCount += len(res)
 	}
@@ -285,6 +511,11 @@ func SortChanges(changes []Change) []Change {
 	return changes
 }
 
+// DriftIgnoreLine returns the driftignore entry ignoring the given resource
+func DriftIgnoreLine(res *resource.Resource) string {
+	return fmt.Sprintf("%s.%s", res.ResourceType(), escapeKey(res.ResourceId()))
+}
+
 func escapeKey(line string) string {
 	line = strings.ReplaceAll(line, `\`, `\\`)
 	line = strings.ReplaceAll(line, `.`, `\.`)
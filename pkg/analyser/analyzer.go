@@ -1,6 +1,9 @@
 package analyser
 
 import (
+	"fmt"
+	"regexp"
+
 	"github.com/cloudskiff/driftctl/pkg/filter"
 	resourceaws "github.com/cloudskiff/driftctl/pkg/resource/aws"
 	"github.com/r3labs/diff/v2"
@@ -39,6 +42,8 @@ func (c *ComputedDiffAlert) ShouldIgnoreResource() bool {
 
 type AnalyzerOptions struct {
 	Deep bool
+	// ReportIgnored records the driftignore rule that filtered out each resource and field
+	ReportIgnored bool
 }
 
 type Analyzer struct {
@@ -56,28 +61,59 @@ func (a Analyzer) Analyze(remoteResources, resourcesFromState []*resource.Resour
 
 	// Iterate on remote resources and filter ignored resources
 	filteredRemoteResource := make([]*resource.Resource, 0, len(remoteResources))
+	ignoredRemoteResources := make([]*resource.Resource, 0)
 	for _, remoteRes := range remoteResources {
-		if a.filter.IsResourceIgnored(remoteRes) || a.alerter.IsResourceIgnored(remoteRes) {
+		if a.filter.IsResourceIgnored(remoteRes) {
+			ignoredRemoteResources = append(ignoredRemoteResources, remoteRes)
+			continue
+		}
+		if a.alerter.IsResourceIgnored(remoteRes) {
 			continue
 		}
 		filteredRemoteResource = append(filteredRemoteResource, remoteRes)
 	}
+	remoteIndex := newResourceIndex(filteredRemoteResource)
+
+	// Addresses found in states, resources declared in configuration files or planned with
+	// those addresses have been applied and are analyzed through their state counterpart.
+	// Plans know the address of each instance while configuration files do not, both are indexed
+	appliedAddresses := make(map[string]struct{}, 2*len(resourcesFromState))
+	for _, stateRes := range resourcesFromState {
+		if src, isStateSource := stateRes.Source.(*resource.TerraformStateSource); isStateSource {
+			appliedAddresses[stateRes.SourceString()+src.InstanceKey] = struct{}{}
+			appliedAddresses[configAddress(stateRes.SourceString())] = struct{}{}
+		}
+	}
 
 	haveComputedDiff := false
+	ignoredStateResources := make(map[string]struct{})
 	for _, stateRes := range resourcesFromState {
-		i, remoteRes, found := findCorrespondingRes(filteredRemoteResource, stateRes)
+		if a.filter.IsResourceIgnored(stateRes) {
+			a.recordIgnored(&analysis, stateRes, nil)
+			ignoredStateResources[ignoredKey(stateRes)] = struct{}{}
+			// Conditions on attributes may ignore the state resource without its remote
+			// twin, which lacks those attributes outside deep mode, consume it as well
+			remoteIndex.Match(stateRes)
+			continue
+		}
+		if a.alerter.IsResourceIgnored(stateRes) {
+			continue
+		}
 
-		if a.filter.IsResourceIgnored(stateRes) || a.alerter.IsResourceIgnored(stateRes) {
+		if src, isConfigSource := stateRes.Source.(*resource.TerraformConfigSource); isConfigSource {
+			if _, applied := appliedAddresses[stateRes.SourceString()+src.InstanceKey]; !applied {
+				analysis.AddPlanned(stateRes)
+			}
 			continue
 		}
 
+		// Matched resources are flagged as managed in the index, so it will remain only unmanaged ones
+		remoteRes, found := remoteIndex.Match(stateRes)
 		if !found {
 			analysis.AddDeleted(stateRes)
 			continue
 		}
 
-		// Remove managed resources, so it will remain only unmanaged ones
-		filteredRemoteResource = removeResourceByIndex(i, filteredRemoteResource)
 		analysis.AddManaged(stateRes)
 
 		// Stop there if we are not in deep mode, we do not want to compute diffs
@@ -85,6 +121,11 @@ func (a Analyzer) Analyze(remoteResources, resourcesFromState []*resource.Resour
 			continue
 		}
 
+		// Stop if the resource does not come from a terraform state, other IaC sources do not provide attributes
+		if _, isStateSource := stateRes.Source.(*resource.TerraformStateSource); stateRes.Source != nil && !isStateSource {
+			continue
+		}
+
 		// Stop if the resource is not compatible with deep mode
 		if stateRes.Schema() != nil && !stateRes.Schema().Flags.HasFlag(resource.FlagDeepMode) {
 			continue
@@ -100,6 +141,7 @@ func (a Analyzer) Analyze(remoteResources, resourcesFromState []*resource.Resour
 		changelog := make([]Change, 0, len(delta))
 		for _, change := range delta {
 			if a.filter.IsFieldIgnored(stateRes, change.Path) {
+				a.recordIgnored(&analysis, stateRes, change.Path)
 				continue
 			}
 			c := Change{Change: change}
@@ -121,7 +163,16 @@ func (a Analyzer) Analyze(remoteResources, resourcesFromState []*resource.Resour
 		}
 	}
 
-	if a.hasUnmanagedSecurityGroupRules(filteredRemoteResource) {
+	// Ignored remote resources are only recorded when their state counterpart was not
+	for _, remoteRes := range ignoredRemoteResources {
+		if _, exists := ignoredStateResources[ignoredKey(remoteRes)]; !exists {
+			a.recordIgnored(&analysis, remoteRes, nil)
+		}
+	}
+
+	unmanagedResources := remoteIndex.Remaining()
+
+	if a.hasUnmanagedSecurityGroupRules(unmanagedResources) {
 		a.alerter.SendAlert("", newUnmanagedSecurityGroupRulesAlert())
 	}
 
@@ -130,7 +181,7 @@ func (a Analyzer) Analyze(remoteResources, resourcesFromState []*resource.Resour
 	}
 
 	// Add remaining unmanaged resources
-	analysis.AddUnmanaged(filteredRemoteResource...)
+	analysis.AddUnmanaged(unmanagedResources...)
 
 	// Sort resources by Terraform Id
 	// The purpose is to have a predictable output
@@ -141,20 +192,44 @@ func (a Analyzer) Analyze(remoteResources, resourcesFromState []*resource.Resour
 	return analysis, nil
 }
 
-func findCorrespondingRes(resources []*resource.Resource, res *resource.Resource) (int, *resource.Resource, bool) {
-	for i, r := range resources {
-		if res.Equal(r) {
-			return i, r, true
-		}
+// recordIgnored adds the resource, or the field at the given path, to the ignored resources of the analysis
+// with the rule that filtered it out, when ignored resources are reported and the filter knows about rules
+func (a Analyzer) recordIgnored(analysis *Analysis, res *resource.Resource, path []string) {
+	if !a.options.ReportIgnored {
+		return
+	}
+	ruleFilter, ok := a.filter.(filter.RuleFilter)
+	if !ok {
+		return
 	}
-	return -1, nil, false
+
+	var rule *filter.IgnoreRule
+	if path == nil {
+		rule = ruleFilter.ResourceIgnoreRule(res)
+	} else {
+		rule = ruleFilter.FieldIgnoreRule(res, path)
+	}
+	if rule == nil {
+		return
+	}
+	analysis.AddIgnored(IgnoredResource{Res: res, Path: path, Rule: *rule})
+}
+
+// instanceKeyRegex matches count and for_each keys of module instances, e.g. [0] or ["key"]
+var instanceKeyRegex = regexp.MustCompile(`\[(?:[0-9]+|"(?:[^"\\]|\\.)*")\]`)
+
+// configAddress drops instance keys of an address read from a state, configuration
+// files declare module.a.aws_s3_bucket.foo for every instance of module.a[0]
+func configAddress(address string) string {
+	return instanceKeyRegex.ReplaceAllString(address, "")
 }
 
-func removeResourceByIndex(i int, resources []*resource.Resource) []*resource.Resource {
-	if i == len(resources)-1 {
-		return resources[:len(resources)-1]
+func ignoredKey(res *resource.Resource) string {
+	key := fmt.Sprintf("%s.%s", res.ResourceType(), res.ResourceId())
+	if res.Scope != nil {
+		key = fmt.Sprintf("%s@%s", key, res.Scope)
 	}
-	return append(resources[:i], resources[i+1:]...)
+	return key
 }
 
 // hasUnmanagedSecurityGroupRules returns true if we find at least one unmanaged
@@ -0,0 +1,88 @@
+package analyser
+
+import (
+	"github.com/cloudskiff/driftctl/pkg/resource"
+)
+
+// Comparison holds what changed between two analyses of the same infrastructure
+type Comparison struct {
+	NewUnmanaged      []*resource.Resource
+	ResolvedUnmanaged []*resource.Resource
+	NewDeleted        []*resource.Resource
+	ResolvedDeleted   []*resource.Resource
+	NewDrifted        []Difference
+	ResolvedDrifted   []Difference
+	PreviousCoverage  int
+	Coverage          int
+}
+
+// Compare computes the changes from a previous analysis to a current one.
+// Resources are compared by type and id since schemas are not available in
+// deserialized analyses.
+func Compare(previous, current *Analysis) Comparison {
+	comparison := Comparison{
+		PreviousCoverage: previous.Coverage(),
+		Coverage:         current.Coverage(),
+	}
+
+	comparison.NewUnmanaged, comparison.ResolvedUnmanaged = compareResources(previous.Unmanaged(), current.Unmanaged())
+	comparison.NewDeleted, comparison.ResolvedDeleted = compareResources(previous.Deleted(), current.Deleted())
+	comparison.NewDrifted, comparison.ResolvedDrifted = compareDifferences(previous.Differences(), current.Differences())
+
+	return comparison
+}
+
+func (c Comparison) CoverageDelta() int {
+	return c.Coverage - c.PreviousCoverage
+}
+
+func (c Comparison) HasChanges() bool {
+	return len(c.NewUnmanaged) > 0 || len(c.ResolvedUnmanaged) > 0 ||
+		len(c.NewDeleted) > 0 || len(c.ResolvedDeleted) > 0 ||
+		len(c.NewDrifted) > 0 || len(c.ResolvedDrifted) > 0 ||
+		c.CoverageDelta() != 0
+}
+
+// compareResources returns resources only found in current (added) and
+// resources only found in previous (removed)
+func compareResources(previous, current []*resource.Resource) (added, removed []*resource.Resource) {
+	previousKeys := make(map[resourceKey]struct{}, len(previous))
+	for _, res := range previous {
+		previousKeys[resourceKey{Type: res.ResourceType(), Id: res.ResourceId()}] = struct{}{}
+	}
+	currentKeys := make(map[resourceKey]struct{}, len(current))
+	for _, res := range current {
+		key := resourceKey{Type: res.ResourceType(), Id: res.ResourceId()}
+		currentKeys[key] = struct{}{}
+		if _, exist := previousKeys[key]; !exist {
+			added = append(added, res)
+		}
+	}
+	for _, res := range previous {
+		if _, exist := currentKeys[resourceKey{Type: res.ResourceType(), Id: res.ResourceId()}]; !exist {
+			removed = append(removed, res)
+		}
+	}
+	return resource.Sort(added), resource.Sort(removed)
+}
+
+func compareDifferences(previous, current []Difference) (added, removed []Difference) {
+	previousKeys := make(map[resourceKey]struct{}, len(previous))
+	for _, d := range previous {
+		previousKeys[resourceKey{Type: d.Res.ResourceType(), Id: d.Res.ResourceId()}] = struct{}{}
+	}
+	currentKeys := make(map[resourceKey]struct{}, len(current))
+	for _, d := range current {
+		key := resourceKey{Type: d.Res.ResourceType(), Id: d.Res.ResourceId()}
+		currentKeys[key] = struct{}{}
+		if _, exist := previousKeys[key]; !exist {
+			added = append(added, d)
+		}
+	}
+	for _, d := range previous {
+		if _, exist := currentKeys[resourceKey{Type: d.Res.ResourceType(), Id: d.Res.ResourceId()}]; !exist {
+			removed = append(removed, d)
+		}
+	}
+	return SortDifferences(added), SortDifferences(removed)
+}
@@ -0,0 +1,59 @@
+package analyser
+
+import (
+	"testing"
+
+	"github.com/cloudskiff/driftctl/pkg/resource"
+	"github.com/r3labs/diff/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompare(t *testing.T) {
+	previous := &Analysis{}
+	previous.AddManaged(
+		&resource.Resource{Id: "managed", Type: "aws_s3_bucket"},
+		&resource.Resource{Id: "drifted", Type: "aws_s3_bucket"},
+	)
+	previous.AddUnmanaged(
+		&resource.Resource{Id: "unmanaged", Type: "aws_iam_user"},
+		&resource.Resource{Id: "adopted", Type: "aws_iam_user"},
+	)
+	previous.AddDeleted(&resource.Resource{Id: "restored", Type: "aws_iam_role"})
+	previous.AddDifference(Difference{
+		Res:       &resource.Resource{Id: "drifted", Type: "aws_s3_bucket"},
+		Changelog: Changelog{{Change: diff.Change{Type: diff.UPDATE, Path: []string{"acl"}, From: "private", To: "public"}}},
+	})
+
+	current := &Analysis{}
+	current.AddManaged(
+		&resource.Resource{Id: "managed", Type: "aws_s3_bucket"},
+		&resource.Resource{Id: "drifted", Type: "aws_s3_bucket"},
+		&resource.Resource{Id: "adopted", Type: "aws_iam_user"},
+	)
+	current.AddUnmanaged(
+		&resource.Resource{Id: "unmanaged", Type: "aws_iam_user"},
+		&resource.Resource{Id: "new", Type: "aws_iam_user"},
+	)
+	current.AddDeleted(&resource.Resource{Id: "deleted", Type: "aws_iam_role"})
+	current.AddDifference(Difference{
+		Res:       &resource.Resource{Id: "managed", Type: "aws_s3_bucket"},
+		Changelog: Changelog{{Change: diff.Change{Type: diff.CREATE, Path: []string{"tags", "foo"}, To: "bar"}}},
+	})
+
+	comparison := Compare(previous, current)
+
+	assert.True(t, comparison.HasChanges())
+	assert.Equal(t, []*resource.Resource{{Id: "new", Type: "aws_iam_user"}}, comparison.NewUnmanaged)
+	assert.Equal(t, []*resource.Resource{{Id: "adopted", Type: "aws_iam_user"}}, comparison.ResolvedUnmanaged)
+	assert.Equal(t, []*resource.Resource{{Id: "deleted", Type: "aws_iam_role"}}, comparison.NewDeleted)
+	assert.Equal(t, []*resource.Resource{{Id: "restored", Type: "aws_iam_role"}}, comparison.ResolvedDeleted)
+	assert.Len(t, comparison.NewDrifted, 1)
+	assert.Equal(t, "managed", comparison.NewDrifted[0].Res.ResourceId())
+	assert.Len(t, comparison.ResolvedDrifted, 1)
+	assert.Equal(t, "drifted", comparison.ResolvedDrifted[0].Res.ResourceId())
+	assert.Equal(t, 40, comparison.PreviousCoverage)
+	assert.Equal(t, 50, comparison.Coverage)
+	assert.Equal(t, 10, comparison.CoverageDelta())
+
+	assert.False(t, Compare(current, current).HasChanges())
+}
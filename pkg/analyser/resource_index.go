@@ -0,0 +1,65 @@
+package analyser
+
+import (
+	"github.com/cloudskiff/driftctl/pkg/resource"
+)
+
+type resourceKey struct {
+	Type string
+	Id   string
+}
+
+// resourceIndex indexes remote resources by type and id so that each state
+// resource can be matched in constant time instead of scanning the whole
+// remote resource list.
+// Resources sharing the same type and id are kept in insertion order and are
+// tie-broken using Resource.Equal, which honours the schema DiscriminantFunc.
+type resourceIndex struct {
+	resources []*resource.Resource
+	matched   []bool
+	index     map[resourceKey][]int
+}
+
+func newResourceIndex(resources []*resource.Resource) *resourceIndex {
+	idx := &resourceIndex{
+		resources: resources,
+		matched:   make([]bool, len(resources)),
+		index:     make(map[resourceKey][]int, len(resources)),
+	}
+	for i, res := range resources {
+		key := resourceKey{Type: res.ResourceType(), Id: res.ResourceId()}
+		idx.index[key] = append(idx.index[key], i)
+	}
+	return idx
+}
+
+// Match returns the first remaining resource corresponding to res and marks
+// it as matched, so it will not be returned again nor listed by Remaining.
+func (idx *resourceIndex) Match(res *resource.Resource) (*resource.Resource, bool) {
+	key := resourceKey{Type: res.ResourceType(), Id: res.ResourceId()}
+	candidates := idx.index[key]
+	for n, i := range candidates {
+		if idx.matched[i] || !res.Equal(idx.resources[i]) {
+			continue
+		}
+		idx.matched[i] = true
+		// Shrink the candidate list when matching its head so that lookups on
+		// keys shared by many resources do not keep skipping matched entries
+		if n == 0 {
+			idx.index[key] = candidates[1:]
+		}
+		return idx.resources[i], true
+	}
+	return nil, false
+}
+
+// Remaining returns resources that were never matched, in their original order
+func (idx *resourceIndex) Remaining() []*resource.Resource {
+	remaining := make([]*resource.Resource, 0, len(idx.resources))
+	for i, res := range idx.resources {
+		if !idx.matched[i] {
+			remaining = append(remaining, res)
+		}
+	}
+	return remaining
+}
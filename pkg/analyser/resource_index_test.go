@@ -0,0 +1,95 @@
+package analyser
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/cloudskiff/driftctl/pkg/alerter"
+	"github.com/cloudskiff/driftctl/pkg/resource"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResourceIndex(t *testing.T) {
+	discriminant := func(self, res *resource.Resource) bool {
+		return (*self.Attributes())["region"] == (*res.Attributes())["region"]
+	}
+	schema := &resource.Schema{DiscriminantFunc: discriminant}
+
+	remote := []*resource.Resource{
+		{Id: "foo", Type: "type1", Attrs: &resource.Attributes{"region": "us-east-1"}, Sch: schema},
+		{Id: "foo", Type: "type1", Attrs: &resource.Attributes{"region": "eu-west-3"}, Sch: schema},
+		{Id: "foo", Type: "type2", Attrs: &resource.Attributes{}},
+		{Id: "bar", Type: "type2", Attrs: &resource.Attributes{}},
+		{Id: "bar", Type: "type2", Attrs: &resource.Attributes{}},
+	}
+	idx := newResourceIndex(remote)
+
+	res, found := idx.Match(&resource.Resource{Id: "foo", Type: "type1", Attrs: &resource.Attributes{"region": "eu-west-3"}, Sch: schema})
+	assert.True(t, found)
+	assert.Same(t, remote[1], res)
+
+	_, found = idx.Match(&resource.Resource{Id: "foo", Type: "type1", Attrs: &resource.Attributes{"region": "eu-west-3"}, Sch: schema})
+	assert.False(t, found)
+
+	_, found = idx.Match(&resource.Resource{Id: "foo", Type: "type3", Attrs: &resource.Attributes{}})
+	assert.False(t, found)
+
+	res, found = idx.Match(&resource.Resource{Id: "bar", Type: "type2", Attrs: &resource.Attributes{}})
+	assert.True(t, found)
+	assert.Same(t, remote[3], res)
+
+	res, found = idx.Match(&resource.Resource{Id: "bar", Type: "type2", Attrs: &resource.Attributes{}})
+	assert.True(t, found)
+	assert.Same(t, remote[4], res)
+
+	_, found = idx.Match(&resource.Resource{Id: "bar", Type: "type2", Attrs: &resource.Attributes{}})
+	assert.False(t, found)
+
+	assert.Equal(t, []*resource.Resource{remote[0], remote[2]}, idx.Remaining())
+}
+
+type noopFilter struct{}
+
+func (noopFilter) IsTypeIgnored(resource.ResourceType) bool         { return false }
+func (noopFilter) IsResourceIgnored(*resource.Resource) bool        { return false }
+func (noopFilter) IsFieldIgnored(*resource.Resource, []string) bool { return false }
+
+func generateBenchmarkResources(count int) (remote, state []*resource.Resource) {
+	remote = make([]*resource.Resource, 0, count)
+	state = make([]*resource.Resource, 0, count)
+	types := []string{"aws_route53_record", "aws_iam_role_policy_attachment", "aws_s3_bucket"}
+	for i := 0; i < count; i++ {
+		ty := types[i%len(types)]
+		id := fmt.Sprintf("resource-%d", i)
+		remote = append(remote, &resource.Resource{Id: id, Type: ty, Attrs: &resource.Attributes{}})
+		// Keep one resource out of ten unmanaged and one out of ten deleted
+		switch i % 10 {
+		case 0:
+			continue
+		case 1:
+			id = fmt.Sprintf("deleted-%d", i)
+		}
+		state = append(state, &resource.Resource{Id: id, Type: ty, Attrs: &resource.Attributes{}})
+	}
+	// Iterate state in reverse order to avoid matching resources in the same order as the remote ones
+	for i, j := 0, len(state)-1; i < j; i, j = i+1, j-1 {
+		state[i], state[j] = state[j], state[i]
+	}
+	return remote, state
+}
+
+// BenchmarkAnalyze runs the analysis against growing sets of resources,
+// ns/op should grow linearly with the number of resources
+func BenchmarkAnalyze(b *testing.B) {
+	for _, count := range []int{1000, 10000, 80000} {
+		remote, state := generateBenchmarkResources(count)
+		b.Run(fmt.Sprintf("%d", count), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				analyzer := NewAnalyzer(alerter.NewAlerter(), AnalyzerOptions{}, noopFilter{})
+				if _, err := analyzer.Analyze(remote, state); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+
+	"github.com/cloudskiff/driftctl/pkg/analyser"
+)
+
+// readAnalysis parses a JSON scan result from the given path, "-" reads from stdin
+func readAnalysis(path string) (*analyser.Analysis, error) {
+	driftFile := os.Stdin
+	if path != "-" {
+		var err error
+		driftFile, err = os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer driftFile.Close()
+	}
+
+	input, err := io.ReadAll(driftFile)
+	if err != nil {
+		return nil, err
+	}
+
+	analysis := &analyser.Analysis{}
+	err = json.Unmarshal(input, analysis)
+	if err != nil {
+		return nil, err
+	}
+
+	return analysis, nil
+}
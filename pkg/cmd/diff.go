@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/cloudskiff/driftctl/pkg/analyser"
+	"github.com/cloudskiff/driftctl/pkg/resource"
+	"github.com/fatih/color"
+	"github.com/pkg/errors"
+	"github.com/r3labs/diff/v2"
+	"github.com/spf13/cobra"
+)
+
+func NewDiffCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "diff <previous> <current>",
+		Short: "Compare two scan results",
+		Long:  "This command will display what changed between two JSON scan results\n\nExample: driftctl diff last_week.json today.json",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			previous, err := readAnalysis(args[0])
+			if err != nil {
+				return errors.Wrapf(err, "unable to read previous analysis")
+			}
+			current, err := readAnalysis(args[1])
+			if err != nil {
+				return errors.Wrapf(err, "unable to read current analysis")
+			}
+
+			printComparison(cmd.OutOrStdout(), analyser.Compare(previous, current))
+
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+func printComparison(out io.Writer, comparison analyser.Comparison) {
+	if !comparison.HasChanges() {
+		fmt.Fprintln(out, "No changes between the two analyses")
+		return
+	}
+
+	printResources(out, "New resources not covered by IaC:", comparison.NewUnmanaged)
+	printResources(out, "Resources no longer unmanaged:", comparison.ResolvedUnmanaged)
+	printResources(out, "New missing resources:", comparison.NewDeleted)
+	printResources(out, "Resources no longer missing:", comparison.ResolvedDeleted)
+
+	if len(comparison.NewDrifted) > 0 {
+		fmt.Fprintln(out, "New changed resources:")
+		for _, difference := range comparison.NewDrifted {
+			fmt.Fprintf(out, "  - %s (%s):\n", difference.Res.ResourceId(), difference.Res.ResourceType())
+			for _, change := range difference.Changelog {
+				path := strings.Join(change.Path, ".")
+				switch change.Type {
+				case diff.CREATE:
+					fmt.Fprintf(out, "      %s %s: %v\n", color.GreenString("+"), path, change.To)
+				case diff.DELETE:
+					fmt.Fprintf(out, "      %s %s: %v\n", color.RedString("-"), path, change.From)
+				default:
+					fmt.Fprintf(out, "      %s %s: %v => %v\n", color.YellowString("~"), path, change.From, change.To)
+				}
+			}
+		}
+	}
+
+	if len(comparison.ResolvedDrifted) > 0 {
+		fmt.Fprintln(out, "Resources no longer changed:")
+		for _, difference := range comparison.ResolvedDrifted {
+			fmt.Fprintf(out, "  - %s (%s)\n", difference.Res.ResourceId(), difference.Res.ResourceType())
+		}
+	}
+
+	delta := comparison.CoverageDelta()
+	coverage := fmt.Sprintf("Coverage changed from %d%% to %d%% (%+d%%)", comparison.PreviousCoverage, comparison.Coverage, delta)
+	switch {
+	case delta > 0:
+		coverage = color.GreenString(coverage)
+	case delta < 0:
+		coverage = color.RedString(coverage)
+	default:
+		coverage = fmt.Sprintf("Coverage unchanged at %d%%", comparison.Coverage)
+	}
+	fmt.Fprintln(out, coverage)
+}
+
+func printResources(out io.Writer, title string, resources []*resource.Resource) {
+	if len(resources) == 0 {
+		return
+	}
+	fmt.Fprintln(out, title)
+	for _, res := range resources {
+		fmt.Fprintf(out, "  - %s (%s)\n", res.ResourceId(), res.ResourceType())
+	}
+}
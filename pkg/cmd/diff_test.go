@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"os"
+	"testing"
+
+	"github.com/cloudskiff/driftctl/test"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffCmd(t *testing.T) {
+	color.NoColor = true
+
+	cases := []struct {
+		name     string
+		args     []string
+		expected string
+		err      string
+	}{
+		{
+			name:     "test diff between two analyses",
+			args:     []string{"./testdata/diff/previous.json", "./testdata/diff/current.json"},
+			expected: "./testdata/diff/output.txt",
+		},
+		{
+			name: "test diff with the same analysis",
+			args: []string{"./testdata/diff/current.json", "./testdata/diff/current.json"},
+		},
+		{
+			name: "test error when previous analysis does not exist",
+			args: []string{"doesnotexist", "./testdata/diff/current.json"},
+			err:  "unable to read previous analysis: open doesnotexist: no such file or directory",
+		},
+		{
+			name: "test error on invalid current analysis",
+			args: []string{"./testdata/diff/previous.json", "./testdata/input_stdin_invalid.json"},
+			err:  "unable to read current analysis: invalid character 'i' looking for beginning of value",
+		},
+		{
+			name: "test error on missing argument",
+			args: []string{"./testdata/diff/previous.json"},
+			err:  "accepts 2 arg(s), received 1",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			rootCmd := &cobra.Command{Use: "root"}
+			rootCmd.AddCommand(NewDiffCmd())
+
+			output, err := test.Execute(rootCmd, append([]string{"diff"}, c.args...)...)
+			if c.err != "" {
+				assert.EqualError(t, err, c.err)
+				return
+			}
+			assert.NoError(t, err)
+
+			expected := "No changes between the two analyses\n"
+			if c.expected != "" {
+				content, err := os.ReadFile(c.expected)
+				assert.NoError(t, err)
+				expected = string(content)
+			}
+			assert.Equal(t, expected, output)
+		})
+	}
+}
@@ -70,6 +70,11 @@ func NewDriftctlCmd(build build.BuildInterface) *DriftctlCmd {
 
 	cmd.AddCommand(NewScanCmd(&pkg.ScanOptions{}))
 	cmd.AddCommand(NewGenDriftIgnoreCmd())
+	cmd.AddCommand(NewDriftIgnoreCmd())
+	cmd.AddCommand(NewDiffCmd())
+	cmd.AddCommand(NewGenImportCmd())
+	cmd.AddCommand(NewGenHclCmd())
+	cmd.AddCommand(NewTriageCmd())
 
 	return cmd
 }
@@ -107,6 +112,11 @@ func handleReporting(cmd *cobra.Command) error {
 	return nil
 }
 
+// Values of those flags are not logged when read from env variables
+var secretFlags = map[string]struct{}{
+	"tofu-passphrase": {},
+}
+
 // Iterate over command flags
 // If the command flag is not manually set (f.Changed) we override its value
 // from the according env value
@@ -131,6 +141,9 @@ func bindEnvToFlags(cmd *cobra.Command) error {
 			if err != nil {
 				return
 			}
+			if _, secret := secretFlags[f.Name]; secret {
+				envVal = "<redacted>"
+			}
 			logrus.WithFields(logrus.Fields{
 				"env":   envKey,
 				"flag":  f.Name,
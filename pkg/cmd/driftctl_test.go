@@ -9,6 +9,8 @@ import (
 	"github.com/cloudskiff/driftctl/pkg/config"
 	"github.com/cloudskiff/driftctl/test"
 	"github.com/cloudskiff/driftctl/test/mocks"
+	"github.com/sirupsen/logrus"
+	logrustest "github.com/sirupsen/logrus/hooks/test"
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
 )
@@ -82,7 +84,7 @@ func TestDriftctlCmd_Scan(t *testing.T) {
 			env: map[string]string{
 				"DCTL_FROM": "test",
 			},
-			err: fmt.Errorf("Unable to parse from flag 'test': \nAccepted schemes are: tfstate://,tfstate+s3://,tfstate+http://,tfstate+https://,tfstate+tfcloud://"),
+			err: fmt.Errorf("Unable to parse from flag 'test': \nAccepted schemes are: tfstate://,tfstate+s3://,tfstate+gs://,tfstate+azurerm://,tfstate+http://,tfstate+https://,tfstate+tfcloud://,tfstate+consul://,tfstate+pg://,tfstate+kubernetes://,tfbackend://,tfconfig://,tfplan://,tfplan+s3://,tfplan+gs://,tfplan+azurerm://,tfplan+http://,tfplan+https://,pulumi://,pulumi+s3://,pulumi+gs://,pulumi+azurerm://,pulumi+http://,pulumi+https://,cloudformation://,cloudformation+s3://,cloudformation+gs://,cloudformation+azurerm://,cloudformation+http://,cloudformation+https://"),
 		},
 		{
 			env: map[string]string{
@@ -94,7 +96,7 @@ func TestDriftctlCmd_Scan(t *testing.T) {
 			env: map[string]string{
 				"DCTL_OUTPUT": "test",
 			},
-			err: fmt.Errorf("Unable to parse output flag 'test': \nAccepted formats are: console://,html://PATH/TO/FILE.html,json://PATH/TO/FILE.json,plan://PATH/TO/FILE.json"),
+			err: fmt.Errorf("Unable to parse output flag 'test': \nAccepted formats are: console://,html://PATH/TO/FILE.html,json://PATH/TO/FILE.json,junit://PATH/TO/FILE.xml,markdown://PATH/TO/FILE.md,plan://PATH/TO/FILE.json,sarif://PATH/TO/FILE.sarif"),
 		},
 		{
 			env: map[string]string{
@@ -140,6 +142,38 @@ func TestDriftctlCmd_Scan(t *testing.T) {
 	}
 }
 
+func TestDriftctlCmd_Scan_TofuPassphraseFromEnv(t *testing.T) {
+	_ = os.Setenv("DCTL_TOFU_PASSPHRASE", "correct horse battery staple")
+	defer os.Unsetenv("DCTL_TOFU_PASSPHRASE")
+
+	hook := logrustest.NewGlobal()
+	defer logrus.StandardLogger().ReplaceHooks(make(logrus.LevelHooks))
+	level := logrus.GetLevel()
+	logrus.SetLevel(logrus.DebugLevel)
+	defer logrus.SetLevel(level)
+
+	config.Init()
+	cmd := NewDriftctlCmd(mocks.MockBuild{})
+	scanCmd, _, _ := cmd.Find([]string{"scan"})
+	scanCmd.RunE = func(_ *cobra.Command, args []string) error { return nil }
+	_, err := test.Execute(&cmd.Command, "scan")
+	assert.NoError(t, err)
+
+	passphrase, err := scanCmd.Flags().GetString("tofu-passphrase")
+	assert.NoError(t, err)
+	assert.Equal(t, "correct horse battery staple", passphrase)
+
+	// The passphrase must not leak in debug logs
+	bound := false
+	for _, entry := range hook.AllEntries() {
+		if entry.Data["flag"] == "tofu-passphrase" {
+			bound = true
+			assert.Equal(t, "<redacted>", entry.Data["value"])
+		}
+	}
+	assert.True(t, bound)
+}
+
 func TestDriftctlCmd_Invalid(t *testing.T) {
 	cmd := NewDriftctlCmd(mocks.MockBuild{})
 
@@ -0,0 +1,208 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/cloudskiff/driftctl/pkg/analyser"
+	"github.com/cloudskiff/driftctl/pkg/filter"
+)
+
+type DriftIgnoreAuditOptions struct {
+	DriftignorePaths []string
+	InputPath        string
+}
+
+func NewDriftIgnoreCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "driftignore",
+		Short: "Manage driftignore files",
+		Args:  cobra.NoArgs,
+	}
+
+	cmd.AddCommand(NewDriftIgnoreAuditCmd())
+
+	return cmd
+}
+
+func NewDriftIgnoreAuditCmd() *cobra.Command {
+	opts := &DriftIgnoreAuditOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "audit",
+		Short: "List expired, never matched and stale driftignore entries",
+		Long: "This command will list the entries of a driftignore file worth cleaning up\n\n" +
+			"Entries may be annotated after a #, e.g. aws_s3_bucket.logs # expires=2022-12-31 owner=alice ticket=OPS-123\n" +
+			"Expired entries no longer ignore resources. Never matched entries and stale entries, only matching resources in sync with IaC, " +
+			"are found from the result of a scan made without driftignore file. " +
+			"Entries with a condition, e.g. aws_iam_role.* %where Attr.path=='/aws-service-role/', are matched on their pattern only " +
+			"as scan results hold no attributes\n\n" +
+			"Example: driftctl scan --driftignore /dev/null -o json://scan.json && driftctl driftignore audit -i scan.json",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			paths, err := driftIgnorePaths(cmd)
+			if err != nil {
+				return err
+			}
+			opts.DriftignorePaths = paths
+			return auditDriftIgnore(cmd.OutOrStdout(), opts, time.Now())
+		},
+	}
+
+	fl := cmd.Flags()
+	addDriftIgnoreFlags(fl)
+	fl.StringVarP(&opts.InputPath, "input", "i", "", "Result of a scan made without driftignore file, in JSON, to find never matched and stale entries. Use - for stdin.")
+
+	return cmd
+}
+
+// addDriftIgnoreFlags adds the flags selecting the driftignore files to merge
+func addDriftIgnoreFlags(fl *pflag.FlagSet) {
+	fl.StringSlice(
+		"driftignore",
+		[]string{},
+		"Paths to driftignore files, entries of a file take precedence over the ones of previous files.\n"+
+			"Defaults to the .driftignore files of the current directory and of its parents up to the root of the git repository, "+
+			"inner files taking precedence\n"+
+			"Entries may only ignore resources verifying a condition, e.g. aws_iam_role.* %where Attr.path=='/aws-service-role/'. "+
+			"Without --deep, remote resources only hold the few attributes returned when listing them, "+
+			"so conditions on attributes may not ignore unmanaged resources\n",
+	)
+	fl.String(
+		"driftignore-global",
+		"",
+		"Path to a driftignore file shared across projects, its entries have the lowest precedence\n",
+	)
+}
+
+// warnAttributeConditions warns about the entries with a condition on attributes, they may not ignore
+// remote resources outside of deep mode as their attributes are not read
+func warnAttributeConditions(driftIgnore *filter.DriftIgnore) {
+	for _, entry := range driftIgnore.Entries() {
+		if !entry.HasAttributeCondition() {
+			continue
+		}
+		logrus.WithFields(logrus.Fields{
+			"file":  entry.File,
+			"line":  entry.Line,
+			"entry": entry.Pattern,
+		}).Warn("Driftignore entry condition on attributes is evaluated without --deep, it may not ignore unmanaged resources")
+	}
+}
+
+// driftIgnorePaths returns the driftignore files given with --driftignore or found from the current directory,
+// preceded by the global driftignore file
+func driftIgnorePaths(cmd *cobra.Command) ([]string, error) {
+	paths, _ := cmd.Flags().GetStringSlice("driftignore")
+	if !cmd.Flags().Changed("driftignore") {
+		found, err := filter.FindDriftIgnoreFiles(".")
+		if err != nil {
+			return nil, err
+		}
+		paths = found
+	}
+	if global, _ := cmd.Flags().GetString("driftignore-global"); global != "" {
+		paths = append([]string{global}, paths...)
+	}
+	return paths, nil
+}
+
+func auditDriftIgnore(out io.Writer, opts *DriftIgnoreAuditOptions, now time.Time) error {
+	var entries []*filter.DriftIgnoreEntry
+	for _, path := range opts.DriftignorePaths {
+		if _, err := os.Stat(path); err != nil {
+			return errors.Wrap(err, "unable to read driftignore file")
+		}
+		fileEntries, err := filter.ReadDriftIgnoreEntries(path)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, fileEntries...)
+	}
+
+	var resources []filter.AuditedResource
+	if opts.InputPath != "" {
+		analysis, err := readAnalysis(opts.InputPath)
+		if err != nil {
+			return err
+		}
+		resources = auditedResources(analysis)
+	}
+
+	audit := filter.AuditDriftIgnore(entries, resources, now)
+	if audit.IsEmpty() {
+		fmt.Fprintf(out, "Found no entry to clean up in %s\n", strings.Join(opts.DriftignorePaths, ", "))
+	}
+	// Entries are located by file as well once they do not all come from the same file
+	showFile := false
+	for _, entry := range entries {
+		showFile = showFile || entry.File != entries[0].File
+	}
+	write := func(title string, entries []*filter.DriftIgnoreEntry, details func(*filter.DriftIgnoreEntry) string) {
+		writeAuditedEntries(out, title, entries, showFile, details)
+	}
+	write("Expired entries", audit.Expired, func(entry *filter.DriftIgnoreEntry) string {
+		return fmt.Sprintf("expired on %s", entry.Expires.Format(filter.ExpiryDateFormat))
+	})
+	write("Never matched entries", audit.NeverMatched, nil)
+	write("Stale entries only matching resources in sync", audit.Stale, nil)
+	write("Invalid entries", audit.Invalid, func(entry *filter.DriftIgnoreEntry) string {
+		return entry.Err.Error()
+	})
+	if opts.InputPath == "" {
+		fmt.Fprintln(out, "Give the result of a scan made without driftignore file with --input to find never matched and stale entries")
+	}
+	return nil
+}
+
+func writeAuditedEntries(out io.Writer, title string, entries []*filter.DriftIgnoreEntry, showFile bool, details func(*filter.DriftIgnoreEntry) string) {
+	if len(entries) == 0 {
+		return
+	}
+	fmt.Fprintf(out, "%s (%d):\n", title, len(entries))
+	for _, entry := range entries {
+		line := fmt.Sprintf("  line %d: %s", entry.Line, entry.Pattern)
+		if showFile {
+			line = fmt.Sprintf("  %s line %d: %s", entry.File, entry.Line, entry.Pattern)
+		}
+		if details != nil {
+			line = fmt.Sprintf("%s %s", line, details(entry))
+		}
+		if annotations := entry.Annotations(); annotations != "" {
+			line = fmt.Sprintf("%s (%s)", line, annotations)
+		}
+		fmt.Fprintln(out, line)
+	}
+}
+
+// auditedResources lists every resource of a scan, resources are in sync unless they are not managed,
+// missing or changed
+func auditedResources(analysis *analyser.Analysis) []filter.AuditedResource {
+	resources := make([]filter.AuditedResource, 0, analysis.Summary().TotalResources)
+	changes := make(map[string][][]string)
+	for _, difference := range analysis.Differences() {
+		key := fmt.Sprintf("%s.%s", difference.Res.ResourceType(), difference.Res.ResourceId())
+		for _, change := range difference.Changelog {
+			changes[key] = append(changes[key], change.Path)
+		}
+	}
+	for _, res := range analysis.Managed() {
+		fields := changes[fmt.Sprintf("%s.%s", res.ResourceType(), res.ResourceId())]
+		resources = append(resources, filter.AuditedResource{Resource: res, Drifted: len(fields) > 0, ChangedFields: fields})
+	}
+	for _, res := range analysis.Unmanaged() {
+		resources = append(resources, filter.AuditedResource{Resource: res, Drifted: true})
+	}
+	for _, res := range analysis.Deleted() {
+		resources = append(resources, filter.AuditedResource{Resource: res, Drifted: true})
+	}
+	return resources
+}
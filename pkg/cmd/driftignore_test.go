@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	logrustest "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudskiff/driftctl/pkg/alerter"
+	"github.com/cloudskiff/driftctl/pkg/filter"
+)
+
+func TestDriftIgnoreAudit(t *testing.T) {
+	cases := []struct {
+		name     string
+		opts     DriftIgnoreAuditOptions
+		expected string
+		err      string
+	}{
+		{
+			name: "without scan result",
+			opts: DriftIgnoreAuditOptions{DriftignorePaths: []string{"testdata/driftignore_audit/.driftignore"}},
+			expected: "Expired entries (1):\n" +
+				"  line 3: aws_iam_user.former_employee expired on 2021-06-30 (owner: bob, ticket: OPS-12)\n" +
+				"Invalid entries (1):\n" +
+				"  line 6: aws_iam_access_key.* invalid expiry date soon, expected a date like 2006-01-02\n" +
+				"Give the result of a scan made without driftignore file with --input to find never matched and stale entries\n",
+		},
+		{
+			name: "with scan result",
+			opts: DriftIgnoreAuditOptions{DriftignorePaths: []string{"testdata/driftignore_audit/.driftignore"}, InputPath: "testdata/driftignore_audit/scan.json"},
+			expected: "Expired entries (1):\n" +
+				"  line 3: aws_iam_user.former_employee expired on 2021-06-30 (owner: bob, ticket: OPS-12)\n" +
+				"Never matched entries (1):\n" +
+				"  line 4: aws_iam_role.deleted_role (ticket: OPS-34)\n" +
+				"Stale entries only matching resources in sync (1):\n" +
+				"  line 5: aws_s3_bucket.test-20210416154114486700000001\n" +
+				"Invalid entries (1):\n" +
+				"  line 6: aws_iam_access_key.* invalid expiry date soon, expected a date like 2006-01-02\n",
+		},
+		{
+			name: "with several driftignore files",
+			opts: DriftIgnoreAuditOptions{DriftignorePaths: []string{"testdata/driftignore_audit/.driftignore", "../filter/testdata/drift_ignore_files/.driftignore"}, InputPath: "testdata/driftignore_audit/scan.json"},
+			expected: "Expired entries (1):\n" +
+				"  testdata/driftignore_audit/.driftignore line 3: aws_iam_user.former_employee expired on 2021-06-30 (owner: bob, ticket: OPS-12)\n" +
+				"Never matched entries (3):\n" +
+				"  testdata/driftignore_audit/.driftignore line 4: aws_iam_role.deleted_role (ticket: OPS-34)\n" +
+				"  ../filter/testdata/drift_ignore_files/.driftignore line 1: !aws_s3_bucket.product-data\n" +
+				"  ../filter/testdata/drift_ignore_files/.driftignore line 2: aws_sqs_queue.*\n" +
+				"Stale entries only matching resources in sync (1):\n" +
+				"  testdata/driftignore_audit/.driftignore line 5: aws_s3_bucket.test-20210416154114486700000001\n" +
+				"Invalid entries (1):\n" +
+				"  testdata/driftignore_audit/.driftignore line 6: aws_iam_access_key.* invalid expiry date soon, expected a date like 2006-01-02\n",
+		},
+		{
+			name:     "with empty driftignore",
+			opts:     DriftIgnoreAuditOptions{DriftignorePaths: []string{"../filter/testdata/drift_ignore_empty/.driftignore"}, InputPath: "testdata/input_stdin_valid.json"},
+			expected: "Found no entry to clean up in ../filter/testdata/drift_ignore_empty/.driftignore\n",
+		},
+		{
+			name: "with missing driftignore",
+			opts: DriftIgnoreAuditOptions{DriftignorePaths: []string{"testdata/driftignore_audit/missing"}},
+			err:  "unable to read driftignore file: stat testdata/driftignore_audit/missing: no such file or directory",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			err := auditDriftIgnore(out, &c.opts, time.Date(2022, 3, 15, 0, 0, 0, 0, time.UTC))
+			if c.err != "" {
+				assert.EqualError(t, err, c.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, c.expected, out.String())
+		})
+	}
+}
+
+func TestWarnAttributeConditions(t *testing.T) {
+	hook := logrustest.NewGlobal()
+	defer logrus.StandardLogger().ReplaceHooks(make(logrus.LevelHooks))
+
+	path := "testdata/driftignore_conditions/.driftignore"
+	warnAttributeConditions(filter.NewDriftIgnore(path, alerter.NewAlerter()))
+
+	entries := hook.AllEntries()
+	if assert.Len(t, entries, 1) {
+		assert.Equal(t, logrus.WarnLevel, entries[0].Level)
+		assert.Equal(t, "Driftignore entry condition on attributes is evaluated without --deep, it may not ignore unmanaged resources", entries[0].Message)
+		assert.Equal(t, logrus.Fields{"file": path, "line": 2, "entry": "aws_iam_role.* %where Attr.path=='/aws-service-role/'"}, entries[0].Data)
+	}
+}
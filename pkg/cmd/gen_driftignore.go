@@ -1,9 +1,7 @@
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
-	"io"
 	"os"
 	"time"
 
@@ -54,23 +52,7 @@ func NewGenDriftIgnoreCmd() *cobra.Command {
 }
 
 func genDriftIgnore(opts *analyser.GenDriftIgnoreOptions) (int, string, error) {
-	driftFile := os.Stdin
-	if opts.InputPath != "-" {
-		var err error
-		driftFile, err = os.Open(opts.InputPath)
-		if err != nil {
-			return 0, "", err
-		}
-		defer driftFile.Close()
-	}
-
-	input, err := io.ReadAll(driftFile)
-	if err != nil {
-		return 0, "", err
-	}
-
-	analysis := &analyser.Analysis{}
-	err = json.Unmarshal(input, analysis)
+	analysis, err := readAnalysis(opts.InputPath)
 	if err != nil {
 		return 0, "", err
 	}
@@ -0,0 +1,301 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/mitchellh/go-homedir"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/cloudskiff/driftctl/pkg/analyser"
+	"github.com/cloudskiff/driftctl/pkg/remote"
+	"github.com/cloudskiff/driftctl/pkg/resource"
+	"github.com/cloudskiff/driftctl/pkg/terraform"
+)
+
+type GenHclOptions struct {
+	InputPath      string
+	OutputPath     string
+	ConfigDir      string
+	ProviderMirror []string
+}
+
+func NewGenHclCmd() *cobra.Command {
+	opts := &GenHclOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "gen-hcl",
+		Short: "Generate terraform resource blocks for unmanaged resources",
+		Long: "This command will generate terraform resource blocks for the resources not covered by IaC found in your scan result\n\n" +
+			"Resource attributes are only available in scan results produced with the --deep flag, " +
+			"computed attributes are omitted and generated blocks should be reviewed before being applied\n\n" +
+			"Example: driftctl scan --deep -o json://stdout | driftctl gen-hcl -o unmanaged.tf",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			analysis, err := readAnalysis(opts.InputPath)
+			if err != nil {
+				return err
+			}
+
+			if analysis.ProviderName == "" || analysis.ProviderVersion == "" {
+				return errors.New("unable to find the provider used for the scan in the analysis")
+			}
+			installation, err := terraform.GetProviderInstallation(opts.ProviderMirror)
+			if err != nil {
+				return err
+			}
+			repo := resource.NewSchemaRepository()
+			for _, provider := range scannedProviders(analysis) {
+				if err := remote.InitSchemaRepository(provider.Name, provider.Version, opts.ConfigDir, installation, repo); err != nil {
+					return errors.Wrap(err, "unable to load provider schema")
+				}
+			}
+
+			out := cmd.OutOrStdout()
+			if opts.OutputPath != "-" {
+				f, err := os.OpenFile(opts.OutputPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+				if err != nil {
+					return errors.Errorf("error opening output file: %s", err)
+				}
+				defer f.Close()
+				out = f
+			}
+
+			n, err := genHcl(out, repo, analysis.Unmanaged())
+			if err != nil {
+				return err
+			}
+			if opts.OutputPath != "-" {
+				fmt.Fprintf(os.Stderr, "Generated configuration for %d resource(s) in %s\n", n, opts.OutputPath)
+			}
+
+			return nil
+		},
+	}
+
+	fl := cmd.Flags()
+	fl.StringVarP(&opts.InputPath, "input", "i", "-", "Input where the JSON should be parsed from. Defaults to stdin.")
+	fl.StringVarP(&opts.OutputPath, "output", "o", "-", "Output file path to write the generated configuration to. Defaults to stdout.")
+
+	configDir, err := homedir.Dir()
+	if err != nil {
+		configDir = os.TempDir()
+	}
+	fl.StringVar(&opts.ConfigDir, "config-dir", configDir, "Directory path that driftctl uses for configuration.\n")
+	fl.StringSliceVar(&opts.ProviderMirror, "tf-provider-mirror", []string{}, "Install terraform providers from filesystem directories or network mirror URLs.\n")
+
+	return cmd
+}
+
+// genHcl writes a resource block per resource and returns the number of generated blocks,
+// resources without attributes or schema are written as comments
+func genHcl(out io.Writer, repo resource.SchemaRepositoryInterface, resources []*resource.Resource) (int, error) {
+	names := newTerraformNames()
+	count := 0
+
+	for _, res := range resource.Sort(resources) {
+		schema, exist := repo.GetSchema(res.ResourceType())
+		var reason string
+		switch {
+		case !exist:
+			reason = "no schema found for this resource type"
+		case res.Attributes() == nil || len(*res.Attributes()) == 0:
+			reason = "no attributes found, make sure the scan was run with --deep"
+		}
+		if reason != "" {
+			if _, err := fmt.Fprintf(out, "# Unable to generate configuration for %s (%s): %s\n\n", res.ResourceId(), res.ResourceType(), reason); err != nil {
+				return count, err
+			}
+			continue
+		}
+
+		f := hclwrite.NewEmptyFile()
+		block := f.Body().AppendNewBlock("resource", []string{res.ResourceType(), names.Name(res)})
+		if err := writeHclBody(block.Body(), schema, nil, *res.Attributes()); err != nil {
+			return count, errors.Wrapf(err, "unable to generate configuration for %s (%s)", res.ResourceId(), res.ResourceType())
+		}
+		f.Body().AppendNewline()
+
+		if _, err := out.Write(hclwrite.Format(f.Bytes())); err != nil {
+			return count, err
+		}
+		count++
+	}
+
+	return count, nil
+}
+
+// writeHclBody writes attributes first then nested blocks, attributes are sorted by name.
+// Attributes unknown to the schema, read only ones and empty values are skipped.
+func writeHclBody(body *hclwrite.Body, schema *resource.Schema, path []string, attrs map[string]interface{}) error {
+	keys := make([]string, 0, len(attrs))
+	for key := range attrs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var blocks []string
+	for _, key := range keys {
+		value := attrs[key]
+		fieldPath := append(append([]string{}, path...), key)
+		if isEmptyHclValue(value) || (len(path) == 0 && key == "id") {
+			continue
+		}
+
+		attribute, exist := schema.Attributes[strings.Join(fieldPath, ".")]
+		if !exist {
+			if isNestedBlock(schema, fieldPath) {
+				blocks = append(blocks, key)
+			}
+			continue
+		}
+		if schema.IsComputedField(fieldPath) && !attribute.ConfigSchema.Optional {
+			continue
+		}
+
+		if schema.IsJsonStringField(fieldPath) {
+			tokens, err := jsonencodeTokens(value)
+			if err != nil {
+				return errors.Wrapf(err, "invalid json in %s", strings.Join(fieldPath, "."))
+			}
+			body.SetAttributeRaw(key, tokens)
+			continue
+		}
+		body.SetAttributeValue(key, toCtyValue(value))
+	}
+
+	for _, key := range blocks {
+		fieldPath := append(append([]string{}, path...), key)
+		var elements []interface{}
+		switch value := attrs[key].(type) {
+		case []interface{}:
+			elements = value
+		case map[string]interface{}:
+			elements = []interface{}{value}
+		}
+		for _, element := range elements {
+			nested, ok := element.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			block := body.AppendNewBlock(key, nil)
+			if err := writeHclBody(block.Body(), schema, fieldPath, nested); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// isNestedBlock returns true when the schema holds attributes under the given path
+func isNestedBlock(schema *resource.Schema, path []string) bool {
+	prefix := strings.Join(path, ".") + "."
+	for name := range schema.Attributes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func isEmptyHclValue(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case []interface{}:
+		return len(v) == 0
+	case map[string]interface{}:
+		return len(v) == 0
+	}
+	return false
+}
+
+// jsonencodeTokens renders a JSON string as a jsonencode() call to keep the generated code readable
+func jsonencodeTokens(value interface{}) (hclwrite.Tokens, error) {
+	str, ok := value.(string)
+	if !ok {
+		return hclwrite.TokensForValue(toCtyValue(value)), nil
+	}
+	var decoded interface{}
+	if err := json.Unmarshal([]byte(str), &decoded); err != nil {
+		return nil, err
+	}
+	tokens := hclwrite.Tokens{
+		{Type: hclsyntax.TokenIdent, Bytes: []byte("jsonencode")},
+		{Type: hclsyntax.TokenOParen, Bytes: []byte("(")},
+	}
+	tokens = append(tokens, hclwrite.TokensForValue(toCtyValue(decoded))...)
+	tokens = append(tokens, &hclwrite.Token{Type: hclsyntax.TokenCParen, Bytes: []byte(")")})
+	return tokens, nil
+}
+
+// toCtyValue converts attributes as decoded from JSON to cty values
+func toCtyValue(value interface{}) cty.Value {
+	switch v := value.(type) {
+	case string:
+		return cty.StringVal(v)
+	case bool:
+		return cty.BoolVal(v)
+	case float64:
+		return cty.NumberFloatVal(v)
+	case int:
+		return cty.NumberIntVal(int64(v))
+	case []interface{}:
+		if len(v) == 0 {
+			return cty.EmptyTupleVal
+		}
+		values := make([]cty.Value, 0, len(v))
+		for _, element := range v {
+			values = append(values, toCtyValue(element))
+		}
+		return cty.TupleVal(values)
+	case map[string]interface{}:
+		if len(v) == 0 {
+			return cty.EmptyObjectVal
+		}
+		values := make(map[string]cty.Value, len(v))
+		for key, element := range v {
+			values[key] = toCtyValue(element)
+		}
+		return cty.ObjectVal(values)
+	}
+	return cty.NullVal(cty.DynamicPseudoType)
+}
+
+// scannedProviders returns the providers of a scan, analyses of a single provider
+// may not record the provider of each resource type
+func scannedProviders(analysis *analyser.Analysis) []analyser.ProviderInfo {
+	if len(analysis.ResourceTypeProviders) == 0 {
+		return []analyser.ProviderInfo{{Name: analysis.ProviderName, Version: analysis.ProviderVersion}}
+	}
+	providers := make([]analyser.ProviderInfo, 0)
+	for _, provider := range analysis.ResourceTypeProviders {
+		if !containsProvider(providers, provider) {
+			providers = append(providers, provider)
+		}
+	}
+	sort.Slice(providers, func(i, j int) bool {
+		return providers[i].Name < providers[j].Name
+	})
+	return providers
+}
+
+func containsProvider(providers []analyser.ProviderInfo, provider analyser.ProviderInfo) bool {
+	for _, p := range providers {
+		if p == provider {
+			return true
+		}
+	}
+	return false
+}
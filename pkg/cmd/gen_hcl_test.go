@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/cloudskiff/driftctl/pkg/resource"
+	testresource "github.com/cloudskiff/driftctl/test/resource"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenHcl(t *testing.T) {
+	cases := []struct {
+		name      string
+		provider  string
+		version   string
+		mutator   func(repo resource.SchemaRepositoryInterface)
+		resources []*resource.Resource
+		count     int
+		expected  string
+	}{
+		{
+			name:     "test nested blocks and computed attributes",
+			provider: "github",
+			version:  "4.4.0",
+			resources: []*resource.Resource{
+				{
+					Id:   "BPR_xxx",
+					Type: "github_branch_protection",
+					Attrs: &resource.Attributes{
+						"id":                     "BPR_xxx",
+						"pattern":                "main",
+						"repository_id":          "MDEwOlJlcG9zaXRvcnkxMjM0NTY3OA==",
+						"enforce_admins":         true,
+						"require_signed_commits": false,
+						"push_restrictions":      []interface{}{},
+						"unknown_attribute":      "foobar",
+						"required_status_checks": []interface{}{
+							map[string]interface{}{
+								"strict":   true,
+								"contexts": []interface{}{"ci/build", "ci/test"},
+							},
+						},
+						"required_pull_request_reviews": []interface{}{
+							map[string]interface{}{
+								"dismiss_stale_reviews":           true,
+								"required_approving_review_count": float64(2),
+							},
+						},
+					},
+				},
+				{
+					Id:   "driftctl-demo",
+					Type: "github_repository",
+					Attrs: &resource.Attributes{
+						"name":        "driftctl-demo",
+						"description": "",
+						"full_name":   "cloudskiff/driftctl-demo",
+						"etag":        "W/\"abcdef\"",
+						"has_issues":  true,
+						"topics":      []interface{}{"demo"},
+						"visibility":  "public",
+					},
+				},
+				{
+					Id:   "without-attributes",
+					Type: "github_repository",
+				},
+				{
+					Id:   "unknown",
+					Type: "github_unknown_type",
+					Attrs: &resource.Attributes{
+						"name": "unknown",
+					},
+				},
+			},
+			count:    2,
+			expected: "./testdata/gen_hcl/output_github.tf",
+		},
+		{
+			name:     "test json string attributes",
+			provider: "google",
+			version:  "3.78.0",
+			mutator: func(repo resource.SchemaRepositoryInterface) {
+				repo.UpdateSchema("google_storage_bucket_iam_policy", map[string]func(attributeSchema *resource.AttributeSchema){
+					"policy_data": func(attributeSchema *resource.AttributeSchema) {
+						attributeSchema.JsonString = true
+					},
+				})
+			},
+			resources: []*resource.Resource{
+				{
+					Id:   "b/driftctl",
+					Type: "google_storage_bucket_iam_policy",
+					Attrs: &resource.Attributes{
+						"bucket":      "b/driftctl",
+						"etag":        "CAE=",
+						"policy_data": `{"bindings":[{"members":["user:foo@example.com"],"role":"roles/storage.admin"}]}`,
+					},
+				},
+			},
+			count:    1,
+			expected: "./testdata/gen_hcl/output_google.tf",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			repo := testresource.InitFakeSchemaRepository(c.provider, c.version)
+			if c.mutator != nil {
+				c.mutator(repo)
+			}
+
+			output := &bytes.Buffer{}
+			count, err := genHcl(output, repo, c.resources)
+			assert.NoError(t, err)
+			assert.Equal(t, c.count, count)
+
+			expected, err := os.ReadFile(c.expected)
+			assert.NoError(t, err)
+			assert.Equal(t, string(expected), output.String())
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/cloudskiff/driftctl/pkg/resource"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+const (
+	genImportFormatBlock  = "block"
+	genImportFormatScript = "script"
+)
+
+type GenImportOptions struct {
+	Format     string
+	InputPath  string
+	OutputPath string
+	// TriageSessionPath restricts unmanaged resources to the ones queued for import during triage
+	TriageSessionPath string
+}
+
+func NewGenImportCmd() *cobra.Command {
+	opts := &GenImportOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "gen-import",
+		Short: "Generate terraform import instructions for unmanaged resources",
+		Long: "This command will generate terraform import blocks or terraform import commands for the resources not covered by IaC found in your scan result\n\n" +
+			"Import blocks require terraform >= 1.5, use 'terraform plan -generate-config-out=generated.tf' to generate matching resource blocks\n\n" +
+			"Example: driftctl scan -o json://stdout | driftctl gen-import -o imports.tf",
+		Args: cobra.NoArgs,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if opts.Format != genImportFormatBlock && opts.Format != genImportFormatScript {
+				return errors.Errorf("unsupported format '%s'\nValid values are: %s,%s", opts.Format, genImportFormatBlock, genImportFormatScript)
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			analysis, err := readAnalysis(opts.InputPath)
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			if opts.OutputPath != "-" {
+				f, err := os.OpenFile(opts.OutputPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+				if err != nil {
+					return errors.Errorf("error opening output file: %s", err)
+				}
+				defer f.Close()
+				out = f
+			}
+
+			resources := analysis.Unmanaged()
+			if opts.TriageSessionPath != "" {
+				resources, err = triageImports(opts.TriageSessionPath, resources)
+				if err != nil {
+					return err
+				}
+			}
+
+			n, err := genImport(out, opts.Format, resources)
+			if err != nil {
+				return err
+			}
+			if opts.OutputPath != "-" {
+				fmt.Fprintf(os.Stderr, "Generated import for %d resource(s) in %s\n", n, opts.OutputPath)
+			}
+
+			return nil
+		},
+	}
+
+	fl := cmd.Flags()
+	fl.StringVar(&opts.Format, "format", genImportFormatBlock, "Output format, either 'block' for terraform import blocks or 'script' for terraform import commands")
+	fl.StringVarP(&opts.InputPath, "input", "i", "-", "Input where the JSON should be parsed from. Defaults to stdin.")
+	fl.StringVarP(&opts.OutputPath, "output", "o", "-", "Output file path to write the import instructions to. Defaults to stdout.")
+	fl.StringVar(&opts.TriageSessionPath, "triage-session", "", "Only generate import instructions for the resources queued for import in this triage session")
+
+	return cmd
+}
+
+// genImport writes import instructions and returns the number of resources that can be imported,
+// resources for which the import id cannot be computed are written as comments
+func genImport(out io.Writer, format string, resources []*resource.Resource) (int, error) {
+	names := newTerraformNames()
+	count := 0
+
+	if format == genImportFormatScript {
+		if _, err := fmt.Fprintln(out, "#!/bin/sh"); err != nil {
+			return 0, err
+		}
+	}
+
+	for _, res := range resource.Sort(resources) {
+		id, err := resource.ImportId(res)
+		if err != nil {
+			if _, err := fmt.Fprintf(out, "# Unable to generate import for %s (%s): %s\n", res.ResourceId(), res.ResourceType(), err); err != nil {
+				return count, err
+			}
+			continue
+		}
+
+		address := fmt.Sprintf("%s.%s", res.ResourceType(), names.Name(res))
+		switch format {
+		case genImportFormatScript:
+			_, err = fmt.Fprintf(out, "terraform import %s %s\n", shellQuote(address), shellQuote(id))
+		default:
+			_, err = fmt.Fprintf(out, "import {\n  to = %s\n  id = %q\n}\n\n", address, id)
+		}
+		if err != nil {
+			return count, err
+		}
+		count++
+	}
+
+	return count, nil
+}
+
+var invalidTerraformNameChars = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+var validTerraformNameStart = regexp.MustCompile(`^[a-zA-Z_]`)
+
+// terraformNames generates unique terraform resource names from resource ids
+type terraformNames struct {
+	used map[string]bool
+}
+
+func newTerraformNames() *terraformNames {
+	return &terraformNames{used: map[string]bool{}}
+}
+
+func (t *terraformNames) Name(res *resource.Resource) string {
+	base := strings.Trim(invalidTerraformNameChars.ReplaceAllString(res.ResourceId(), "_"), "_")
+	// Names must start with a letter or an underscore
+	if !validTerraformNameStart.MatchString(base) {
+		base = "r_" + base
+	}
+
+	name := base
+	for i := 2; t.used[fmt.Sprintf("%s.%s", res.ResourceType(), name)]; i++ {
+		name = fmt.Sprintf("%s_%d", base, i)
+	}
+	t.used[fmt.Sprintf("%s.%s", res.ResourceType(), name)] = true
+	return name
+}
+
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
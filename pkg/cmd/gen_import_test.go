@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"os"
+	"testing"
+
+	"github.com/cloudskiff/driftctl/pkg/resource"
+	"github.com/cloudskiff/driftctl/test"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenImportCmd(t *testing.T) {
+	cases := []struct {
+		name     string
+		args     []string
+		expected string
+		err      string
+	}{
+		{
+			name:     "test import blocks",
+			args:     []string{"-i", "./testdata/gen_import/input.json"},
+			expected: "./testdata/gen_import/output_block.tf",
+		},
+		{
+			name:     "test import script",
+			args:     []string{"-i", "./testdata/gen_import/input.json", "--format", "script"},
+			expected: "./testdata/gen_import/output_script.sh",
+		},
+		{
+			name:     "test import of resources queued during triage",
+			args:     []string{"-i", "./testdata/gen_import/input.json", "--triage-session", "./testdata/gen_import/triage_session.json"},
+			expected: "./testdata/gen_import/output_triage.tf",
+		},
+		{
+			name: "test missing triage session",
+			args: []string{"-i", "./testdata/gen_import/input.json", "--triage-session", "./testdata/gen_import/missing.json"},
+			err:  "unable to read triage session: stat ./testdata/gen_import/missing.json: no such file or directory",
+		},
+		{
+			name: "test invalid format",
+			args: []string{"-i", "./testdata/gen_import/input.json", "--format", "foobar"},
+			err:  "unsupported format 'foobar'\nValid values are: block,script",
+		},
+		{
+			name: "test error on invalid input",
+			args: []string{"-i", "./testdata/input_stdin_invalid.json"},
+			err:  "invalid character 'i' looking for beginning of value",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			rootCmd := &cobra.Command{Use: "root"}
+			rootCmd.AddCommand(NewGenImportCmd())
+
+			output, err := test.Execute(rootCmd, append([]string{"gen-import"}, c.args...)...)
+			if c.err != "" {
+				assert.EqualError(t, err, c.err)
+				return
+			}
+			assert.NoError(t, err)
+
+			expected, err := os.ReadFile(c.expected)
+			assert.NoError(t, err)
+			assert.Equal(t, string(expected), output)
+		})
+	}
+}
+
+func TestTerraformNames_Name(t *testing.T) {
+	names := newTerraformNames()
+	assert.Equal(t, "foo_bar", names.Name(&resource.Resource{Id: "foo.bar", Type: "aws_s3_bucket"}))
+	assert.Equal(t, "foo_bar_2", names.Name(&resource.Resource{Id: "foo/bar", Type: "aws_s3_bucket"}))
+	assert.Equal(t, "foo_bar", names.Name(&resource.Resource{Id: "foo.bar", Type: "aws_iam_user"}))
+	assert.Equal(t, "r_1234", names.Name(&resource.Resource{Id: "1234", Type: "aws_s3_bucket"}))
+	assert.Equal(t, "r_", names.Name(&resource.Resource{Id: "...", Type: "aws_s3_bucket"}))
+}
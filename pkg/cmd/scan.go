@@ -5,6 +5,7 @@ import (
 	"os"
 	"os/signal"
 	"regexp"
+	"sort"
 	"strings"
 	"syscall"
 	"time"
@@ -31,6 +32,7 @@ import (
 	"github.com/cloudskiff/driftctl/pkg/iac/terraform/state/backend"
 	globaloutput "github.com/cloudskiff/driftctl/pkg/output"
 	"github.com/cloudskiff/driftctl/pkg/remote"
+	"github.com/cloudskiff/driftctl/pkg/remote/aws"
 	"github.com/cloudskiff/driftctl/pkg/resource"
 	"github.com/cloudskiff/driftctl/pkg/terraform"
 )
@@ -53,13 +55,20 @@ func NewScanCmd(opts *pkg.ScanOptions) *cobra.Command {
 
 			opts.From = iacSource
 
-			to, _ := cmd.Flags().GetString("to")
-			if !remote.IsSupported(to) {
-				return errors.Errorf(
-					"unsupported cloud provider '%s'\nValid values are: %s",
-					to,
-					strings.Join(remote.GetSupportedRemotes(), ","),
-				)
+			to, _ := cmd.Flags().GetStringSlice("to")
+			opts.To = make([]string, 0, len(to))
+			for _, r := range to {
+				if !remote.IsSupported(r) {
+					return errors.Errorf(
+						"unsupported cloud provider '%s'\nValid values are: %s",
+						r,
+						strings.Join(remote.GetSupportedRemotes(), ","),
+					)
+				}
+				// A provider given twice is only scanned once
+				if !contains(opts.To, r) {
+					opts.To = append(opts.To, r)
+				}
 			}
 
 			outputFlag, _ := cmd.Flags().GetStringSlice("output")
@@ -68,6 +77,12 @@ func NewScanCmd(opts *pkg.ScanOptions) *cobra.Command {
 			if err != nil {
 				return err
 			}
+			markdownMaxSize, _ := cmd.Flags().GetInt("markdown-max-size")
+			for i := range out {
+				if out[i].Key == output.MarkdownOutputType {
+					out[i].MaxSize = markdownMaxSize
+				}
+			}
 			opts.Output = out
 
 			filterFlag, _ := cmd.Flags().GetStringArray("filter")
@@ -88,20 +103,52 @@ func NewScanCmd(opts *pkg.ScanOptions) *cobra.Command {
 			if err := validateTfProviderVersionString(providerVersion); err != nil {
 				return err
 			}
-			opts.ProviderVersion = providerVersion
+			if providerVersion != "" && len(opts.To) > 1 {
+				return errors.New("--tf-provider-version can only be used to scan a single cloud provider, versions of several providers are read from the terraform lock file")
+			}
 
-			if opts.ProviderVersion == "" {
-				lockfilePath, _ := cmd.Flags().GetString("tf-lockfile")
+			lockfilePath, _ := cmd.Flags().GetString("tf-lockfile")
 
-				// Attempt to read the provider version from a terraform lock file
-				lockFile, err := lock.ReadLocksFromFile(lockfilePath)
-				if err != nil {
-					logrus.WithField("error", err.Error()).Debug("Error while parsing terraform lock file")
+			// Attempt to read the provider version from a terraform lock file
+			lockFile, err := lock.ReadLocksFromFile(lockfilePath)
+			if err != nil {
+				logrus.WithField("error", err.Error()).Debug("Error while parsing terraform lock file")
+			}
+			opts.ProviderVersions = make(map[string]string, len(opts.To))
+			for _, to := range opts.To {
+				if providerVersion != "" {
+					opts.ProviderVersions[to] = providerVersion
+					continue
 				}
 				if provider := lockFile.GetProviderByAddress(common.RemoteParameter(to).GetProviderAddress()); provider != nil {
-					opts.ProviderVersion = provider.Version
-					logrus.WithFields(logrus.Fields{"version": opts.ProviderVersion, "provider": to}).Debug("Found provider version in terraform lock file")
+					opts.ProviderVersions[to] = provider.Version
+					logrus.WithFields(logrus.Fields{"version": provider.Version, "provider": to}).Debug("Found provider version in terraform lock file")
+				}
+			}
+
+			mirrors, _ := cmd.Flags().GetStringSlice("tf-provider-mirror")
+			installation, err := terraform.GetProviderInstallation(mirrors)
+			if err != nil {
+				return err
+			}
+			// Mirrored providers are checked against the checksums of the lock file
+			installation.Lockfile = lockFile
+			opts.ProviderInstallation = installation
+
+			if awsTargetsPath, _ := cmd.Flags().GetString("aws-targets"); awsTargetsPath != "" {
+				if !contains(opts.To, common.RemoteAWSTerraform) {
+					return errors.Errorf("--aws-targets can only be used to scan %s", common.RemoteAWSTerraform)
+				}
+				targets, err := aws.ReadTargets(awsTargetsPath)
+				if err != nil {
+					return err
 				}
+				opts.AWSTargets = targets
+			}
+
+			opts.DriftignorePaths, err = driftIgnorePaths(cmd)
+			if err != nil {
+				return err
 			}
 
 			opts.Quiet, _ = cmd.Flags().GetBool("quiet")
@@ -140,6 +187,12 @@ func NewScanCmd(opts *pkg.ScanOptions) *cobra.Command {
 		"Output format, by default it will write to the console\n"+
 			"Accepted formats are: "+strings.Join(output.SupportedOutputsExample(), ",")+"\n",
 	)
+	fl.Int(
+		"markdown-max-size",
+		output.DefaultMarkdownMaxSize,
+		"Maximum size in bytes of the markdown output, larger results are truncated. Use 0 to disable truncation.\n"+
+			"Only used with markdown output.\n",
+	)
 	fl.StringSliceP(
 		"from",
 		"f",
@@ -148,12 +201,11 @@ func NewScanCmd(opts *pkg.ScanOptions) *cobra.Command {
 			"Accepted schemes are: "+strings.Join(supplier.GetSupportedSchemes(), ",")+"\n",
 	)
 	supportedRemotes := remote.GetSupportedRemotes()
-	fl.StringVarP(
-		&opts.To,
+	fl.StringSliceP(
 		"to",
 		"t",
-		supportedRemotes[0],
-		"Cloud provider source\n"+
+		[]string{supportedRemotes[0]},
+		"Cloud provider sources, several providers are scanned at once when given a comma separated list\n"+
 			"Accepted values are: "+strings.Join(supportedRemotes, ",")+"\n",
 	)
 	fl.StringToStringVarP(&opts.BackendOptions.Headers,
@@ -175,6 +227,35 @@ func NewScanCmd(opts *pkg.ScanOptions) *cobra.Command {
 		"Terraform Cloud / Enterprise API endpoint.\n"+
 			"Only used with tfstate+tfcloud backend.\n",
 	)
+	fl.BoolVar(&opts.BackendOptions.AllWorkspaces,
+		"all-workspaces",
+		false,
+		"Read the states of every terraform workspace.\n"+
+			"tfstate sources must point to the state of the default workspace, or to an organization for tfstate+tfcloud backend.\n",
+	)
+	fl.StringVar(&opts.BackendOptions.WorkspaceKeyPrefix,
+		"workspace-key-prefix",
+		"",
+		"Prefix of non default workspaces states in S3 buckets, env: by default.\n"+
+			"Only used with tfstate+s3 backend and --all-workspaces.\n",
+	)
+	fl.StringVar(&opts.BackendOptions.StateDecryption.TofuPassphrase,
+		"tofu-passphrase",
+		"",
+		"Passphrase of OpenTofu encrypted states, using the pbkdf2 key provider and the aes_gcm method.\n"+
+			"Prefer the DCTL_TOFU_PASSPHRASE env var to keep it out of your shell history and process list.\n",
+	)
+	fl.StringVar(&opts.BackendOptions.StateDecryption.SopsAgeKeyFile,
+		"sops-age-key-file",
+		"",
+		"File containing the age identities of sops encrypted states.\n"+
+			"SOPS_AGE_KEY and SOPS_AGE_KEY_FILE env vars are used by default.\n",
+	)
+	fl.StringVar(&opts.BackendOptions.StateDecryption.SopsPGPKeyFile,
+		"sops-pgp-key-file",
+		"",
+		"File containing the PGP secret keyring of sops encrypted states.\n",
+	)
 	fl.String(
 		"tf-provider-version",
 		"",
@@ -191,16 +272,31 @@ func NewScanCmd(opts *pkg.ScanOptions) *cobra.Command {
 		fmt.Sprintf("%s Enable deep mode\n", warn("EXPERIMENTAL:"))+
 			"You should check the documentation for more details: https://docs.driftctl.com/deep-mode\n",
 	)
-	fl.StringVar(&opts.DriftignorePath,
-		"driftignore",
-		".driftignore",
-		"Path to the driftignore file",
+	addDriftIgnoreFlags(fl)
+	fl.BoolVar(&opts.ReportIgnored,
+		"report-ignored",
+		false,
+		"Report resources and fields ignored by the driftignore file with the rule that matched, in json and html outputs\n"+
+			"Resource types ignored as a whole are then still listed to be counted",
 	)
 	fl.String(
 		"tf-lockfile",
 		".terraform.lock.hcl",
 		"Terraform lock file to get the provider's version from. Will be ignored if the file doesn't exist.\n",
 	)
+	fl.StringSlice(
+		"tf-provider-mirror",
+		[]string{},
+		"Install terraform providers from filesystem directories or network mirror URLs in the layout of terraform providers mirror.\n"+
+			"Providers are installed as configured in the terraform CLI configuration otherwise.\n",
+	)
+
+	fl.String(
+		"aws-targets",
+		"",
+		"YAML file listing the AWS profiles, roles to assume and regions to scan at once.\n"+
+			"Resources are matched with the state resources of the same account and region.\n",
+	)
 
 	configDir, err := homedir.Dir()
 	if err != nil {
@@ -239,11 +335,6 @@ func scanRun(opts *pkg.ScanOptions) error {
 
 	resFactory := terraform.NewTerraformResourceFactory(resourceSchemaRepository)
 
-	err := remote.Activate(opts.To, opts.ProviderVersion, alerter, providerLibrary, remoteLibrary, scanProgress, resourceSchemaRepository, resFactory, opts.ConfigDir)
-	if err != nil {
-		return err
-	}
-
 	// Teardown
 	defer func() {
 		logrus.Trace("Exiting scan cmd")
@@ -251,12 +342,27 @@ func scanRun(opts *pkg.ScanOptions) error {
 		logrus.Trace("Exited")
 	}()
 
+	// Every remote registers its enumerators in the same library so they are scanned at once
+	for _, to := range opts.To {
+		err := remote.Activate(to, opts.ProviderVersions[to], alerter, providerLibrary, remoteLibrary, scanProgress, resourceSchemaRepository, resFactory, opts.ConfigDir, opts.ProviderInstallation, opts.AWSTargets)
+		if err != nil {
+			return err
+		}
+	}
+
 	logrus.Debug("Checking for driftignore")
-	driftIgnore := filter.NewDriftIgnore(opts.DriftignorePath)
+	driftIgnore := filter.NewDriftIgnoreFiles(opts.DriftignorePaths, alerter)
+	if !opts.Deep {
+		warnAttributeConditions(driftIgnore)
+	}
 
-	scanner := remote.NewScanner(remoteLibrary, alerter, remote.ScannerOptions{Deep: opts.Deep}, driftIgnore)
+	scanner := remote.NewScanner(remoteLibrary, alerter, remote.ScannerOptions{Deep: opts.Deep, ReportIgnored: opts.ReportIgnored}, driftIgnore)
 
-	iacSupplier, err := supplier.GetIACSupplier(opts.From, providerLibrary, opts.BackendOptions, iacProgress, alerter, resFactory, driftIgnore)
+	var iacFilter filter.Filter = driftIgnore
+	if opts.ReportIgnored {
+		iacFilter = filter.ReadIgnoredTypes(driftIgnore)
+	}
+	iacSupplier, err := supplier.GetIACSupplier(opts.From, providerLibrary, opts.BackendOptions, iacProgress, alerter, resFactory, iacFilter)
 	if err != nil {
 		return err
 	}
@@ -265,7 +371,7 @@ func scanRun(opts *pkg.ScanOptions) error {
 		scanner,
 		iacSupplier,
 		alerter,
-		analyser.NewAnalyzer(alerter, analyser.AnalyzerOptions{Deep: opts.Deep}, driftIgnore),
+		analyser.NewAnalyzer(alerter, analyser.AnalyzerOptions{Deep: opts.Deep, ReportIgnored: opts.ReportIgnored}, driftIgnore),
 		resFactory,
 		opts,
 		scanProgress,
@@ -285,8 +391,7 @@ func scanRun(opts *pkg.ScanOptions) error {
 		return err
 	}
 
-	analysis.ProviderVersion = resourceSchemaRepository.ProviderVersion.String()
-	analysis.ProviderName = resourceSchemaRepository.ProviderName
+	setAnalysisProviders(analysis, resourceSchemaRepository)
 	store.Bucket(memstore.TelemetryBucket).Set("provider_name", analysis.ProviderName)
 
 	validOutput := false
@@ -307,7 +412,15 @@ func scanRun(opts *pkg.ScanOptions) error {
 	}
 
 	globaloutput.Printf(color.WhiteString("Scan duration: %s\n", analysis.Duration.Round(time.Second)))
-	globaloutput.Printf(color.WhiteString("Provider version used to scan: %s. Use --tf-provider-version to use another version.\n"), resourceSchemaRepository.ProviderVersion.String())
+	if len(opts.To) == 1 {
+		globaloutput.Printf(color.WhiteString("Provider version used to scan: %s. Use --tf-provider-version to use another version.\n"), analysis.ProviderVersion)
+	} else {
+		versions := make([]string, 0, len(analysis.ProvidersCoverage()))
+		for _, provider := range analysis.ProvidersCoverage() {
+			versions = append(versions, fmt.Sprintf("%s %s", provider.Name, provider.Version))
+		}
+		globaloutput.Printf(color.WhiteString("Provider versions used to scan: %s. Use a terraform lock file to use other versions.\n"), strings.Join(versions, ", "))
+	}
 
 	if !opts.DisableTelemetry {
 		tl := telemetry.NewTelemetry(&build.Build{})
@@ -486,11 +599,77 @@ func parseOutputFlag(out string) (*output.OutputConfig, error) {
 			)
 		}
 		o.Path = opts[0]
+	case output.SARIFOutputType:
+		if len(opts) != 1 || opts[0] == "" {
+			return nil, errors.Wrapf(
+				cmderrors.NewUsageError(
+					fmt.Sprintf(
+						"\nMust be of kind: %s",
+						output.Example(output.SARIFOutputType),
+					),
+				),
+				"Invalid sarif output '%s'",
+				out,
+			)
+		}
+		o.Path = opts[0]
+	case output.JUnitOutputType:
+		if len(opts) != 1 || opts[0] == "" {
+			return nil, errors.Wrapf(
+				cmderrors.NewUsageError(
+					fmt.Sprintf(
+						"\nMust be of kind: %s",
+						output.Example(output.JUnitOutputType),
+					),
+				),
+				"Invalid junit output '%s'",
+				out,
+			)
+		}
+		o.Path = opts[0]
+	case output.MarkdownOutputType:
+		if len(opts) != 1 || opts[0] == "" {
+			return nil, errors.Wrapf(
+				cmderrors.NewUsageError(
+					fmt.Sprintf(
+						"\nMust be of kind: %s",
+						output.Example(output.MarkdownOutputType),
+					),
+				),
+				"Invalid markdown output '%s'",
+				out,
+			)
+		}
+		o.Path = opts[0]
 	}
 
 	return o, nil
 }
 
+// setAnalysisProviders records the provider of each resource type found by the scan,
+// the provider name and version of the analysis list every provider when several were used
+func setAnalysisProviders(analysis *analyser.Analysis, repo *resource.SchemaRepository) {
+	names := make([]string, 0, len(repo.Providers))
+	for name := range repo.Providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	versions := make([]string, 0, len(names))
+	for _, name := range names {
+		versions = append(versions, repo.Providers[name].String())
+	}
+	analysis.ProviderName = strings.Join(names, ",")
+	analysis.ProviderVersion = strings.Join(versions, ",")
+
+	resources := append(append(append([]*resource.Resource{}, analysis.Managed()...), analysis.Unmanaged()...), analysis.Deleted()...)
+	resources = append(resources, analysis.Planned()...)
+	for _, res := range resources {
+		if schema, exists := repo.GetSchema(res.ResourceType()); exists && schema.ProviderVersion != nil {
+			analysis.SetResourceTypeProvider(res.ResourceType(), schema.ProviderName, schema.ProviderVersion.String())
+		}
+	}
+}
+
 func validateTfProviderVersionString(version string) error {
 	if version == "" {
 		return nil
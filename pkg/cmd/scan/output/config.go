@@ -5,6 +5,8 @@ import "fmt"
 type OutputConfig struct {
 	Key  string
 	Path string
+	// Maximum size of the output in bytes, only used by the markdown output
+	MaxSize int
 }
 
 func (o *OutputConfig) String() string {
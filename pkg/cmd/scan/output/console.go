@@ -95,6 +95,17 @@ func (c *Console) Write(analysis *analyser.Analysis) error {
 		}
 	}
 
+	if analysis.Summary().TotalPlanned > 0 {
+		fmt.Println("Found resources declared in IaC but never applied:")
+		for _, res := range analysis.Planned() {
+			humanString := fmt.Sprintf("  - %s", res.ResourceId())
+			if res.Source != nil {
+				humanString += fmt.Sprintf(" (%s)", res.Source.Source())
+			}
+			fmt.Println(humanString)
+		}
+	}
+
 	if analysis.Summary().TotalDrifted > 0 {
 		var sources []string
 		groupedBySource := make(map[string][]analyser.Difference)
@@ -196,6 +207,16 @@ func (c Console) writeSummary(analysis *analyser.Analysis) {
 			analysis.Coverage(),
 		),
 	)
+	if providers := analysis.ProvidersCoverage(); len(providers) > 1 {
+		for _, provider := range providers {
+			fmt.Printf(
+				"     - %s%% coverage of %s resource(s) from %s\n",
+				boldWriter.Sprintf("%d", provider.Coverage),
+				boldWriter.Sprintf("%d", provider.Summary.TotalResources),
+				provider.Name,
+			)
+		}
+	}
 	if !analysis.IsSync() {
 		managed := successWriter.Sprintf("0")
 		if analysis.Summary().TotalManaged > 0 {
@@ -223,6 +244,9 @@ func (c Console) writeSummary(analysis *analyser.Analysis) {
 		}
 		fmt.Printf(" - %s resource(s) found in a Terraform state but missing on the cloud provider\n", deleted)
 	}
+	if analysis.Summary().TotalPlanned > 0 {
+		fmt.Printf(" - %s resource(s) declared in IaC but never applied\n", warningWriter.Sprintf("%d", analysis.Summary().TotalPlanned))
+	}
 	if analysis.IsSync() {
 		fmt.Println(color.GreenString("Congrats! Your infrastructure is fully in sync."))
 	}
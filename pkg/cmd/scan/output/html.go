@@ -45,6 +45,10 @@ type HTMLTemplateParams struct {
 	ScanDuration    string
 	ProviderName    string
 	ProviderVersion string
+	Providers       []analyser.ProviderCoverage
+	ReportIgnored   bool
+	Ignored         []analyser.IgnoredResource
+	IgnoredRules    []analyser.IgnoreRuleCount
 	LogoSvg         template.HTML
 	FaviconBase64   string
 }
@@ -103,6 +107,7 @@ func (c *HTML) Write(analysis *analyser.Analysis) error {
 
 			return distinctIaCSources(resources)
 		},
+		"join": strings.Join,
 		"rate": func(count int) float64 {
 			if analysis.Summary().TotalResources == 0 {
 				return 0
@@ -166,6 +171,10 @@ func (c *HTML) Write(analysis *analyser.Analysis) error {
 		ScanDuration:    analysis.Duration.Round(time.Second).String(),
 		ProviderName:    analysis.ProviderName,
 		ProviderVersion: analysis.ProviderVersion,
+		Providers:       analysis.ProvidersCoverage(),
+		ReportIgnored:   analysis.Options().ReportIgnored,
+		Ignored:         analysis.Ignored(),
+		IgnoredRules:    analysis.IgnoredRules(),
 		LogoSvg:         template.HTML(logoSvgFile),
 		FaviconBase64:   base64.StdEncoding.EncodeToString(faviconFile),
 	}
@@ -11,6 +11,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/cloudskiff/driftctl/pkg/analyser"
+	"github.com/cloudskiff/driftctl/pkg/filter"
 	"github.com/cloudskiff/driftctl/test/goldenfile"
 )
 
@@ -232,6 +233,35 @@ func TestHTML_Write(t *testing.T) {
 			},
 			err: nil,
 		},
+		{
+			name:       "test html output with ignored resources",
+			goldenfile: "output_ignored.html",
+			analysis: func() *analyser.Analysis {
+				a := analyser.NewAnalysis(analyser.AnalyzerOptions{ReportIgnored: true})
+				a.Date = time.Date(2021, 06, 10, 0, 0, 0, 0, &time.Location{})
+				a.Duration = 72 * time.Second
+				a.AddManaged(
+					&resource.Resource{
+						Id:   "web",
+						Type: "aws_instance",
+					},
+				)
+				bucketRule := filter.IgnoreRule{Pattern: "aws_s3_bucket.*", File: ".driftignore", Line: 2}
+				a.AddIgnored(
+					analyser.IgnoredResource{
+						Res:  &resource.Resource{Id: "web", Type: "aws_instance"},
+						Path: []string{"tags", "Name"},
+						Rule: filter.IgnoreRule{Pattern: "aws_instance.web.tags.*", File: ".driftignore", Line: 4},
+					},
+					analyser.IgnoredResource{Res: &resource.Resource{Id: "logs", Type: "aws_s3_bucket"}, Rule: bucketRule},
+					analyser.IgnoredResource{Res: &resource.Resource{Id: "unmanaged", Type: "aws_s3_bucket"}, Rule: bucketRule},
+				)
+				a.ProviderName = "AWS"
+				a.ProviderVersion = "3.19.0"
+				return a
+			},
+			err: nil,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
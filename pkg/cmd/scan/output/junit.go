@@ -0,0 +1,201 @@
+package output
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/r3labs/diff/v2"
+
+	"github.com/cloudskiff/driftctl/pkg/analyser"
+	"github.com/cloudskiff/driftctl/pkg/resource"
+)
+
+const JUnitOutputType = "junit"
+const JUnitOutputExample = "junit://PATH/TO/FILE.xml"
+
+// Name of the test suite holding resources not covered by IaC
+const junitUnmanagedSuiteName = "unmanaged"
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message  string `xml:"message,attr"`
+	Type     string `xml:"type,attr"`
+	Contents string `xml:",cdata"`
+}
+
+type JUnit struct {
+	path string
+}
+
+func NewJUnit(path string) *JUnit {
+	return &JUnit{path}
+}
+
+func (c *JUnit) Write(analysis *analyser.Analysis) error {
+	file := os.Stdout
+	if !isStdOut(c.path) {
+		f, err := os.OpenFile(c.path, os.O_CREATE|os.O_RDWR|os.O_TRUNC, 0600)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		file = f
+	}
+
+	output := junitTestSuites{Name: "driftctl"}
+	output.Suites = append(output.Suites, junitManagedSuites(analysis)...)
+	if len(analysis.Unmanaged()) > 0 {
+		output.Suites = append(output.Suites, junitUnmanagedSuite(analysis.Unmanaged()))
+	}
+	for _, suite := range output.Suites {
+		output.Tests += suite.Tests
+		output.Failures += suite.Failures
+	}
+
+	junit, err := xml.MarshalIndent(output, "", "\t")
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write([]byte(xml.Header)); err != nil {
+		return err
+	}
+	if _, err := file.Write(junit); err != nil {
+		return err
+	}
+	return nil
+}
+
+// junitManagedSuites creates a test suite per resource type found in IaC,
+// changed and missing resources are reported as failing test cases
+func junitManagedSuites(analysis *analyser.Analysis) []junitTestSuite {
+	suites := map[string]*junitTestSuite{}
+	addTestCase := func(res *resource.Resource, failure *junitFailure) {
+		suite, exist := suites[res.ResourceType()]
+		if !exist {
+			suite = &junitTestSuite{Name: res.ResourceType()}
+			suites[res.ResourceType()] = suite
+		}
+		suite.Tests++
+		if failure != nil {
+			suite.Failures++
+		}
+		suite.TestCases = append(suite.TestCases, junitTestCase{
+			Name:      res.ResourceId(),
+			Classname: res.ResourceType(),
+			Failure:   failure,
+		})
+	}
+
+	differences := make(map[string]analyser.Difference, len(analysis.Differences()))
+	for _, difference := range analysis.Differences() {
+		differences[junitResourceKey(difference.Res)] = difference
+	}
+
+	for _, res := range analysis.Managed() {
+		difference, drifted := differences[junitResourceKey(res)]
+		if !drifted {
+			addTestCase(res, nil)
+			continue
+		}
+		delete(differences, junitResourceKey(res))
+		addTestCase(res, newJUnitChangedFailure(difference))
+	}
+	// Differences are expected to be part of managed resources, but do not lose
+	// them if the analysis does not list them as managed
+	for _, difference := range analysis.Differences() {
+		if _, remaining := differences[junitResourceKey(difference.Res)]; remaining {
+			addTestCase(difference.Res, newJUnitChangedFailure(difference))
+		}
+	}
+	for _, res := range analysis.Deleted() {
+		addTestCase(res, &junitFailure{
+			Message: "Resource found in IaC but missing on cloud provider",
+			Type:    "missing",
+		})
+	}
+
+	types := make([]string, 0, len(suites))
+	for ty := range suites {
+		types = append(types, ty)
+	}
+	sort.Strings(types)
+
+	result := make([]junitTestSuite, 0, len(types))
+	for _, ty := range types {
+		suite := suites[ty]
+		sort.SliceStable(suite.TestCases, func(i, j int) bool {
+			return suite.TestCases[i].Name < suite.TestCases[j].Name
+		})
+		result = append(result, *suite)
+	}
+	return result
+}
+
+func junitUnmanagedSuite(unmanaged []*resource.Resource) junitTestSuite {
+	suite := junitTestSuite{
+		Name:     junitUnmanagedSuiteName,
+		Tests:    len(unmanaged),
+		Failures: len(unmanaged),
+	}
+	for _, res := range unmanaged {
+		suite.TestCases = append(suite.TestCases, junitTestCase{
+			Name:      res.ResourceId(),
+			Classname: res.ResourceType(),
+			Failure: &junitFailure{
+				Message: "Resource not covered by IaC",
+				Type:    "unmanaged",
+			},
+		})
+	}
+	return suite
+}
+
+func newJUnitChangedFailure(difference analyser.Difference) *junitFailure {
+	lines := make([]string, 0, len(difference.Changelog))
+	for _, change := range difference.Changelog {
+		path := strings.Join(change.Path, ".")
+		line := fmt.Sprintf("~ %s: %s => %s", path, prettify(change.From), prettify(change.To))
+		switch change.Type {
+		case diff.CREATE:
+			line = fmt.Sprintf("+ %s: %s", path, prettify(change.To))
+		case diff.DELETE:
+			line = fmt.Sprintf("- %s: %s", path, prettify(change.From))
+		}
+		if change.Computed {
+			line += " (computed)"
+		}
+		lines = append(lines, line)
+	}
+	return &junitFailure{
+		Message:  "Resource changed on cloud provider",
+		Type:     "changed",
+		Contents: strings.Join(lines, "\n"),
+	}
+}
+
+func junitResourceKey(res *resource.Resource) string {
+	return fmt.Sprintf("%s.%s", res.ResourceType(), res.ResourceId())
+}
@@ -0,0 +1,218 @@
+package output
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/r3labs/diff/v2"
+
+	"github.com/cloudskiff/driftctl/pkg/analyser"
+	"github.com/cloudskiff/driftctl/pkg/resource"
+)
+
+const MarkdownOutputType = "markdown"
+const MarkdownOutputExample = "markdown://PATH/TO/FILE.md"
+
+// DefaultMarkdownMaxSize fits in a GitHub pull request comment
+const DefaultMarkdownMaxSize = 65000
+
+const markdownTruncatedMessage = "\n> :warning: Output truncated, %d resource(s) not displayed. Use the JSON output to get the full result.\n"
+
+type Markdown struct {
+	path    string
+	maxSize int
+}
+
+// NewMarkdown creates a markdown output, a maxSize lower or equal to zero disables truncation
+func NewMarkdown(path string, maxSize int) *Markdown {
+	return &Markdown{path, maxSize}
+}
+
+func (c *Markdown) Write(analysis *analyser.Analysis) error {
+	file := os.Stdout
+	if !isStdOut(c.path) {
+		f, err := os.OpenFile(c.path, os.O_CREATE|os.O_RDWR|os.O_TRUNC, 0600)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		file = f
+	}
+
+	if _, err := file.WriteString(c.render(analysis)); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (c *Markdown) render(analysis *analyser.Analysis) string {
+	builder := &markdownBuilder{maxSize: c.maxSize}
+
+	builder.WriteString("## Driftctl scan report\n\n")
+	if analysis.IsSync() {
+		builder.WriteString(":white_check_mark: Congrats! Your infrastructure is fully in sync.\n\n")
+	}
+	builder.WriteString("| | Count |\n|---|---|\n")
+	builder.WriteString(fmt.Sprintf("| Total resources | %d |\n", analysis.Summary().TotalResources))
+	builder.WriteString(fmt.Sprintf("| Coverage | %d%% |\n", analysis.Coverage()))
+	builder.WriteString(fmt.Sprintf("| Managed | %d |\n", analysis.Summary().TotalManaged))
+	if analysis.Options().Deep {
+		builder.WriteString(fmt.Sprintf("| Changed | %d |\n", analysis.Summary().TotalDrifted))
+	}
+	builder.WriteString(fmt.Sprintf("| Not covered by IaC | %d |\n", analysis.Summary().TotalUnmanaged))
+	builder.WriteString(fmt.Sprintf("| Missing | %d |\n", analysis.Summary().TotalDeleted))
+
+	if providers := analysis.ProvidersCoverage(); len(providers) > 1 {
+		builder.WriteString("\n| Provider | Total resources | Coverage | Managed | Not covered by IaC | Missing |\n|---|---|---|---|---|---|\n")
+		for _, provider := range providers {
+			builder.WriteString(fmt.Sprintf(
+				"| %s (%s) | %d | %d%% | %d | %d | %d |\n",
+				provider.Name,
+				provider.Version,
+				provider.Summary.TotalResources,
+				provider.Coverage,
+				provider.Summary.TotalManaged,
+				provider.Summary.TotalUnmanaged,
+				provider.Summary.TotalDeleted,
+			))
+		}
+	}
+
+	if len(analysis.Unmanaged()) > 0 {
+		items := make([]string, 0, len(analysis.Unmanaged()))
+		for _, res := range analysis.Unmanaged() {
+			items = append(items, fmt.Sprintf("| %s | %s |\n", res.ResourceType(), escapeMarkdownCell(res.ResourceId())))
+		}
+		builder.WriteSection(
+			fmt.Sprintf("Resources not covered by IaC (%d)", len(items)),
+			"| Type | Id |\n|---|---|\n",
+			items,
+		)
+	}
+
+	if len(analysis.Deleted()) > 0 {
+		items := make([]string, 0, len(analysis.Deleted()))
+		for _, res := range analysis.Deleted() {
+			items = append(items, fmt.Sprintf("| %s | %s | %s |\n", res.ResourceType(), escapeMarkdownCell(res.ResourceId()), escapeMarkdownCell(markdownSource(res))))
+		}
+		builder.WriteSection(
+			fmt.Sprintf("Missing resources (%d)", len(items)),
+			"| Type | Id | Source |\n|---|---|---|\n",
+			items,
+		)
+	}
+
+	if len(analysis.Differences()) > 0 {
+		items := make([]string, 0, len(analysis.Differences()))
+		for _, difference := range analysis.Differences() {
+			items = append(items, markdownDifference(difference))
+		}
+		builder.WriteSection(
+			fmt.Sprintf("Changed resources (%d)", len(items)),
+			"",
+			items,
+		)
+	}
+
+	return builder.String()
+}
+
+func markdownDifference(difference analyser.Difference) string {
+	var b strings.Builder
+	title := fmt.Sprintf("%s.%s", difference.Res.ResourceType(), difference.Res.ResourceId())
+	if source := markdownSource(difference.Res); source != "" {
+		title += fmt.Sprintf(" (%s)", source)
+	}
+	b.WriteString(fmt.Sprintf("#### %s\n\n", escapeMarkdownCell(title)))
+	b.WriteString("| | Path | From | To |\n|---|---|---|---|\n")
+	for _, change := range difference.Changelog {
+		op := "~"
+		switch change.Type {
+		case diff.CREATE:
+			op = "+"
+		case diff.DELETE:
+			op = "-"
+		}
+		path := strings.Join(change.Path, ".")
+		if change.Computed {
+			path += " (computed)"
+		}
+		b.WriteString(fmt.Sprintf(
+			"| %s | %s | %s | %s |\n",
+			op,
+			escapeMarkdownCell(path),
+			markdownValue(change.Type != diff.CREATE, change.From),
+			markdownValue(change.Type != diff.DELETE, change.To),
+		))
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+func markdownValue(display bool, value interface{}) string {
+	if !display {
+		return ""
+	}
+	return fmt.Sprintf("`%s`", escapeMarkdownCell(prettify(value)))
+}
+
+func markdownSource(res *resource.Resource) string {
+	if res.Source == nil {
+		return ""
+	}
+	if res.SourceString() == "" {
+		return res.Source.Source()
+	}
+	return fmt.Sprintf("%s (%s)", res.SourceString(), res.Source.Source())
+}
+
+func escapeMarkdownCell(value string) string {
+	value = strings.ReplaceAll(value, "|", `\|`)
+	value = strings.ReplaceAll(value, "\n", "<br>")
+	return value
+}
+
+// markdownBuilder writes collapsible sections while keeping the output below a
+// maximum size, items that do not fit anymore are counted and reported at the end
+type markdownBuilder struct {
+	strings.Builder
+	maxSize int
+	skipped int
+}
+
+func (b *markdownBuilder) WriteSection(title, header string, items []string) {
+	open := fmt.Sprintf("\n<details>\n<summary>%s</summary>\n\n%s", title, header)
+	closing := "</details>\n"
+
+	if b.skipped > 0 || !b.fits(len(open)+len(closing)) {
+		b.skipped += len(items)
+		return
+	}
+
+	b.WriteString(open)
+	for _, item := range items {
+		if b.skipped > 0 || !b.fits(len(item)+len(closing)) {
+			b.skipped++
+			continue
+		}
+		b.WriteString(item)
+	}
+	b.WriteString(closing)
+}
+
+func (b *markdownBuilder) String() string {
+	if b.skipped > 0 {
+		return b.Builder.String() + fmt.Sprintf(markdownTruncatedMessage, b.skipped)
+	}
+	return b.Builder.String()
+}
+
+// fits returns true when size bytes can be added while keeping room for the truncation message
+func (b *markdownBuilder) fits(size int) bool {
+	if b.maxSize <= 0 {
+		return true
+	}
+	reserved := len(fmt.Sprintf(markdownTruncatedMessage, 0)) + 10
+	return b.Len()+size+reserved <= b.maxSize
+}
@@ -0,0 +1,88 @@
+package output
+
+import (
+	"io/ioutil"
+	"path"
+	"testing"
+
+	"github.com/cloudskiff/driftctl/pkg/analyser"
+	"github.com/cloudskiff/driftctl/test/goldenfile"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarkdown_Write(t *testing.T) {
+	tests := []struct {
+		name       string
+		goldenfile string
+		analysis   *analyser.Analysis
+		maxSize    int
+		wantErr    bool
+	}{
+		{
+			name:       "test markdown output",
+			goldenfile: "output.md",
+			analysis:   fakeAnalysis(analyser.AnalyzerOptions{}),
+			maxSize:    DefaultMarkdownMaxSize,
+			wantErr:    false,
+		},
+		{
+			name:       "test markdown output without truncation",
+			goldenfile: "output.md",
+			analysis:   fakeAnalysis(analyser.AnalyzerOptions{}),
+			maxSize:    0,
+			wantErr:    false,
+		},
+		{
+			name:       "test markdown output when in sync",
+			goldenfile: "output_sync.md",
+			analysis:   &analyser.Analysis{},
+			maxSize:    DefaultMarkdownMaxSize,
+			wantErr:    false,
+		},
+		{
+			name:       "test markdown output with several providers",
+			goldenfile: "output_providers.md",
+			analysis:   fakeAnalysisWithProviders(),
+			maxSize:    DefaultMarkdownMaxSize,
+			wantErr:    false,
+		},
+		{
+			name:       "test markdown output truncated",
+			goldenfile: "output_truncated.md",
+			analysis:   fakeAnalysis(analyser.AnalyzerOptions{}),
+			maxSize:    600,
+			wantErr:    false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir := t.TempDir()
+			tempFile, err := ioutil.TempFile(tempDir, "result")
+			if err != nil {
+				t.Fatal(err)
+			}
+			c := NewMarkdown(tempFile.Name(), tt.maxSize)
+			if err := c.Write(tt.analysis); (err != nil) != tt.wantErr {
+				t.Errorf("Write() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			result, err := ioutil.ReadFile(tempFile.Name())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.maxSize > 0 {
+				assert.LessOrEqual(t, len(result), tt.maxSize)
+			}
+			expectedFilePath := path.Join("./testdata/", tt.goldenfile)
+			if *goldenfile.Update == tt.goldenfile {
+				if err := ioutil.WriteFile(expectedFilePath, result, 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+			expected, err := ioutil.ReadFile(expectedFilePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, string(expected), string(result))
+		})
+	}
+}
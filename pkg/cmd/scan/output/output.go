@@ -16,13 +16,19 @@ var supportedOutputTypes = []string{
 	JSONOutputType,
 	HTMLOutputType,
 	PlanOutputType,
+	SARIFOutputType,
+	JUnitOutputType,
+	MarkdownOutputType,
 }
 
 var supportedOutputExample = map[string]string{
-	ConsoleOutputType: ConsoleOutputExample,
-	JSONOutputType:    JSONOutputExample,
-	HTMLOutputType:    HTMLOutputExample,
-	PlanOutputType:    PlanOutputExample,
+	ConsoleOutputType:  ConsoleOutputExample,
+	JSONOutputType:     JSONOutputExample,
+	HTMLOutputType:     HTMLOutputExample,
+	PlanOutputType:     PlanOutputExample,
+	SARIFOutputType:    SARIFOutputExample,
+	JUnitOutputType:    JUnitOutputExample,
+	MarkdownOutputType: MarkdownOutputExample,
 }
 
 func SupportedOutputsExample() []string {
@@ -55,6 +61,12 @@ func GetOutput(config OutputConfig) Output {
 		return NewHTML(config.Path)
 	case PlanOutputType:
 		return NewPlan(config.Path)
+	case SARIFOutputType:
+		return NewSARIF(config.Path)
+	case JUnitOutputType:
+		return NewJUnit(config.Path)
+	case MarkdownOutputType:
+		return NewMarkdown(config.Path, config.MaxSize)
 	case ConsoleOutputType:
 		fallthrough
 	default:
@@ -89,6 +101,21 @@ func GetPrinter(config OutputConfig, quiet bool) output.Printer {
 			return &output.VoidPrinter{}
 		}
 		fallthrough
+	case SARIFOutputType:
+		if isStdOut(config.Path) {
+			return &output.VoidPrinter{}
+		}
+		fallthrough
+	case JUnitOutputType:
+		if isStdOut(config.Path) {
+			return &output.VoidPrinter{}
+		}
+		fallthrough
+	case MarkdownOutputType:
+		if isStdOut(config.Path) {
+			return &output.VoidPrinter{}
+		}
+		fallthrough
 	case ConsoleOutputType:
 		fallthrough
 	default:
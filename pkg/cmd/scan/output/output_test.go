@@ -125,6 +125,25 @@ func fakeAnalysisWithAlerts() *analyser.Analysis {
 	return a
 }
 
+func fakeAnalysisWithProviders() *analyser.Analysis {
+	a := fakeAnalysis(analyser.AnalyzerOptions{})
+	a.AddManaged(&resource.Resource{
+		Id:   "driftctl",
+		Type: "github_repository",
+	})
+	a.AddUnmanaged(&resource.Resource{
+		Id:   "infra",
+		Type: "github_repository",
+	})
+	for _, ty := range []string{"aws_unmanaged_resource", "aws_deleted_resource", "aws_diff_resource", "aws_no_diff_resource"} {
+		a.SetResourceTypeProvider(ty, "aws", "3.19.0")
+	}
+	a.SetResourceTypeProvider("github_repository", "github", "4.4.0")
+	a.ProviderName = "aws,github"
+	a.ProviderVersion = "3.19.0,4.4.0"
+	return a
+}
+
 func fakeAnalysisNoDrift() *analyser.Analysis {
 	a := analyser.Analysis{}
 	for i := 0; i < 5; i++ {
@@ -529,6 +548,42 @@ func TestGetPrinter(t *testing.T) {
 			key:  PlanOutputType,
 			want: &output.VoidPrinter{},
 		},
+		{
+			name: "sarif file output",
+			path: "/path/to/file",
+			key:  SARIFOutputType,
+			want: output.NewConsolePrinter(),
+		},
+		{
+			name: "sarif stdout output",
+			path: "stdout",
+			key:  SARIFOutputType,
+			want: &output.VoidPrinter{},
+		},
+		{
+			name: "junit file output",
+			path: "/path/to/file",
+			key:  JUnitOutputType,
+			want: output.NewConsolePrinter(),
+		},
+		{
+			name: "junit stdout output",
+			path: "stdout",
+			key:  JUnitOutputType,
+			want: &output.VoidPrinter{},
+		},
+		{
+			name: "markdown file output",
+			path: "/path/to/file",
+			key:  MarkdownOutputType,
+			want: output.NewConsolePrinter(),
+		},
+		{
+			name: "markdown stdout output",
+			path: "stdout",
+			key:  MarkdownOutputType,
+			want: &output.VoidPrinter{},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -0,0 +1,226 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cloudskiff/driftctl/pkg/analyser"
+	"github.com/cloudskiff/driftctl/pkg/resource"
+	"github.com/cloudskiff/driftctl/pkg/version"
+)
+
+const SARIFOutputType = "sarif"
+const SARIFOutputExample = "sarif://PATH/TO/FILE.sarif"
+
+const sarifVersion = "2.1.0"
+const sarifSchema = "https://raw.githubusercontent.com/oasis-tcs/sarif-spec/master/Schemata/sarif-schema-2.1.0.json"
+
+// Indexes of the rules in sarifRules, results refer to their rule using them
+const (
+	sarifUnmanagedRuleIndex = iota
+	sarifMissingRuleIndex
+	sarifChangedRuleIndex
+)
+
+// Each drift category is reported as a SARIF rule
+var sarifRules = []sarifRule{
+	{
+		Id:               "unmanaged-resource",
+		Name:             "UnmanagedResource",
+		ShortDescription: sarifMessage{Text: "Resource not covered by IaC"},
+		DefaultConfiguration: sarifRuleConfiguration{
+			Level: "warning",
+		},
+	},
+	{
+		Id:               "missing-resource",
+		Name:             "MissingResource",
+		ShortDescription: sarifMessage{Text: "Resource found in IaC but missing on cloud provider"},
+		DefaultConfiguration: sarifRuleConfiguration{
+			Level: "error",
+		},
+	},
+	{
+		Id:               "changed-resource",
+		Name:             "ChangedResource",
+		ShortDescription: sarifMessage{Text: "Resource changed on cloud provider"},
+		DefaultConfiguration: sarifRuleConfiguration{
+			Level: "error",
+		},
+	},
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationUri string      `json:"informationUri"`
+	Version        string      `json:"version"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	Id                   string                 `json:"id"`
+	Name                 string                 `json:"name"`
+	ShortDescription     sarifMessage           `json:"shortDescription"`
+	DefaultConfiguration sarifRuleConfiguration `json:"defaultConfiguration"`
+}
+
+type sarifRuleConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleId     string                 `json:"ruleId"`
+	RuleIndex  int                    `json:"ruleIndex"`
+	Level      string                 `json:"level"`
+	Message    sarifMessage           `json:"message"`
+	Locations  []sarifLocation        `json:"locations"`
+	Properties map[string]interface{} `json:"properties,omitempty"`
+}
+
+type sarifLocation struct {
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations"`
+}
+
+type sarifLogicalLocation struct {
+	Name               string `json:"name"`
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+type SARIF struct {
+	path string
+}
+
+func NewSARIF(path string) *SARIF {
+	return &SARIF{path}
+}
+
+func (c *SARIF) Write(analysis *analyser.Analysis) error {
+	file := os.Stdout
+	if !isStdOut(c.path) {
+		f, err := os.OpenFile(c.path, os.O_CREATE|os.O_RDWR|os.O_TRUNC, 0600)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		file = f
+	}
+
+	output := sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs: []sarifRun{
+			{
+				Tool: sarifTool{
+					Driver: sarifDriver{
+						Name:           "driftctl",
+						InformationUri: "https://driftctl.com",
+						Version:        version.Current(),
+						Rules:          sarifRules,
+					},
+				},
+				Results: sarifResults(analysis),
+			},
+		},
+	}
+
+	sarif, err := json.MarshalIndent(output, "", "\t")
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(sarif); err != nil {
+		return err
+	}
+	return nil
+}
+
+func sarifResults(analysis *analyser.Analysis) []sarifResult {
+	results := make([]sarifResult, 0, analysis.Summary().TotalUnmanaged+analysis.Summary().TotalDeleted+analysis.Summary().TotalDrifted)
+
+	for _, res := range analysis.Unmanaged() {
+		results = append(results, newSarifResult(
+			sarifUnmanagedRuleIndex,
+			res,
+			fmt.Sprintf("Resource %s (%s) is not covered by IaC", res.ResourceId(), res.ResourceType()),
+		))
+	}
+
+	for _, res := range analysis.Deleted() {
+		results = append(results, newSarifResult(
+			sarifMissingRuleIndex,
+			res,
+			fmt.Sprintf("Resource %s (%s) is missing on cloud provider", res.ResourceId(), res.ResourceType()),
+		))
+	}
+
+	for _, difference := range analysis.Differences() {
+		paths := make([]string, 0, len(difference.Changelog))
+		for _, change := range difference.Changelog {
+			paths = append(paths, strings.Join(change.Path, "."))
+		}
+		result := newSarifResult(
+			sarifChangedRuleIndex,
+			difference.Res,
+			fmt.Sprintf("Resource %s (%s) changed on cloud provider: %s", difference.Res.ResourceId(), difference.Res.ResourceType(), strings.Join(paths, ", ")),
+		)
+		result.Properties = map[string]interface{}{
+			"changelog": difference.Changelog,
+		}
+		results = append(results, result)
+	}
+
+	return results
+}
+
+func newSarifResult(ruleIndex int, res *resource.Resource, message string) sarifResult {
+	rule := sarifRules[ruleIndex]
+	return sarifResult{
+		RuleId:    rule.Id,
+		RuleIndex: ruleIndex,
+		Level:     rule.DefaultConfiguration.Level,
+		Message:   sarifMessage{Text: message},
+		Locations: []sarifLocation{
+			{
+				LogicalLocations: []sarifLogicalLocation{sarifResourceLocation(res)},
+			},
+		},
+	}
+}
+
+// sarifResourceLocation uses the Terraform address of the resource when it comes from a state,
+// resources without source are located using their type and id
+func sarifResourceLocation(res *resource.Resource) sarifLogicalLocation {
+	if _, isStateSource := res.Source.(*resource.TerraformStateSource); isStateSource {
+		return sarifLogicalLocation{
+			Name:               res.Source.InternalName(),
+			FullyQualifiedName: res.SourceString(),
+			Kind:               "resource",
+		}
+	}
+	return sarifLogicalLocation{
+		Name:               res.ResourceId(),
+		FullyQualifiedName: fmt.Sprintf("%s.%s", res.ResourceType(), res.ResourceId()),
+		Kind:               "resource",
+	}
+}
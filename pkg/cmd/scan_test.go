@@ -10,6 +10,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/cloudskiff/driftctl/pkg/iac/config"
+	"github.com/cloudskiff/driftctl/pkg/remote/aws"
 	"github.com/cloudskiff/driftctl/test"
 
 	"github.com/spf13/cobra"
@@ -42,16 +43,32 @@ func TestScanCmd_Valid(t *testing.T) {
 		{args: []string{"scan", "--to", "aws+tf", "--from", "tfstate://test"}},
 		{args: []string{"scan", "--to", "aws+tf", "--from", "tfstate+https://github.com/state.tfstate"}},
 		{args: []string{"scan", "--to", "aws+tf", "--from", "tfstate+tfcloud://workspace_id"}},
+		{args: []string{"scan", "--from", "tfstate://terraform.tfstate", "--from", "tfconfig://infra"}},
+		{args: []string{"scan", "--from", "tfplan://plan.json"}},
+		{args: []string{"scan", "--from", "tfplan+s3://bucket/plan.json"}},
+		{args: []string{"scan", "--from", "tfstate+gs://bucket/path/to/state.tfstate"}},
+		{args: []string{"scan", "--from", "tfstate+azurerm://account/container/path/to/state.tfstate"}},
+		{args: []string{"scan", "--from", "tfstate+consul://localhost:8500/states/*"}},
+		{args: []string{"scan", "--from", "tfstate+pg://localhost/terraform?workspace=*"}},
+		{args: []string{"scan", "--from", "tfstate+kubernetes://terraform/tfstate-default-infra"}},
+		{args: []string{"scan", "--from", "tfbackend://./infra"}},
+		{args: []string{"scan", "--from", "pulumi://stack.json", "--from", "cloudformation://stack-resources.json"}},
 		{args: []string{"scan", "--tfc-token", "token"}},
+		{args: []string{"scan", "--all-workspaces"}},
+		{args: []string{"scan", "--all-workspaces", "--workspace-key-prefix", "workspaces"}},
+		{args: []string{"scan", "--tofu-passphrase", "passphrase"}},
+		{args: []string{"scan", "--sops-age-key-file", "keys.txt", "--sops-pgp-key-file", "secring.asc"}},
 		{args: []string{"scan", "--filter", "Type=='aws_s3_bucket'"}},
 		{args: []string{"scan", "--strict"}},
 		{args: []string{"scan", "--deep"}},
+		{args: []string{"scan", "--report-ignored"}},
 		{args: []string{"scan", "--tf-provider-version", "1.2.3"}},
 		{args: []string{"scan", "--tf-provider-version", "3.30.2"}},
 		{args: []string{"scan", "--driftignore", "./path/to/driftignore.s3"}},
 		{args: []string{"scan", "--driftignore", ".driftignore"}},
 		{args: []string{"scan", "-o", "html://result.html", "-o", "json://result.json"}},
 		{args: []string{"scan", "--tf-lockfile", "../.terraform.lock.hcl"}},
+		{args: []string{"scan", "--tf-provider-mirror", "/opt/providers", "--tf-provider-mirror", "https://mirror.example.com/providers"}},
 	}
 
 	for _, tt := range cases {
@@ -77,17 +94,21 @@ func TestScanCmd_Invalid(t *testing.T) {
 		{args: []string{"scan", "-t", "glou"}, expected: "unsupported cloud provider 'glou'\nValid values are: aws+tf,github+tf,gcp+tf,azure+tf"},
 		{args: []string{"scan", "--to"}, expected: `flag needs an argument: --to`},
 		{args: []string{"scan", "--to", "glou"}, expected: "unsupported cloud provider 'glou'\nValid values are: aws+tf,github+tf,gcp+tf,azure+tf"},
+		{args: []string{"scan", "--to", "aws+tf,glou"}, expected: "unsupported cloud provider 'glou'\nValid values are: aws+tf,github+tf,gcp+tf,azure+tf"},
+		{args: []string{"scan", "--to", "aws+tf,github+tf", "--tf-provider-version", "3.41.0"}, expected: "--tf-provider-version can only be used to scan a single cloud provider, versions of several providers are read from the terraform lock file"},
+		{args: []string{"scan", "--to", "github+tf", "--aws-targets", "testdata/aws_targets.yml"}, expected: "--aws-targets can only be used to scan aws+tf"},
+		{args: []string{"scan", "--aws-targets", "testdata/missing_aws_targets.yml"}, expected: "unable to read AWS targets: open testdata/missing_aws_targets.yml: no such file or directory"},
 		{args: []string{"scan", "-f"}, expected: `flag needs an argument: 'f' in -f`},
 		{args: []string{"scan", "--from"}, expected: `flag needs an argument: --from`},
 		{args: []string{"scan", "--from"}, expected: `flag needs an argument: --from`},
-		{args: []string{"scan", "--from", "tosdgjhgsdhgkjs"}, expected: "Unable to parse from flag 'tosdgjhgsdhgkjs': \nAccepted schemes are: tfstate://,tfstate+s3://,tfstate+http://,tfstate+https://,tfstate+tfcloud://"},
-		{args: []string{"scan", "--from", "://"}, expected: "Unable to parse from flag '://': \nAccepted schemes are: tfstate://,tfstate+s3://,tfstate+http://,tfstate+https://,tfstate+tfcloud://"},
-		{args: []string{"scan", "--from", "://test"}, expected: "Unable to parse from flag '://test': \nAccepted schemes are: tfstate://,tfstate+s3://,tfstate+http://,tfstate+https://,tfstate+tfcloud://"},
-		{args: []string{"scan", "--from", "tosdgjhgsdhgkjs://"}, expected: "Unable to parse from flag 'tosdgjhgsdhgkjs://': \nAccepted schemes are: tfstate://,tfstate+s3://,tfstate+http://,tfstate+https://,tfstate+tfcloud://"},
-		{args: []string{"scan", "--from", "terraform+foo+bar://test"}, expected: "Unable to parse from scheme 'terraform+foo+bar': \nAccepted schemes are: tfstate://,tfstate+s3://,tfstate+http://,tfstate+https://,tfstate+tfcloud://"},
-		{args: []string{"scan", "--from", "unsupported://test"}, expected: "Unsupported IaC source 'unsupported': \nAccepted values are: tfstate"},
-		{args: []string{"scan", "--from", "tfstate+foobar://test"}, expected: "Unsupported IaC backend 'foobar': \nAccepted values are: s3,http,https,tfcloud"},
-		{args: []string{"scan", "--from", "tfstate:///tmp/test", "--from", "tfstate+toto://test"}, expected: "Unsupported IaC backend 'toto': \nAccepted values are: s3,http,https,tfcloud"},
+		{args: []string{"scan", "--from", "tosdgjhgsdhgkjs"}, expected: "Unable to parse from flag 'tosdgjhgsdhgkjs': \nAccepted schemes are: tfstate://,tfstate+s3://,tfstate+gs://,tfstate+azurerm://,tfstate+http://,tfstate+https://,tfstate+tfcloud://,tfstate+consul://,tfstate+pg://,tfstate+kubernetes://,tfbackend://,tfconfig://,tfplan://,tfplan+s3://,tfplan+gs://,tfplan+azurerm://,tfplan+http://,tfplan+https://,pulumi://,pulumi+s3://,pulumi+gs://,pulumi+azurerm://,pulumi+http://,pulumi+https://,cloudformation://,cloudformation+s3://,cloudformation+gs://,cloudformation+azurerm://,cloudformation+http://,cloudformation+https://"},
+		{args: []string{"scan", "--from", "://"}, expected: "Unable to parse from flag '://': \nAccepted schemes are: tfstate://,tfstate+s3://,tfstate+gs://,tfstate+azurerm://,tfstate+http://,tfstate+https://,tfstate+tfcloud://,tfstate+consul://,tfstate+pg://,tfstate+kubernetes://,tfbackend://,tfconfig://,tfplan://,tfplan+s3://,tfplan+gs://,tfplan+azurerm://,tfplan+http://,tfplan+https://,pulumi://,pulumi+s3://,pulumi+gs://,pulumi+azurerm://,pulumi+http://,pulumi+https://,cloudformation://,cloudformation+s3://,cloudformation+gs://,cloudformation+azurerm://,cloudformation+http://,cloudformation+https://"},
+		{args: []string{"scan", "--from", "://test"}, expected: "Unable to parse from flag '://test': \nAccepted schemes are: tfstate://,tfstate+s3://,tfstate+gs://,tfstate+azurerm://,tfstate+http://,tfstate+https://,tfstate+tfcloud://,tfstate+consul://,tfstate+pg://,tfstate+kubernetes://,tfbackend://,tfconfig://,tfplan://,tfplan+s3://,tfplan+gs://,tfplan+azurerm://,tfplan+http://,tfplan+https://,pulumi://,pulumi+s3://,pulumi+gs://,pulumi+azurerm://,pulumi+http://,pulumi+https://,cloudformation://,cloudformation+s3://,cloudformation+gs://,cloudformation+azurerm://,cloudformation+http://,cloudformation+https://"},
+		{args: []string{"scan", "--from", "tosdgjhgsdhgkjs://"}, expected: "Unable to parse from flag 'tosdgjhgsdhgkjs://': \nAccepted schemes are: tfstate://,tfstate+s3://,tfstate+gs://,tfstate+azurerm://,tfstate+http://,tfstate+https://,tfstate+tfcloud://,tfstate+consul://,tfstate+pg://,tfstate+kubernetes://,tfbackend://,tfconfig://,tfplan://,tfplan+s3://,tfplan+gs://,tfplan+azurerm://,tfplan+http://,tfplan+https://,pulumi://,pulumi+s3://,pulumi+gs://,pulumi+azurerm://,pulumi+http://,pulumi+https://,cloudformation://,cloudformation+s3://,cloudformation+gs://,cloudformation+azurerm://,cloudformation+http://,cloudformation+https://"},
+		{args: []string{"scan", "--from", "terraform+foo+bar://test"}, expected: "Unable to parse from scheme 'terraform+foo+bar': \nAccepted schemes are: tfstate://,tfstate+s3://,tfstate+gs://,tfstate+azurerm://,tfstate+http://,tfstate+https://,tfstate+tfcloud://,tfstate+consul://,tfstate+pg://,tfstate+kubernetes://,tfbackend://,tfconfig://,tfplan://,tfplan+s3://,tfplan+gs://,tfplan+azurerm://,tfplan+http://,tfplan+https://,pulumi://,pulumi+s3://,pulumi+gs://,pulumi+azurerm://,pulumi+http://,pulumi+https://,cloudformation://,cloudformation+s3://,cloudformation+gs://,cloudformation+azurerm://,cloudformation+http://,cloudformation+https://"},
+		{args: []string{"scan", "--from", "unsupported://test"}, expected: "Unsupported IaC source 'unsupported': \nAccepted values are: tfstate,tfbackend,tfconfig,tfplan,pulumi,cloudformation"},
+		{args: []string{"scan", "--from", "tfstate+foobar://test"}, expected: "Unsupported IaC backend 'foobar': \nAccepted values are: s3,gs,azurerm,http,https,tfcloud,consul,pg,kubernetes"},
+		{args: []string{"scan", "--from", "tfstate:///tmp/test", "--from", "tfstate+toto://test"}, expected: "Unsupported IaC backend 'toto': \nAccepted values are: s3,gs,azurerm,http,https,tfcloud,consul,pg,kubernetes"},
 		{args: []string{"scan", "--filter", "Type='test'"}, expected: "unable to parse filter expression: SyntaxError: Expected tRbracket, received: tUnknown"},
 		{args: []string{"scan", "--filter", "Type='test'", "--filter", "Type='test2'"}, expected: "Filter flag should be specified only once"},
 		{args: []string{"scan", "--tf-provider-version", ".30.2"}, expected: "Invalid version argument .30.2, expected a valid semver string (e.g. 2.13.4)"},
@@ -183,7 +204,7 @@ func Test_parseOutputFlag(t *testing.T) {
 				out: []string{""},
 			},
 			want: []output.OutputConfig{},
-			err:  fmt.Errorf("Unable to parse output flag '': \nAccepted formats are: console://,html://PATH/TO/FILE.html,json://PATH/TO/FILE.json,plan://PATH/TO/FILE.json"),
+			err:  fmt.Errorf("Unable to parse output flag '': \nAccepted formats are: console://,html://PATH/TO/FILE.html,json://PATH/TO/FILE.json,junit://PATH/TO/FILE.xml,markdown://PATH/TO/FILE.md,plan://PATH/TO/FILE.json,sarif://PATH/TO/FILE.sarif"),
 		},
 		{
 			name: "test empty array",
@@ -199,7 +220,7 @@ func Test_parseOutputFlag(t *testing.T) {
 				out: []string{"sdgjsdgjsdg"},
 			},
 			want: []output.OutputConfig{},
-			err:  fmt.Errorf("Unable to parse output flag 'sdgjsdgjsdg': \nAccepted formats are: console://,html://PATH/TO/FILE.html,json://PATH/TO/FILE.json,plan://PATH/TO/FILE.json"),
+			err:  fmt.Errorf("Unable to parse output flag 'sdgjsdgjsdg': \nAccepted formats are: console://,html://PATH/TO/FILE.html,json://PATH/TO/FILE.json,junit://PATH/TO/FILE.xml,markdown://PATH/TO/FILE.md,plan://PATH/TO/FILE.json,sarif://PATH/TO/FILE.sarif"),
 		},
 		{
 			name: "test invalid",
@@ -207,7 +228,7 @@ func Test_parseOutputFlag(t *testing.T) {
 				out: []string{"://"},
 			},
 			want: []output.OutputConfig{},
-			err:  fmt.Errorf("Unable to parse output flag '://': \nAccepted formats are: console://,html://PATH/TO/FILE.html,json://PATH/TO/FILE.json,plan://PATH/TO/FILE.json"),
+			err:  fmt.Errorf("Unable to parse output flag '://': \nAccepted formats are: console://,html://PATH/TO/FILE.html,json://PATH/TO/FILE.json,junit://PATH/TO/FILE.xml,markdown://PATH/TO/FILE.md,plan://PATH/TO/FILE.json,sarif://PATH/TO/FILE.sarif"),
 		},
 		{
 			name: "test unsupported",
@@ -215,7 +236,7 @@ func Test_parseOutputFlag(t *testing.T) {
 				out: []string{"foobar://"},
 			},
 			want: []output.OutputConfig{},
-			err:  fmt.Errorf("Unsupported output 'foobar': \nValid formats are: console://,html://PATH/TO/FILE.html,json://PATH/TO/FILE.json,plan://PATH/TO/FILE.json"),
+			err:  fmt.Errorf("Unsupported output 'foobar': \nValid formats are: console://,html://PATH/TO/FILE.html,json://PATH/TO/FILE.json,junit://PATH/TO/FILE.xml,markdown://PATH/TO/FILE.md,plan://PATH/TO/FILE.json,sarif://PATH/TO/FILE.sarif"),
 		},
 		{
 			name: "test empty json",
@@ -271,6 +292,69 @@ func Test_parseOutputFlag(t *testing.T) {
 			},
 			err: nil,
 		},
+		{
+			name: "test empty sarif",
+			args: args{
+				out: []string{"sarif://"},
+			},
+			want: []output.OutputConfig{},
+			err:  fmt.Errorf("Invalid sarif output 'sarif://': \nMust be of kind: sarif://PATH/TO/FILE.sarif"),
+		},
+		{
+			name: "test valid sarif",
+			args: args{
+				out: []string{"sarif:///tmp/foobar.sarif"},
+			},
+			want: []output.OutputConfig{
+				{
+					Key:  "sarif",
+					Path: "/tmp/foobar.sarif",
+				},
+			},
+			err: nil,
+		},
+		{
+			name: "test empty junit",
+			args: args{
+				out: []string{"junit://"},
+			},
+			want: []output.OutputConfig{},
+			err:  fmt.Errorf("Invalid junit output 'junit://': \nMust be of kind: junit://PATH/TO/FILE.xml"),
+		},
+		{
+			name: "test valid junit",
+			args: args{
+				out: []string{"junit:///tmp/foobar.xml"},
+			},
+			want: []output.OutputConfig{
+				{
+					Key:  "junit",
+					Path: "/tmp/foobar.xml",
+				},
+			},
+			err: nil,
+		},
+		{
+			name: "test empty markdown",
+			args: args{
+				out: []string{"markdown://"},
+			},
+			want: []output.OutputConfig{},
+			err:  fmt.Errorf("Invalid markdown output 'markdown://': \nMust be of kind: markdown://PATH/TO/FILE.md"),
+		},
+		{
+			name: "test valid markdown",
+			args: args{
+				out: []string{"markdown:///tmp/foobar.md"},
+			},
+			want: []output.OutputConfig{
+				{
+					Key:  "markdown",
+					Path: "/tmp/foobar.md",
+				},
+			},
+			err: nil,
+		},
 		{
 			name: "test multiple output values",
 			args: args{
@@ -297,7 +381,7 @@ func Test_parseOutputFlag(t *testing.T) {
 					Key: "console",
 				},
 			},
-			err: fmt.Errorf("Unsupported output 'invalid': \nValid formats are: console://,html://PATH/TO/FILE.html,json://PATH/TO/FILE.json,plan://PATH/TO/FILE.json"),
+			err: fmt.Errorf("Unsupported output 'invalid': \nValid formats are: console://,html://PATH/TO/FILE.html,json://PATH/TO/FILE.json,junit://PATH/TO/FILE.xml,markdown://PATH/TO/FILE.md,plan://PATH/TO/FILE.json,sarif://PATH/TO/FILE.sarif"),
 		},
 		{
 			name: "test multiple valid output values",
@@ -344,28 +428,74 @@ func Test_Options(t *testing.T) {
 			name: "lockfile should be ignored by tf-provider-version flag",
 			args: []string{"scan", "--to", "aws+tf", "--tf-lockfile", "testdata/terraform_valid.lock.hcl", "--tf-provider-version", "3.41.0"},
 			assertOptions: func(t *testing.T, opts *pkg.ScanOptions) {
-				assert.Equal(t, "3.41.0", opts.ProviderVersion)
+				assert.Equal(t, "3.41.0", opts.ProviderVersions["aws+tf"])
 			},
 		},
 		{
 			name: "should get provider version from lockfile",
 			args: []string{"scan", "--to", "aws+tf", "--tf-lockfile", "testdata/terraform_valid.lock.hcl"},
 			assertOptions: func(t *testing.T, opts *pkg.ScanOptions) {
-				assert.Equal(t, "3.47.0", opts.ProviderVersion)
+				assert.Equal(t, "3.47.0", opts.ProviderVersions["aws+tf"])
 			},
 		},
 		{
 			name: "should not find provider version in lockfile",
 			args: []string{"scan", "--to", "gcp+tf", "--tf-lockfile", "testdata/terraform_valid.lock.hcl"},
 			assertOptions: func(t *testing.T, opts *pkg.ScanOptions) {
-				assert.Equal(t, "", opts.ProviderVersion)
+				assert.Equal(t, "", opts.ProviderVersions["gcp+tf"])
+			},
+		},
+		{
+			name: "should get provider versions of several cloud providers",
+			args: []string{"scan", "--to", "aws+tf,gcp+tf", "--tf-lockfile", "testdata/terraform_valid.lock.hcl"},
+			assertOptions: func(t *testing.T, opts *pkg.ScanOptions) {
+				assert.Equal(t, []string{"aws+tf", "gcp+tf"}, opts.To)
+				assert.Equal(t, map[string]string{"aws+tf": "3.47.0"}, opts.ProviderVersions)
+			},
+		},
+		{
+			name: "should scan cloud providers given with repeated flags",
+			args: []string{"scan", "-t", "aws+tf", "-t", "azure+tf"},
+			assertOptions: func(t *testing.T, opts *pkg.ScanOptions) {
+				assert.Equal(t, []string{"aws+tf", "azure+tf"}, opts.To)
+			},
+		},
+		{
+			name: "should scan once a cloud provider given twice",
+			args: []string{"scan", "--to", "aws+tf,github+tf,aws+tf"},
+			assertOptions: func(t *testing.T, opts *pkg.ScanOptions) {
+				assert.Equal(t, []string{"aws+tf", "github+tf"}, opts.To)
+			},
+		},
+		{
+			name: "should read aws targets",
+			args: []string{"scan", "--to", "aws+tf", "--aws-targets", "testdata/aws_targets.yml"},
+			assertOptions: func(t *testing.T, opts *pkg.ScanOptions) {
+				assert.Equal(t, []aws.Target{
+					{Profile: "production", Regions: []string{"us-east-1", "eu-west-3"}},
+					{RoleARN: "arn:aws:iam::123456789012:role/driftctl", Regions: []string{"us-east-1"}},
+				}, opts.AWSTargets)
+			},
+		},
+		{
+			name: "should merge driftignore files after the global one",
+			args: []string{"scan", "--driftignore-global", "/etc/driftctl/driftignore", "--driftignore", "../.driftignore", "--driftignore", ".driftignore"},
+			assertOptions: func(t *testing.T, opts *pkg.ScanOptions) {
+				assert.Equal(t, []string{"/etc/driftctl/driftignore", "../.driftignore", ".driftignore"}, opts.DriftignorePaths)
+			},
+		},
+		{
+			name: "should default to the driftignore file of the current directory",
+			args: []string{"scan"},
+			assertOptions: func(t *testing.T, opts *pkg.ScanOptions) {
+				assert.Equal(t, []string{".driftignore"}, opts.DriftignorePaths)
 			},
 		},
 		{
 			name: "should fail to read lockfile with silent error",
 			args: []string{"scan", "--to", "gcp+tf", "--tf-lockfile", "testdata/terraform_invalid.lock.hcl"},
 			assertOptions: func(t *testing.T, opts *pkg.ScanOptions) {
-				assert.Equal(t, "", opts.ProviderVersion)
+				assert.Equal(t, "", opts.ProviderVersions["gcp+tf"])
 			},
 		},
 	}
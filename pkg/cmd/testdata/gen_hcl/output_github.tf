@@ -0,0 +1,26 @@
+resource "github_branch_protection" "BPR_xxx" {
+  enforce_admins         = true
+  pattern                = "main"
+  repository_id          = "MDEwOlJlcG9zaXRvcnkxMjM0NTY3OA=="
+  require_signed_commits = false
+  required_pull_request_reviews {
+    dismiss_stale_reviews           = true
+    required_approving_review_count = 2
+  }
+  required_status_checks {
+    contexts = ["ci/build", "ci/test"]
+    strict   = true
+  }
+}
+
+resource "github_repository" "driftctl-demo" {
+  has_issues = true
+  name       = "driftctl-demo"
+  topics     = ["demo"]
+  visibility = "public"
+}
+
+# Unable to generate configuration for without-attributes (github_repository): no attributes found, make sure the scan was run with --deep
+
+# Unable to generate configuration for unknown (github_unknown_type): no schema found for this resource type
+
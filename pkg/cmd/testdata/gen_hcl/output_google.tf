@@ -0,0 +1,10 @@
+resource "google_storage_bucket_iam_policy" "b_driftctl" {
+  bucket = "b/driftctl"
+  policy_data = jsonencode({
+    bindings = [{
+      members = ["user:foo@example.com"]
+      role    = "roles/storage.admin"
+    }]
+  })
+}
+
@@ -0,0 +1,25 @@
+import {
+  to = aws_api_gateway_method.agm-foo-bar-GET
+  id = "foo/bar/GET"
+}
+
+import {
+  to = aws_iam_role_policy.driftctl_assume_role_driftctl_policy_10
+  id = "driftctl_assume_role:driftctl_policy.10"
+}
+
+import {
+  to = aws_iam_role_policy_attachment.policy-role
+  id = "role/arn:aws:iam::123456789012:policy/policy"
+}
+
+import {
+  to = aws_iam_user.driftctl
+  id = "driftctl"
+}
+
+import {
+  to = aws_s3_bucket.r_123-test_bucket
+  id = "123-test'bucket"
+}
+
@@ -0,0 +1,6 @@
+#!/bin/sh
+terraform import 'aws_api_gateway_method.agm-foo-bar-GET' 'foo/bar/GET'
+terraform import 'aws_iam_role_policy.driftctl_assume_role_driftctl_policy_10' 'driftctl_assume_role:driftctl_policy.10'
+terraform import 'aws_iam_role_policy_attachment.policy-role' 'role/arn:aws:iam::123456789012:policy/policy'
+terraform import 'aws_iam_user.driftctl' 'driftctl'
+terraform import 'aws_s3_bucket.r_123-test_bucket' '123-test'\''bucket'
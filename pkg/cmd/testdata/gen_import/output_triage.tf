@@ -0,0 +1,5 @@
+import {
+  to = aws_iam_user.driftctl
+  id = "driftctl"
+}
+
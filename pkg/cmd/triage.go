@@ -0,0 +1,706 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/pkg/errors"
+	"github.com/r3labs/diff/v2"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"github.com/cloudskiff/driftctl/pkg/analyser"
+	"github.com/cloudskiff/driftctl/pkg/filter"
+	"github.com/cloudskiff/driftctl/pkg/resource"
+)
+
+const (
+	triageKindUnmanaged = "unmanaged"
+	triageKindMissing   = "missing"
+	triageKindChanged   = "changed"
+
+	triageDecisionIgnore      = "ignore"
+	triageDecisionImport      = "import"
+	triageDecisionInvestigate = "investigate"
+)
+
+const triageHelp = `Commands:
+  list                            list the resources matching the filter, with their number
+  types, sources, tags            count the resources matching the filter by type, IaC source or tag,
+                                  tags are only known for unmanaged resources of a scan in deep mode
+  filter [<key>=<value> ...]      filter resources by kind, type, source, tag (tag=Key or tag=Key=Value) or decision (none for undecided ones),
+                                  clear the filter when no argument is given
+  show <n>                        show a resource with its changes
+  ignore <items> [comment]        mark resources to be written to the driftignore file with a comment
+  import <items>                  queue unmanaged resources for import generation, see gen-import --triage-session
+  investigate <items> [comment]   mark resources to investigate
+  reset <items>                   forget the decision made on resources
+  save                            write ignored resources to the driftignore file and save the session
+  quit                            save and quit
+<items> are numbers from the list, ranges like 1-5, comma separated lists of them or all
+`
+
+type TriageOptions struct {
+	InputPath       string
+	SessionPath     string
+	DriftignorePath string
+}
+
+// triageDecision is the decision made on a resource of a scan result
+type triageDecision struct {
+	Kind     string `json:"kind"`
+	Type     string `json:"type"`
+	Id       string `json:"id"`
+	Scope    string `json:"scope,omitempty"`
+	Decision string `json:"decision"`
+	Comment  string `json:"comment,omitempty"`
+	Date     string `json:"date"`
+	// Written is true once an ignore decision was written to the driftignore file
+	Written bool `json:"written,omitempty"`
+}
+
+func (d *triageDecision) key() string {
+	return strings.Join([]string{d.Kind, d.Type, d.Id, d.Scope}, "|")
+}
+
+// triageSession holds the decisions made so far, triage resumes from it
+type triageSession struct {
+	Decisions []*triageDecision `json:"decisions"`
+}
+
+type triageItem struct {
+	Kind      string
+	Res       *resource.Resource
+	Changelog analyser.Changelog
+}
+
+func (i *triageItem) key() string {
+	scope := ""
+	if i.Res.Scope != nil {
+		scope = i.Res.Scope.String()
+	}
+	return strings.Join([]string{i.Kind, i.Res.ResourceType(), i.Res.ResourceId(), scope}, "|")
+}
+
+func (i *triageItem) source() string {
+	if i.Res.Src() == nil {
+		return ""
+	}
+	return i.Res.Src().Source()
+}
+
+// tags reads the tags of AWS and Azure resources or the labels of Google ones, they are only
+// known for resources serialized with their attributes
+func (i *triageItem) tags() map[string]string {
+	tags := make(map[string]string)
+	if i.Res.Attributes() == nil {
+		return tags
+	}
+	for _, field := range []string{"tags", "labels"} {
+		values, ok := (*i.Res.Attributes())[field].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for k, v := range values {
+			tags[k] = fmt.Sprintf("%v", v)
+		}
+	}
+	return tags
+}
+
+func NewTriageCmd() *cobra.Command {
+	opts := &TriageOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "triage",
+		Short: "Browse a scan result and decide what to do with each drift",
+		Long: "This command will let you browse the unmanaged, missing and changed resources of a scan result by kind, type, IaC source and tag " +
+			"in a terminal UI, show their changes and mark them to be ignored, imported or investigated\n\n" +
+			"Ignored resources are written to the driftignore file with a comment, resources to import are generated by gen-import --triage-session. " +
+			"Decisions are saved in a session file so that triage can be resumed later\n\n" +
+			"Tags are only known for unmanaged resources of a scan in deep mode, " +
+			"managed, missing and changed resources are written to scan results without their attributes\n\n" +
+			"When the input is not a terminal, commands are read line by line instead, type help to list them\n\n" +
+			"Example: driftctl scan --deep -o json://scan.json && driftctl triage -i scan.json",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			in, isInFile := cmd.InOrStdin().(*os.File)
+			out, isOutFile := cmd.OutOrStdout().(*os.File)
+			if isInFile && isOutFile && term.IsTerminal(int(in.Fd())) && term.IsTerminal(int(out.Fd())) {
+				return runTriageUI(in, out, opts, time.Now())
+			}
+			return runTriage(cmd.InOrStdin(), cmd.OutOrStdout(), opts, time.Now())
+		},
+	}
+
+	fl := cmd.Flags()
+	fl.StringVarP(&opts.InputPath, "input", "i", "", "Scan result to triage, in JSON. Tags are only known for unmanaged resources of a scan in deep mode.")
+	fl.StringVar(&opts.SessionPath, "session", ".driftctl-triage.json", "Session file the decisions are saved to and resumed from")
+	fl.StringVar(&opts.DriftignorePath, "driftignore", ".driftignore", "Driftignore file ignored resources are written to")
+	_ = cmd.MarkFlagRequired("input")
+
+	return cmd
+}
+
+type triage struct {
+	out       io.Writer
+	opts      *TriageOptions
+	now       time.Time
+	items     []*triageItem
+	session   *triageSession
+	decisions map[string]*triageDecision
+	// filters are the values resources have to match by key, e.g. type or tag
+	filters map[string]string
+}
+
+// newTriage loads the scan result to triage and resumes the decisions of the session
+func newTriage(out io.Writer, opts *TriageOptions, now time.Time) (*triage, error) {
+	analysis, err := readAnalysis(opts.InputPath)
+	if err != nil {
+		return nil, err
+	}
+	session, err := readTriageSession(opts.SessionPath)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &triage{
+		out:       out,
+		opts:      opts,
+		now:       now,
+		items:     triageItems(analysis),
+		session:   session,
+		decisions: make(map[string]*triageDecision, len(session.Decisions)),
+		filters:   make(map[string]string),
+	}
+	for _, decision := range session.Decisions {
+		t.decisions[decision.key()] = decision
+	}
+	return t, nil
+}
+
+// runTriage reads commands line by line, it is used when the input is not a terminal
+func runTriage(in io.Reader, out io.Writer, opts *TriageOptions, now time.Time) error {
+	t, err := newTriage(out, opts, now)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "%d resource(s) to triage, %d already decided. Type help to list commands.\n", len(t.items), t.decidedCount())
+	scanner := bufio.NewScanner(in)
+	for {
+		fmt.Fprint(out, "triage> ")
+		if !scanner.Scan() {
+			fmt.Fprintln(out)
+			break
+		}
+		quit, err := t.run(strings.Fields(scanner.Text()))
+		if err != nil {
+			fmt.Fprintf(out, "%s\n", color.RedString(err.Error()))
+		}
+		if quit {
+			break
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return t.save()
+}
+
+func triageItems(analysis *analyser.Analysis) []*triageItem {
+	items := make([]*triageItem, 0, len(analysis.Unmanaged())+len(analysis.Deleted())+len(analysis.Differences()))
+	for _, res := range analysis.Unmanaged() {
+		items = append(items, &triageItem{Kind: triageKindUnmanaged, Res: res})
+	}
+	for _, res := range analysis.Deleted() {
+		items = append(items, &triageItem{Kind: triageKindMissing, Res: res})
+	}
+	for _, difference := range analysis.Differences() {
+		items = append(items, &triageItem{Kind: triageKindChanged, Res: difference.Res, Changelog: difference.Changelog})
+	}
+	return items
+}
+
+func readTriageSession(path string) (*triageSession, error) {
+	session := &triageSession{}
+	content, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return session, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read triage session")
+	}
+	if err := json.Unmarshal(content, session); err != nil {
+		return nil, errors.Wrapf(err, "unable to parse triage session %s", path)
+	}
+	return session, nil
+}
+
+// triageImports returns the unmanaged resources queued for import in a triage session
+func triageImports(path string, unmanaged []*resource.Resource) ([]*resource.Resource, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, errors.Wrap(err, "unable to read triage session")
+	}
+	session, err := readTriageSession(path)
+	if err != nil {
+		return nil, err
+	}
+	imports := make(map[string]struct{})
+	for _, decision := range session.Decisions {
+		if decision.Decision == triageDecisionImport {
+			imports[decision.key()] = struct{}{}
+		}
+	}
+	resources := make([]*resource.Resource, 0, len(imports))
+	for _, res := range unmanaged {
+		item := &triageItem{Kind: triageKindUnmanaged, Res: res}
+		if _, exists := imports[item.key()]; exists {
+			resources = append(resources, res)
+		}
+	}
+	return resources, nil
+}
+
+// run executes a command and returns true when triage is over
+func (t *triage) run(args []string) (bool, error) {
+	if len(args) == 0 {
+		return false, nil
+	}
+	switch args[0] {
+	case "help":
+		fmt.Fprint(t.out, triageHelp)
+	case "list", "ls":
+		t.list()
+	case "types", "sources", "tags":
+		t.count(strings.TrimSuffix(args[0], "s"))
+	case "filter":
+		return false, t.filter(args[1:])
+	case "show":
+		return false, t.show(args[1:])
+	case triageDecisionIgnore, triageDecisionImport, triageDecisionInvestigate, "reset":
+		return false, t.decide(args[0], args[1:])
+	case "save":
+		if err := t.save(); err != nil {
+			return false, err
+		}
+		fmt.Fprintf(t.out, "Saved session to %s\n", t.opts.SessionPath)
+	case "quit", "exit", "q":
+		return true, nil
+	default:
+		return false, errors.Errorf("unknown command %s, type help to list commands", args[0])
+	}
+	return false, nil
+}
+
+// filtered returns the items matching the filter, numbers of items are their position in that list
+func (t *triage) filtered() []*triageItem {
+	items := make([]*triageItem, 0, len(t.items))
+	for _, item := range t.items {
+		if t.matches(item) {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func (t *triage) matches(item *triageItem) bool {
+	for key, value := range t.filters {
+		switch key {
+		case "kind":
+			if item.Kind != value {
+				return false
+			}
+		case "type":
+			if item.Res.ResourceType() != value {
+				return false
+			}
+		case "source":
+			if item.source() != value {
+				return false
+			}
+		case "decision":
+			decision := "none"
+			if d, exists := t.decisions[item.key()]; exists {
+				decision = d.Decision
+			}
+			if decision != value {
+				return false
+			}
+		case "tag":
+			parts := strings.SplitN(value, "=", 2)
+			tag, exists := item.tags()[parts[0]]
+			if !exists || (len(parts) == 2 && tag != parts[1]) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (t *triage) filter(args []string) error {
+	filters := make(map[string]string, len(args))
+	for _, arg := range args {
+		parts := strings.SplitN(arg, "=", 2)
+		if len(parts) != 2 {
+			return errors.Errorf("invalid filter %s, expected key=value", arg)
+		}
+		switch parts[0] {
+		case "kind", "type", "source", "tag", "decision":
+			filters[parts[0]] = parts[1]
+		default:
+			return errors.Errorf("unknown filter %s, valid keys are kind, type, source, tag and decision", parts[0])
+		}
+	}
+	t.filters = filters
+	fmt.Fprintf(t.out, "%d resource(s) match the filter\n", len(t.filtered()))
+	return nil
+}
+
+func (t *triage) list() {
+	items := t.filtered()
+	if len(items) == 0 {
+		fmt.Fprintln(t.out, "No resource matches the filter")
+		return
+	}
+	for i, item := range items {
+		fmt.Fprintf(t.out, "%4d  %s\n", i+1, t.describeShort(item))
+	}
+}
+
+// describeShort describes an item on a single line with the decision made on it
+func (t *triage) describeShort(item *triageItem) string {
+	line := fmt.Sprintf("%-9s  %s.%s", t.colorKind(item.Kind), item.Res.ResourceType(), item.Res.ResourceId())
+	if item.Res.Scope != nil {
+		line = fmt.Sprintf("%s (%s)", line, item.Res.Scope)
+	}
+	if decision, exists := t.decisions[item.key()]; exists {
+		line = fmt.Sprintf("%s  [%s]", line, decision.Decision)
+	}
+	return line
+}
+
+func (t *triage) colorKind(kind string) string {
+	switch kind {
+	case triageKindUnmanaged:
+		return color.YellowString("%-9s", kind)
+	case triageKindMissing:
+		return color.RedString("%-9s", kind)
+	}
+	return color.CyanString("%-9s", kind)
+}
+
+// values returns the values of an item for one of the keys resources are browsed by
+func (i *triageItem) values(key string) []string {
+	switch key {
+	case "kind":
+		return []string{i.Kind}
+	case "type":
+		return []string{i.Res.ResourceType()}
+	case "source":
+		return []string{i.source()}
+	}
+	tags := make([]string, 0)
+	for k, v := range i.tags() {
+		tags = append(tags, fmt.Sprintf("%s=%s", k, v))
+	}
+	return tags
+}
+
+// count lists the values of the items matching the filter with the number of items having them
+func (t *triage) count(key string) {
+	counts := make(map[string]int)
+	for _, item := range t.filtered() {
+		for _, value := range item.values(key) {
+			if value == "" {
+				value = "(none)"
+			}
+			counts[value]++
+		}
+	}
+	keys := make([]string, 0, len(counts))
+	for k := range counts {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(t.out, "%5d  %s\n", counts[k], k)
+	}
+}
+
+func (t *triage) show(args []string) error {
+	items := t.filtered()
+	if len(args) != 1 {
+		return errors.New("show expects the number of a resource")
+	}
+	selected, err := selectTriageItems(items, args[0])
+	if err != nil {
+		return err
+	}
+	for _, item := range selected {
+		t.describe(t.out, item)
+	}
+	return nil
+}
+
+// describe writes an item with its tags, the decision made on it and its changes
+func (t *triage) describe(w io.Writer, item *triageItem) {
+	fmt.Fprintf(w, "%s.%s (%s)\n", item.Res.ResourceType(), item.Res.ResourceId(), item.Kind)
+	if item.source() != "" {
+		fmt.Fprintf(w, "  source: %s\n", item.Res.SourceString())
+	}
+	if item.Res.Scope != nil {
+		fmt.Fprintf(w, "  scope: %s\n", item.Res.Scope)
+	}
+	tags := item.tags()
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(w, "  tag %s: %s\n", k, tags[k])
+	}
+	if decision, exists := t.decisions[item.key()]; exists {
+		fmt.Fprintf(w, "  decision: %s", decision.Decision)
+		if decision.Comment != "" {
+			fmt.Fprintf(w, " (%s)", decision.Comment)
+		}
+		fmt.Fprintln(w)
+	}
+	for _, change := range item.Changelog {
+		fmt.Fprintf(w, "  %s\n", formatTriageChange(change))
+	}
+}
+
+func formatTriageChange(change analyser.Change) string {
+	path := strings.Join(change.Path, ".")
+	line := ""
+	switch change.Type {
+	case diff.CREATE:
+		line = color.GreenString("+ %s: %s", path, formatTriageValue(change.To))
+	case diff.DELETE:
+		line = color.RedString("- %s: %s", path, formatTriageValue(change.From))
+	default:
+		line = color.YellowString("~ %s: %s => %s", path, formatTriageValue(change.From), formatTriageValue(change.To))
+	}
+	if change.Computed {
+		line = fmt.Sprintf("%s (computed)", line)
+	}
+	return line
+}
+
+func formatTriageValue(value interface{}) string {
+	if str, ok := value.(string); ok {
+		return fmt.Sprintf("%q", str)
+	}
+	out, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(out)
+}
+
+// decide records a decision, or forgets it with reset, for the selected items
+func (t *triage) decide(decision string, args []string) error {
+	if len(args) == 0 {
+		return errors.Errorf("%s expects the numbers of resources", decision)
+	}
+	selected, err := selectTriageItems(t.filtered(), args[0])
+	if err != nil {
+		return err
+	}
+	comment := strings.Join(args[1:], " ")
+
+	decided, skipped := 0, 0
+	for _, item := range selected {
+		existing, exists := t.decisions[item.key()]
+		if exists && existing.Written && decision != triageDecisionIgnore {
+			fmt.Fprintf(t.out, "%s.%s is already written to %s, remove it from there to stop ignoring it\n", item.Res.ResourceType(), item.Res.ResourceId(), t.opts.DriftignorePath)
+		}
+		if decision == "reset" {
+			if exists {
+				delete(t.decisions, item.key())
+				decided++
+			}
+			continue
+		}
+		if decision == triageDecisionImport && item.Kind != triageKindUnmanaged {
+			skipped++
+			continue
+		}
+		d := &triageDecision{
+			Kind:     item.Kind,
+			Type:     item.Res.ResourceType(),
+			Id:       item.Res.ResourceId(),
+			Decision: decision,
+			Comment:  comment,
+			Date:     t.now.Format(time.RFC3339),
+		}
+		if item.Res.Scope != nil {
+			d.Scope = item.Res.Scope.String()
+		}
+		if exists && existing.Written && decision == triageDecisionIgnore {
+			d.Written = true
+		}
+		t.decisions[item.key()] = d
+		decided++
+	}
+	if skipped > 0 {
+		fmt.Fprintf(t.out, "Skipped %d resource(s), only unmanaged resources can be imported\n", skipped)
+	}
+	if decided == 0 {
+		return nil
+	}
+	if decision == "reset" {
+		fmt.Fprintf(t.out, "Forgot the decision made on %d resource(s)\n", decided)
+	} else {
+		fmt.Fprintf(t.out, "Marked %d resource(s) to %s\n", decided, decision)
+	}
+	return t.saveSession()
+}
+
+// selectTriageItems returns the items designated by numbers, ranges or all
+func selectTriageItems(items []*triageItem, selection string) ([]*triageItem, error) {
+	if selection == "all" {
+		return items, nil
+	}
+	selected := make([]*triageItem, 0)
+	for _, part := range strings.Split(selection, ",") {
+		bounds := strings.SplitN(part, "-", 2)
+		from, err := strconv.Atoi(bounds[0])
+		if err != nil {
+			return nil, errors.Errorf("invalid resource number %s", part)
+		}
+		to := from
+		if len(bounds) == 2 {
+			if to, err = strconv.Atoi(bounds[1]); err != nil {
+				return nil, errors.Errorf("invalid resource range %s", part)
+			}
+		}
+		if from < 1 || to > len(items) || from > to {
+			return nil, errors.Errorf("no resource %s in the list of %d resource(s)", part, len(items))
+		}
+		selected = append(selected, items[from-1:to]...)
+	}
+	return selected, nil
+}
+
+func (t *triage) decidedCount() int {
+	count := 0
+	for _, item := range t.items {
+		if _, exists := t.decisions[item.key()]; exists {
+			count++
+		}
+	}
+	return count
+}
+
+// save writes ignored resources to the driftignore file then saves the session
+func (t *triage) save() error {
+	if err := t.writeDriftIgnore(); err != nil {
+		return err
+	}
+	return t.saveSession()
+}
+
+func (t *triage) writeDriftIgnore() error {
+	existing, err := t.driftIgnorePatterns()
+	if err != nil {
+		return err
+	}
+
+	lines := make([]string, 0)
+	written := make([]*triageDecision, 0)
+	for _, item := range t.items {
+		decision, exists := t.decisions[item.key()]
+		if !exists || decision.Decision != triageDecisionIgnore || decision.Written {
+			continue
+		}
+		// Resources ignored again after a reset are already in the driftignore file
+		if _, exists := existing[analyser.DriftIgnoreLine(item.Res)]; exists {
+			decision.Written = true
+			continue
+		}
+		comment := fmt.Sprintf("%s resource, triaged on %s", item.Kind, t.now.Format(filter.ExpiryDateFormat))
+		if decision.Comment != "" {
+			comment = fmt.Sprintf("%s: %s", comment, decision.Comment)
+		}
+		lines = append(lines, fmt.Sprintf("%s # %s", analyser.DriftIgnoreLine(item.Res), comment))
+		written = append(written, decision)
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+
+	f, err := os.OpenFile(t.opts.DriftignorePath, os.O_CREATE|os.O_APPEND|os.O_RDWR, 0644)
+	if err != nil {
+		return errors.Wrap(err, "unable to write driftignore file")
+	}
+	defer f.Close()
+	// Entries are not glued to the last line of a file missing its trailing newline
+	content := strings.Join(lines, "\n") + "\n"
+	if info, err := f.Stat(); err == nil && info.Size() > 0 {
+		last := make([]byte, 1)
+		if _, err := f.ReadAt(last, info.Size()-1); err != nil {
+			return errors.Wrap(err, "unable to read driftignore file")
+		}
+		if last[0] != '\n' {
+			content = "\n" + content
+		}
+	}
+	if _, err := f.WriteString(content); err != nil {
+		return errors.Wrap(err, "unable to write driftignore file")
+	}
+	for _, decision := range written {
+		decision.Written = true
+	}
+	fmt.Fprintf(t.out, "Wrote %d resource(s) to %s\n", len(lines), t.opts.DriftignorePath)
+	return nil
+}
+
+// driftIgnorePatterns returns the patterns of the driftignore file, it may not exist yet
+func (t *triage) driftIgnorePatterns() (map[string]struct{}, error) {
+	patterns := make(map[string]struct{})
+	entries, err := filter.ReadDriftIgnoreEntries(t.opts.DriftignorePath)
+	if os.IsNotExist(errors.Cause(err)) {
+		return patterns, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read driftignore file")
+	}
+	for _, entry := range entries {
+		patterns[entry.Pattern] = struct{}{}
+	}
+	return patterns, nil
+}
+
+func (t *triage) saveSession() error {
+	// Decisions on resources that are not part of this scan result are kept
+	decisions := make([]*triageDecision, 0, len(t.decisions))
+	for _, decision := range t.decisions {
+		decisions = append(decisions, decision)
+	}
+	sort.Slice(decisions, func(i, j int) bool {
+		return decisions[i].key() < decisions[j].key()
+	})
+	t.session.Decisions = decisions
+
+	content, err := json.MarshalIndent(t.session, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(t.opts.SessionPath, content, 0600); err != nil {
+		return errors.Wrap(err, "unable to save triage session")
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTriage(t *testing.T) {
+	dir := t.TempDir()
+	opts := &TriageOptions{
+		InputPath:       "testdata/triage/scan.json",
+		SessionPath:     filepath.Join(dir, "session.json"),
+		DriftignorePath: filepath.Join(dir, ".driftignore"),
+	}
+	now := time.Date(2022, 3, 15, 0, 0, 0, 0, time.UTC)
+
+	out := &bytes.Buffer{}
+	commands := []string{
+		"types",
+		"filter tag=ManagedBy=karpenter",
+		"ignore all karpenter nodes",
+		"filter decision=none",
+		"list",
+		"show 3",
+		"show 9",
+		"import 1",
+		"import 2",
+		"investigate 1 deleted by hand?",
+		"quit",
+	}
+	err := runTriage(strings.NewReader(strings.Join(commands, "\n")), out, opts, now)
+	assert.NoError(t, err)
+
+	output := out.String()
+	assert.Contains(t, output, "5 resource(s) to triage, 0 already decided.")
+	assert.Contains(t, output, "    1  aws_iam_user\n    3  aws_instance\n    1  aws_s3_bucket\n")
+	assert.Contains(t, output, "2 resource(s) match the filter\n")
+	assert.Contains(t, output, "Marked 2 resource(s) to ignore\n")
+	assert.Contains(t, output, "   1  unmanaged  aws_s3_bucket.logs.example.com\n   2  missing    aws_iam_user.deploy\n   3  changed    aws_instance.i-web\n")
+	assert.Contains(t, output, "aws_instance.i-web (changed)\n  source: aws_instance.web\n  ~ instance_type: \"t3.micro\" => \"t3.large\"\n")
+	assert.Contains(t, output, "no resource 9 in the list of 3 resource(s)\n")
+	assert.Contains(t, output, "Skipped 1 resource(s), only unmanaged resources can be imported\ntriage> ")
+	assert.Contains(t, output, "Wrote 2 resource(s) to "+opts.DriftignorePath)
+
+	driftignore, err := ioutil.ReadFile(opts.DriftignorePath)
+	assert.NoError(t, err)
+	assert.Equal(t, "aws_instance.i-karpenter-1 # unmanaged resource, triaged on 2022-03-15: karpenter nodes\n"+
+		"aws_instance.i-karpenter-2 # unmanaged resource, triaged on 2022-03-15: karpenter nodes\n", string(driftignore))
+
+	session, err := readTriageSession(opts.SessionPath)
+	assert.NoError(t, err)
+	decisions := make([]string, 0, len(session.Decisions))
+	for _, decision := range session.Decisions {
+		decisions = append(decisions, decision.Type+"."+decision.Id+" "+decision.Decision+" "+decision.Comment)
+	}
+	assert.Equal(t, []string{
+		"aws_iam_user.deploy investigate deleted by hand?",
+		"aws_instance.i-karpenter-1 ignore karpenter nodes",
+		"aws_instance.i-karpenter-2 ignore karpenter nodes",
+		"aws_s3_bucket.logs.example.com import ",
+	}, decisions)
+
+	// Triage resumes from the session, ignored resources are not written twice
+	out.Reset()
+	commands = []string{
+		"filter kind=unmanaged",
+		"list",
+		"reset 1",
+		"ignore 3",
+	}
+	err = runTriage(strings.NewReader(strings.Join(commands, "\n")), out, opts, now)
+	assert.NoError(t, err)
+
+	output = out.String()
+	assert.Contains(t, output, "5 resource(s) to triage, 4 already decided.")
+	assert.Contains(t, output, "   1  unmanaged  aws_instance.i-karpenter-1  [ignore]\n")
+	assert.Contains(t, output, "aws_instance.i-karpenter-1 is already written to "+opts.DriftignorePath)
+	assert.Contains(t, output, "Wrote 1 resource(s) to "+opts.DriftignorePath)
+
+	driftignore, err = ioutil.ReadFile(opts.DriftignorePath)
+	assert.NoError(t, err)
+	assert.Equal(t, "aws_instance.i-karpenter-1 # unmanaged resource, triaged on 2022-03-15: karpenter nodes\n"+
+		"aws_instance.i-karpenter-2 # unmanaged resource, triaged on 2022-03-15: karpenter nodes\n"+
+		"aws_s3_bucket.logs\\.example\\.com # unmanaged resource, triaged on 2022-03-15\n", string(driftignore))
+}
+
+func TestTriage_InvalidCommands(t *testing.T) {
+	dir := t.TempDir()
+	opts := &TriageOptions{
+		InputPath:       "testdata/triage/scan.json",
+		SessionPath:     filepath.Join(dir, "session.json"),
+		DriftignorePath: filepath.Join(dir, ".driftignore"),
+	}
+
+	out := &bytes.Buffer{}
+	commands := []string{
+		"foo",
+		"filter color=blue",
+		"filter kind",
+		"ignore",
+		"ignore 2-1",
+		"ignore one",
+	}
+	err := runTriage(strings.NewReader(strings.Join(commands, "\n")), out, opts, time.Now())
+	assert.NoError(t, err)
+
+	output := out.String()
+	assert.Contains(t, output, "unknown command foo, type help to list commands\n")
+	assert.Contains(t, output, "unknown filter color, valid keys are kind, type, source, tag and decision\n")
+	assert.Contains(t, output, "invalid filter kind, expected key=value\n")
+	assert.Contains(t, output, "ignore expects the numbers of resources\n")
+	assert.Contains(t, output, "no resource 2-1 in the list of 5 resource(s)\n")
+	assert.Contains(t, output, "invalid resource number one\n")
+	assert.NoFileExists(t, opts.DriftignorePath)
+}
+
+func TestTriage_IgnoreAfterReset(t *testing.T) {
+	dir := t.TempDir()
+	opts := &TriageOptions{
+		InputPath:       "testdata/triage/scan.json",
+		SessionPath:     filepath.Join(dir, "session.json"),
+		DriftignorePath: filepath.Join(dir, ".driftignore"),
+	}
+	now := time.Date(2022, 3, 15, 0, 0, 0, 0, time.UTC)
+
+	out := &bytes.Buffer{}
+	err := runTriage(strings.NewReader("filter kind=unmanaged\nignore 1\nsave\nreset 1\nignore 1 still karpenter"), out, opts, now)
+	assert.NoError(t, err)
+	assert.Contains(t, out.String(), "aws_instance.i-karpenter-1 is already written to "+opts.DriftignorePath)
+
+	// The resource is not appended again to the driftignore file
+	driftignore, err := ioutil.ReadFile(opts.DriftignorePath)
+	assert.NoError(t, err)
+	assert.Equal(t, "aws_instance.i-karpenter-1 # unmanaged resource, triaged on 2022-03-15\n", string(driftignore))
+
+	session, err := readTriageSession(opts.SessionPath)
+	assert.NoError(t, err)
+	if assert.Len(t, session.Decisions, 1) {
+		assert.True(t, session.Decisions[0].Written)
+	}
+}
+
+func TestTriage_DriftIgnoreWithoutTrailingNewline(t *testing.T) {
+	dir := t.TempDir()
+	opts := &TriageOptions{
+		InputPath:       "testdata/triage/scan.json",
+		SessionPath:     filepath.Join(dir, "session.json"),
+		DriftignorePath: filepath.Join(dir, ".driftignore"),
+	}
+	now := time.Date(2022, 3, 15, 0, 0, 0, 0, time.UTC)
+	assert.NoError(t, ioutil.WriteFile(opts.DriftignorePath, []byte("# Buckets\naws_s3_bucket.logs"), 0644))
+
+	err := runTriage(strings.NewReader("filter kind=unmanaged\nignore 1"), &bytes.Buffer{}, opts, now)
+	assert.NoError(t, err)
+
+	driftignore, err := ioutil.ReadFile(opts.DriftignorePath)
+	assert.NoError(t, err)
+	assert.Equal(t, "# Buckets\naws_s3_bucket.logs\n"+
+		"aws_instance.i-karpenter-1 # unmanaged resource, triaged on 2022-03-15\n", string(driftignore))
+}
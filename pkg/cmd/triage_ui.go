@@ -0,0 +1,584 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/fatih/color"
+	"github.com/pkg/errors"
+	"golang.org/x/term"
+)
+
+type triageView int
+
+const (
+	triageViewGroups triageView = iota
+	triageViewList
+	triageViewDetail
+)
+
+// triageBrowseKeys are the keys resources are grouped by on the first screen, switched with tab
+var triageBrowseKeys = []string{"kind", "type", "source", "tag"}
+
+const (
+	triageKeysGroups = "↑↓ move  tab browse by  enter open  s save  q quit"
+	triageKeysList   = "↑↓ move  space mark  a mark all  enter details  i ignore  m import  v investigate  r reset  u undecided only  esc back  q quit"
+	triageKeysDetail = "↑↓ scroll  i ignore  m import  v investigate  r reset  esc back  q quit"
+)
+
+// triageGroup is a value of the browse key with the number of resources having it
+type triageGroup struct {
+	value     string
+	total     int
+	undecided int
+}
+
+// triageUI browses the resources of a triage in the terminal, drawing a screen after each key
+type triageUI struct {
+	*triage
+	browseKey int
+	view      triageView
+	cursors   map[triageView]int
+	// marked holds the keys of the items a decision applies to, the one under the cursor when empty
+	marked map[string]struct{}
+	detail *triageItem
+	// prompt is the decision waiting for its comment to be typed, empty when none
+	prompt string
+	input  []rune
+	status string
+}
+
+func newTriageUI(t *triage) *triageUI {
+	return &triageUI{
+		triage:  t,
+		cursors: make(map[triageView]int),
+		marked:  make(map[string]struct{}),
+	}
+}
+
+// runTriageUI takes over the terminal until triage is over, then saves the decisions
+func runTriageUI(in *os.File, out io.Writer, opts *TriageOptions, now time.Time) error {
+	t, err := newTriage(out, opts, now)
+	if err != nil {
+		return err
+	}
+
+	fd := int(in.Fd())
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return errors.Wrap(err, "unable to setup terminal")
+	}
+	// Use the alternate screen so that the content of the terminal is restored when leaving
+	fmt.Fprint(out, "\x1b[?1049h\x1b[?25l")
+	err = newTriageUI(t).run(bufio.NewReader(in), out, func() (int, int) {
+		width, height, err := term.GetSize(fd)
+		if err != nil || width <= 0 || height <= 0 {
+			return 80, 24
+		}
+		return width, height
+	})
+	fmt.Fprint(out, "\x1b[?25h\x1b[?1049l")
+	if restoreErr := term.Restore(fd, state); restoreErr != nil && err == nil {
+		err = errors.Wrap(restoreErr, "unable to restore terminal")
+	}
+	if err != nil {
+		return err
+	}
+
+	t.out = out
+	return t.save()
+}
+
+// run draws the screen and handles keys until quit is pressed or the input is over
+func (ui *triageUI) run(in *bufio.Reader, out io.Writer, size func() (int, int)) error {
+	for {
+		width, height := size()
+		fmt.Fprint(out, ui.render(width, height))
+		key, err := readTriageKey(in)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if ui.handle(key, height) {
+			return nil
+		}
+	}
+}
+
+// readTriageKey reads a key pressed in a raw terminal, special keys are returned by name
+func readTriageKey(in *bufio.Reader) (string, error) {
+	r, _, err := in.ReadRune()
+	if err != nil {
+		return "", err
+	}
+	switch r {
+	case '\r', '\n':
+		return "enter", nil
+	case '\t':
+		return "tab", nil
+	case 0x7f, '\b':
+		return "backspace", nil
+	case 0x03:
+		return "ctrl-c", nil
+	case 0x1b:
+	default:
+		return string(r), nil
+	}
+
+	// Escape sequences are written at once, a lone escape is the escape key
+	if in.Buffered() == 0 {
+		return "esc", nil
+	}
+	if next, err := in.Peek(1); err != nil || (next[0] != '[' && next[0] != 'O') {
+		return "esc", nil
+	}
+	_, _ = in.ReadByte()
+	sequence := make([]byte, 0, 4)
+	for {
+		b, err := in.ReadByte()
+		if err != nil {
+			return "", err
+		}
+		sequence = append(sequence, b)
+		if b >= 0x40 && b <= 0x7e {
+			break
+		}
+	}
+	switch string(sequence) {
+	case "A":
+		return "up", nil
+	case "B":
+		return "down", nil
+	case "C":
+		return "right", nil
+	case "D":
+		return "left", nil
+	case "H", "1~", "7~":
+		return "home", nil
+	case "F", "4~", "8~":
+		return "end", nil
+	case "5~":
+		return "pgup", nil
+	case "6~":
+		return "pgdown", nil
+	}
+	return "", nil
+}
+
+// handle applies a key and returns true when triage is over
+func (ui *triageUI) handle(key string, height int) bool {
+	if ui.prompt != "" {
+		ui.handlePrompt(key)
+		return false
+	}
+	ui.status = ""
+	if key == "ctrl-c" || key == "q" {
+		return true
+	}
+	if key == "s" {
+		ui.capture(func() error {
+			if err := ui.save(); err != nil {
+				return err
+			}
+			fmt.Fprintf(ui.out, "Saved session to %s\n", ui.opts.SessionPath)
+			return nil
+		})
+		return false
+	}
+	if ui.move(key, height) {
+		return false
+	}
+
+	switch ui.view {
+	case triageViewGroups:
+		ui.handleGroups(key)
+	case triageViewList:
+		ui.handleList(key)
+	case triageViewDetail:
+		ui.handleDetail(key)
+	}
+	return false
+}
+
+// move handles the keys moving the cursor of the current view
+func (ui *triageUI) move(key string, height int) bool {
+	cursor := ui.cursors[ui.view]
+	page := ui.bodyHeight(height)
+	switch key {
+	case "up", "k":
+		cursor--
+	case "down", "j":
+		cursor++
+	case "pgup":
+		cursor -= page
+	case "pgdown":
+		cursor += page
+	case "home", "g":
+		cursor = 0
+	case "end", "G":
+		cursor = ui.rowCount() - 1
+	default:
+		return false
+	}
+	if cursor >= ui.rowCount() {
+		cursor = ui.rowCount() - 1
+	}
+	if cursor < 0 {
+		cursor = 0
+	}
+	ui.cursors[ui.view] = cursor
+	return true
+}
+
+func (ui *triageUI) handleGroups(key string) {
+	switch key {
+	case "tab", "right":
+		ui.browseKey = (ui.browseKey + 1) % len(triageBrowseKeys)
+		ui.cursors[triageViewGroups] = 0
+	case "left":
+		ui.browseKey = (ui.browseKey + len(triageBrowseKeys) - 1) % len(triageBrowseKeys)
+		ui.cursors[triageViewGroups] = 0
+	case "enter":
+		ui.filters = make(map[string]string)
+		// The first row lists all resources
+		if cursor := ui.cursors[triageViewGroups]; cursor > 0 {
+			ui.filters[triageBrowseKeys[ui.browseKey]] = ui.groups()[cursor-1].value
+		}
+		ui.view = triageViewList
+		ui.cursors[triageViewList] = 0
+		ui.marked = make(map[string]struct{})
+	}
+}
+
+func (ui *triageUI) handleList(key string) {
+	items := ui.filtered()
+	switch key {
+	case "esc", "backspace", "left":
+		ui.view = triageViewGroups
+		ui.marked = make(map[string]struct{})
+	case " ":
+		if len(items) == 0 {
+			return
+		}
+		cursor := ui.cursors[triageViewList]
+		if _, exists := ui.marked[items[cursor].key()]; exists {
+			delete(ui.marked, items[cursor].key())
+		} else {
+			ui.marked[items[cursor].key()] = struct{}{}
+		}
+		ui.move("down", 0)
+	case "a":
+		if len(ui.marked) == len(items) {
+			ui.marked = make(map[string]struct{})
+			return
+		}
+		for _, item := range items {
+			ui.marked[item.key()] = struct{}{}
+		}
+	case "u":
+		if _, exists := ui.filters["decision"]; exists {
+			delete(ui.filters, "decision")
+		} else {
+			ui.filters["decision"] = "none"
+		}
+		ui.cursors[triageViewList] = 0
+		ui.marked = make(map[string]struct{})
+	case "enter":
+		if len(items) == 0 {
+			return
+		}
+		ui.detail = items[ui.cursors[triageViewList]]
+		ui.view = triageViewDetail
+		ui.cursors[triageViewDetail] = 0
+	default:
+		ui.startDecision(key)
+	}
+}
+
+func (ui *triageUI) handleDetail(key string) {
+	switch key {
+	case "esc", "backspace", "left":
+		ui.view = triageViewList
+	default:
+		ui.startDecision(key)
+	}
+}
+
+// startDecision asks for a comment on ignore and investigate decisions, others are applied right away
+func (ui *triageUI) startDecision(key string) {
+	decisions := map[string]string{
+		"i": triageDecisionIgnore,
+		"m": triageDecisionImport,
+		"v": triageDecisionInvestigate,
+		"r": "reset",
+	}
+	decision, exists := decisions[key]
+	if !exists || len(ui.selected()) == 0 {
+		return
+	}
+	if decision == triageDecisionIgnore || decision == triageDecisionInvestigate {
+		ui.prompt = decision
+		ui.input = nil
+		return
+	}
+	ui.decide(decision, "")
+}
+
+func (ui *triageUI) handlePrompt(key string) {
+	switch key {
+	case "esc", "ctrl-c":
+		ui.prompt = ""
+	case "enter":
+		decision := ui.prompt
+		ui.prompt = ""
+		ui.decide(decision, string(ui.input))
+	case "backspace":
+		if len(ui.input) > 0 {
+			ui.input = ui.input[:len(ui.input)-1]
+		}
+	case "tab":
+	default:
+		if utf8.RuneCountInString(key) == 1 {
+			ui.input = append(ui.input, []rune(key)...)
+		}
+	}
+}
+
+// selected returns the items a decision applies to
+func (ui *triageUI) selected() []*triageItem {
+	if ui.view == triageViewDetail {
+		return []*triageItem{ui.detail}
+	}
+	items := ui.filtered()
+	selected := make([]*triageItem, 0, len(ui.marked))
+	for _, item := range items {
+		if _, exists := ui.marked[item.key()]; exists {
+			selected = append(selected, item)
+		}
+	}
+	if len(selected) == 0 && len(items) > 0 {
+		selected = append(selected, items[ui.cursors[triageViewList]])
+	}
+	return selected
+}
+
+// decide applies a decision to the selected items through their numbers in the filtered list
+func (ui *triageUI) decide(decision, comment string) {
+	selected := make(map[string]struct{})
+	for _, item := range ui.selected() {
+		selected[item.key()] = struct{}{}
+	}
+	numbers := make([]string, 0, len(selected))
+	for i, item := range ui.filtered() {
+		if _, exists := selected[item.key()]; exists {
+			numbers = append(numbers, strconv.Itoa(i+1))
+		}
+	}
+	args := []string{strings.Join(numbers, ",")}
+	if comment != "" {
+		args = append(args, comment)
+	}
+	ui.capture(func() error { return ui.triage.decide(decision, args) })
+	ui.marked = make(map[string]struct{})
+
+	// Decided items may not match the filter anymore
+	if ui.view == triageViewDetail && !ui.matches(ui.detail) {
+		ui.view = triageViewList
+	}
+	if rows := ui.rowCount(); ui.cursors[triageViewList] >= rows && rows > 0 {
+		ui.cursors[triageViewList] = rows - 1
+	}
+}
+
+// capture shows the messages written by a triage command in the status line
+func (ui *triageUI) capture(command func() error) {
+	out := ui.out
+	messages := &bytes.Buffer{}
+	ui.out = messages
+	err := command()
+	ui.out = out
+
+	lines := strings.Split(strings.TrimSpace(messages.String()), "\n")
+	if err != nil {
+		lines = append(lines, color.RedString(err.Error()))
+	}
+	ui.status = strings.Join(lines, ", ")
+}
+
+// groups returns the values of the browse key with the number of resources having them
+func (ui *triageUI) groups() []triageGroup {
+	key := triageBrowseKeys[ui.browseKey]
+	groups := make(map[string]*triageGroup)
+	for _, item := range ui.items {
+		_, decided := ui.decisions[item.key()]
+		for _, value := range item.values(key) {
+			group, exists := groups[value]
+			if !exists {
+				group = &triageGroup{value: value}
+				groups[value] = group
+			}
+			group.total++
+			if !decided {
+				group.undecided++
+			}
+		}
+	}
+	sorted := make([]triageGroup, 0, len(groups))
+	for _, group := range groups {
+		sorted = append(sorted, *group)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].value < sorted[j].value
+	})
+	return sorted
+}
+
+func (ui *triageUI) rows() []string {
+	switch ui.view {
+	case triageViewGroups:
+		undecided := len(ui.items) - ui.decidedCount()
+		rows := []string{fmt.Sprintf("%5d  %9d  %s", len(ui.items), undecided, "all resources")}
+		for _, group := range ui.groups() {
+			value := group.value
+			if value == "" {
+				value = "(none)"
+			}
+			rows = append(rows, fmt.Sprintf("%5d  %9d  %s", group.total, group.undecided, value))
+		}
+		return rows
+	case triageViewList:
+		items := ui.filtered()
+		rows := make([]string, 0, len(items))
+		for _, item := range items {
+			mark := "[ ]"
+			if _, exists := ui.marked[item.key()]; exists {
+				mark = "[x]"
+			}
+			rows = append(rows, fmt.Sprintf("%s %s", mark, ui.describeShort(item)))
+		}
+		return rows
+	}
+	detail := &bytes.Buffer{}
+	ui.describe(detail, ui.detail)
+	return strings.Split(strings.TrimSuffix(detail.String(), "\n"), "\n")
+}
+
+func (ui *triageUI) rowCount() int {
+	return len(ui.rows())
+}
+
+// bodyHeight is the number of rows displayed between the title lines and the status lines
+func (ui *triageUI) bodyHeight(height int) int {
+	if height < 6 {
+		return 1
+	}
+	return height - 5
+}
+
+func (ui *triageUI) title() string {
+	switch ui.view {
+	case triageViewGroups:
+		keys := make([]string, 0, len(triageBrowseKeys))
+		for i, key := range triageBrowseKeys {
+			if i == ui.browseKey {
+				key = color.New(color.Bold).Sprintf("[%s]", key)
+			}
+			keys = append(keys, key)
+		}
+		return fmt.Sprintf("Browse by %s\n  %5s  %9s  %s", strings.Join(keys, " "), "total", "undecided", triageBrowseKeys[ui.browseKey])
+	case triageViewList:
+		filters := make([]string, 0, len(ui.filters))
+		for key, value := range ui.filters {
+			filters = append(filters, fmt.Sprintf("%s=%s", key, value))
+		}
+		sort.Strings(filters)
+		if len(filters) == 0 {
+			filters = append(filters, "all resources")
+		}
+		return fmt.Sprintf("%s\n%d resource(s), %d marked", strings.Join(filters, " "), len(ui.filtered()), len(ui.marked))
+	}
+	return fmt.Sprintf("Resource %d of %d\n%d change(s)", ui.cursors[triageViewList]+1, len(ui.filtered()), len(ui.detail.Changelog))
+}
+
+// render draws the whole screen, long lines are cut to the width of the terminal
+func (ui *triageUI) render(width, height int) string {
+	lines := []string{color.New(color.Bold).Sprintf("driftctl triage: %d resource(s), %d decided", len(ui.items), ui.decidedCount())}
+	lines = append(lines, strings.SplitN(ui.title(), "\n", 2)...)
+
+	rows := ui.rows()
+	page := ui.bodyHeight(height)
+	cursor := ui.cursors[ui.view]
+	// Lists scroll by page, details scroll line by line
+	offset := cursor / page * page
+	if ui.view == triageViewDetail {
+		offset = cursor
+	}
+	for i := offset; i < offset+page; i++ {
+		if i >= len(rows) {
+			lines = append(lines, "")
+			continue
+		}
+		prefix := "  "
+		if i == cursor && ui.view != triageViewDetail {
+			prefix = "> "
+		}
+		lines = append(lines, prefix+rows[i])
+	}
+	if ui.view == triageViewGroups && triageBrowseKeys[ui.browseKey] == "tag" && len(rows) == 1 {
+		lines[3] = "  No tags, they are only known for unmanaged resources of a scan in deep mode"
+	}
+
+	status := ui.status
+	if ui.prompt != "" {
+		status = fmt.Sprintf("Comment to %s (enter to confirm, esc to cancel): %s_", ui.prompt, string(ui.input))
+	}
+	keys := map[triageView]string{
+		triageViewGroups: triageKeysGroups,
+		triageViewList:   triageKeysList,
+		triageViewDetail: triageKeysDetail,
+	}
+	lines = append(lines, status, color.New(color.Faint).Sprint(keys[ui.view]))
+
+	screen := &strings.Builder{}
+	screen.WriteString("\x1b[H")
+	for i, line := range lines {
+		if i > 0 {
+			screen.WriteString("\r\n")
+		}
+		screen.WriteString(truncateTerminalLine(line, width))
+		screen.WriteString("\x1b[K")
+	}
+	screen.WriteString("\x1b[J")
+	return screen.String()
+}
+
+// truncateTerminalLine cuts a line to a number of columns, escape sequences are kept as they take none
+func truncateTerminalLine(line string, width int) string {
+	out := &strings.Builder{}
+	columns := 0
+	escape := false
+	for _, r := range line {
+		switch {
+		case r == 0x1b:
+			escape = true
+		case escape:
+			escape = r < 0x40 || r > 0x7e || r == '['
+		case columns >= width:
+			continue
+		default:
+			columns++
+		}
+		out.WriteRune(r)
+	}
+	return out.String()
+}
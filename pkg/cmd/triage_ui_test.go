@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTriageUI(t *testing.T) {
+	dir := t.TempDir()
+	opts := &TriageOptions{
+		InputPath:       "testdata/triage/scan.json",
+		SessionPath:     filepath.Join(dir, "session.json"),
+		DriftignorePath: filepath.Join(dir, ".driftignore"),
+	}
+	now := time.Date(2022, 3, 15, 0, 0, 0, 0, time.UTC)
+	tr, err := newTriage(&bytes.Buffer{}, opts, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keys := strings.Join([]string{
+		// Browse by tag and ignore all the resources having it
+		"\t\t\t", "\x1b[B", "\r", "a", "i", "karpenter nodes", "\r", "\x1b",
+		// Browse by kind and investigate the missing resource from its details
+		"\t", "j", "j", "\r", "\r", "v", "gone?", "\r", "\x7f", "\x7f", "s",
+		// Import the unmanaged resource left undecided
+		"j", "\r", "u", "m", "q",
+	}, "")
+	screen := &bytes.Buffer{}
+	ui := newTriageUI(tr)
+	err = ui.run(bufio.NewReader(strings.NewReader(keys)), screen, func() (int, int) { return 120, 20 })
+	assert.NoError(t, err)
+
+	output := screen.String()
+	assert.Contains(t, output, "Browse by kind type source [tag]\x1b[K\r\n  total  undecided  tag")
+	assert.Contains(t, output, ">     2          2  ManagedBy=karpenter")
+	assert.Contains(t, output, "tag=ManagedBy=karpenter\x1b[K\r\n2 resource(s), 2 marked")
+	assert.Contains(t, output, "Comment to ignore (enter to confirm, esc to cancel): karpenter nodes_")
+	assert.Contains(t, output, "Marked 2 resource(s) to ignore")
+	assert.Contains(t, output, "  aws_iam_user.deploy (missing)\x1b[K\r\n    source: module.iam.aws_iam_user.deploy")
+	assert.Contains(t, output, "Wrote 2 resource(s) to "+opts.DriftignorePath+", Saved session to ")
+	assert.Contains(t, output, "decision=none kind=unmanaged\x1b[K\r\n1 resource(s), 0 marked")
+	assert.Contains(t, output, "Marked 1 resource(s) to import")
+
+	driftignore, err := ioutil.ReadFile(opts.DriftignorePath)
+	assert.NoError(t, err)
+	assert.Equal(t, "aws_instance.i-karpenter-1 # unmanaged resource, triaged on 2022-03-15: karpenter nodes\n"+
+		"aws_instance.i-karpenter-2 # unmanaged resource, triaged on 2022-03-15: karpenter nodes\n", string(driftignore))
+
+	session, err := readTriageSession(opts.SessionPath)
+	assert.NoError(t, err)
+	decisions := make([]string, 0, len(session.Decisions))
+	for _, decision := range session.Decisions {
+		decisions = append(decisions, decision.Type+"."+decision.Id+" "+decision.Decision+" "+decision.Comment)
+	}
+	assert.Equal(t, []string{
+		"aws_iam_user.deploy investigate gone?",
+		"aws_instance.i-karpenter-1 ignore karpenter nodes",
+		"aws_instance.i-karpenter-2 ignore karpenter nodes",
+		"aws_s3_bucket.logs.example.com import ",
+	}, decisions)
+}
+
+func TestReadTriageKey(t *testing.T) {
+	in := bufio.NewReader(strings.NewReader("\x1b[A\x1b[6~\x1bOHq\r\t\x7f\x03é\x1b"))
+	keys := make([]string, 0)
+	for {
+		key, err := readTriageKey(in)
+		if err != nil {
+			break
+		}
+		keys = append(keys, key)
+	}
+	assert.Equal(t, []string{"up", "pgdown", "home", "q", "enter", "tab", "backspace", "ctrl-c", "é", "esc"}, keys)
+}
+
+func TestTruncateTerminalLine(t *testing.T) {
+	assert.Equal(t, "abc", truncateTerminalLine("abcdef", 3))
+	assert.Equal(t, "\x1b[33mab\x1b[0m", truncateTerminalLine("\x1b[33mabcd\x1b[0m", 2))
+	assert.Equal(t, "é", truncateTerminalLine("é", 3))
+}
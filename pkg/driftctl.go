@@ -16,24 +16,34 @@ import (
 	"github.com/cloudskiff/driftctl/pkg/iac/config"
 	"github.com/cloudskiff/driftctl/pkg/iac/terraform/state/backend"
 	"github.com/cloudskiff/driftctl/pkg/middlewares"
+	"github.com/cloudskiff/driftctl/pkg/remote/aws"
 	"github.com/cloudskiff/driftctl/pkg/resource"
+	"github.com/cloudskiff/driftctl/pkg/terraform"
 )
 
 type ScanOptions struct {
 	Coverage         bool
 	Detect           bool
 	From             []config.SupplierConfig
-	To               string
+	To               []string
 	Output           []output.OutputConfig
 	Filter           *jmespath.JMESPath
 	Quiet            bool
 	BackendOptions   *backend.Options
 	StrictMode       bool
 	DisableTelemetry bool
-	ProviderVersion  string
+	// ProviderVersions maps remotes to the version of their terraform provider
+	ProviderVersions map[string]string
 	ConfigDir        string
-	DriftignorePath  string
+	// DriftignorePaths are the driftignore files to merge, entries of later files take precedence
+	DriftignorePaths []string
 	Deep             bool
+	// ReportIgnored lists resources and fields filtered out by the driftignore with the rule that matched
+	ReportIgnored bool
+	// ProviderInstallation configures mirrors terraform providers are installed from
+	ProviderInstallation *terraform.ProviderInstallation
+	// AWSTargets lists the accounts and regions scanned at once, the default credentials are used when empty
+	AWSTargets []aws.Target
 }
 
 type DriftCTL struct {
@@ -121,6 +131,8 @@ func (d DriftCTL) Run() (*analyser.Analysis, error) {
 
 		middlewares.NewAzurermRouteExpander(d.resourceFactory),
 		middlewares.NewAzurermSubnetExpander(d.resourceFactory),
+
+		middlewares.NewAwsResourceScope(),
 	)
 
 	if !d.opts.StrictMode {
@@ -131,11 +143,15 @@ func (d DriftCTL) Run() (*analyser.Analysis, error) {
 		)
 	}
 
+	// Only resources read from terraform states hold attributes, keep the other ones away from middlewares
+	resourcesFromState, attributelessResources := splitStateResources(resourcesFromState)
+
 	logrus.Debug("Ready to run middlewares")
 	err = middleware.Execute(&remoteResources, &resourcesFromState)
 	if err != nil {
 		return nil, err
 	}
+	resourcesFromState = append(resourcesFromState, attributelessResources...)
 
 	if d.opts.Filter != nil {
 		engine := filter.NewFilterEngine(d.opts.Filter)
@@ -164,6 +180,18 @@ func (d DriftCTL) Run() (*analyser.Analysis, error) {
 	return &analysis, nil
 }
 
+func splitStateResources(resources []*resource.Resource) (fromState, others []*resource.Resource) {
+	fromState = make([]*resource.Resource, 0, len(resources))
+	for _, res := range resources {
+		if _, isStateSource := res.Source.(*resource.TerraformStateSource); res.Source != nil && !isStateSource {
+			others = append(others, res)
+			continue
+		}
+		fromState = append(fromState, res)
+	}
+	return fromState, others
+}
+
 func (d DriftCTL) Stop() {
 	stoppableSupplier, ok := d.remoteSupplier.(resource.StoppableSupplier)
 	if ok {
@@ -4,40 +4,253 @@ import (
 	"bufio"
 	"fmt"
 	"os"
+	"path/filepath"
+	"regexp"
 	"strings"
+	"time"
 
+	"github.com/cloudskiff/driftctl/pkg/alerter"
 	"github.com/cloudskiff/driftctl/pkg/resource"
 	"github.com/go-git/go-git/v5/plumbing/format/gitignore"
+	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 )
 
 const separator = "_-_"
 
-type DriftIgnore struct {
-	driftignorePath string
-	matcher         gitignore.Matcher
+// ExpiryDateFormat is the layout of the expires annotation of driftignore entries
+const ExpiryDateFormat = "2006-01-02"
+
+// DriftIgnoreFileName is the name of the driftignore files looked up in directories
+const DriftIgnoreFileName = ".driftignore"
+
+// includeDirective reads the entries of another driftignore file in place of the line
+const includeDirective = "%include"
+
+// conditionRegex matches the start of the condition of an entry, a JMESPath expression like the ones of
+// the filter flag, e.g. aws_iam_role.* %where Attr.path=='/aws-service-role/'
+var conditionRegex = regexp.MustCompile(`\s+%where\s+`)
+
+// nonTypeReferenceRegex matches the parts of a condition that may depend on anything but the type of resources
+var nonTypeReferenceRegex = regexp.MustCompile(`Attr|Id|Res|@|\*`)
+
+// attributeReferenceRegex matches the parts of a condition that may depend on the attributes of resources
+var attributeReferenceRegex = regexp.MustCompile(`Attr|Res|@|\*`)
+
+// annotationRegex matches the start of the annotation of an entry, a # preceded by a blank
+var annotationRegex = regexp.MustCompile(`\s+#`)
+
+// DriftIgnoreEntry is a pattern of a driftignore file with the annotation written after it, e.g.
+// aws_s3_bucket.legacy-logs # expires=2022-12-31 owner=alice ticket=OPS-123 kept for the audit
+type DriftIgnoreEntry struct {
+	// File is the path of the driftignore file the entry was read from
+	File    string
+	Line    int
+	Pattern string
+	// Expires is the last day the entry applies, entries without expiry never expire
+	Expires *time.Time
+	Owner   string
+	Ticket  string
+	Comment string
+	// Condition is the expression resources matched by the entry pattern have to verify to be matched
+	Condition string
+	// Err reports an invalid annotation, the entry applies as if it was not annotated,
+	// or an invalid condition, the entry never applies
+	Err       error
+	patterns  []gitignore.Pattern
+	condition *FilterEngine
+	// typeCondition is true when the condition only depends on the type of resources
+	typeCondition bool
+	// attributeCondition is true when the condition may depend on the attributes of resources
+	attributeCondition bool
+}
+
+// IsExpired returns true once the last day of the entry is over
+func (e *DriftIgnoreEntry) IsExpired(now time.Time) bool {
+	return e.Expires != nil && !now.Before(e.Expires.AddDate(0, 0, 1))
+}
+
+// HasAttributeCondition returns true when the condition of the entry may depend on the attributes of resources,
+// they are only all read in deep mode, remote resources hold few attributes otherwise
+func (e *DriftIgnoreEntry) HasAttributeCondition() bool {
+	return e.attributeCondition
+}
+
+// IsNegation returns true when the entry stops ignoring resources matched by a previous entry
+func (e *DriftIgnoreEntry) IsNegation() bool {
+	return strings.HasPrefix(e.Pattern, "!")
 }
 
-func NewDriftIgnore(path string) *DriftIgnore {
-	d := DriftIgnore{
-		driftignorePath: path,
-		matcher:         gitignore.NewMatcher(nil),
+// Matches returns true when the entry pattern matches the given resource or field, the condition of the entry
+// is not evaluated
+func (e *DriftIgnoreEntry) Matches(strRes string) bool {
+	return e.match(strRes) > gitignore.NoMatch
+}
+
+func (e *DriftIgnoreEntry) match(strRes string) gitignore.MatchResult {
+	path := []string{strings.ReplaceAll(strRes, "/", separator)}
+	for i := len(e.patterns) - 1; i >= 0; i-- {
+		if result := e.patterns[i].Match(path, false); result > gitignore.NoMatch {
+			return result
+		}
+	}
+	return gitignore.NoMatch
+}
+
+// matchResource matches the entry against a resource or a field of it, the condition is evaluated on the resource.
+// Only conditions on the type can be evaluated to match every resource of a type
+func (e *DriftIgnoreEntry) matchResource(strRes string, res *resource.Resource, wholeType bool) gitignore.MatchResult {
+	result := e.match(strRes)
+	if result == gitignore.NoMatch || e.Condition == "" {
+		return result
+	}
+	if e.condition == nil {
+		return gitignore.NoMatch
 	}
-	err := d.readIgnoreFile()
+	if wholeType && !e.typeCondition {
+		// Some resources of the type may verify the condition, a negation may keep them
+		// but the type can't be ignored as a whole
+		if result == gitignore.Include {
+			return result
+		}
+		return gitignore.NoMatch
+	}
+
+	matched, err := e.condition.Match(res)
 	if err != nil {
-		logrus.Debug(err)
+		logrus.WithFields(logrus.Fields{
+			"entry": e.String(),
+			"error": err,
+		}).Debug("Unable to evaluate driftignore entry condition")
+		return gitignore.NoMatch
+	}
+	if !matched {
+		return gitignore.NoMatch
+	}
+	return result
+}
+
+// Annotations returns the owner and ticket of the entry for display
+func (e *DriftIgnoreEntry) Annotations() string {
+	annotations := make([]string, 0, 2)
+	if e.Owner != "" {
+		annotations = append(annotations, fmt.Sprintf("owner: %s", e.Owner))
+	}
+	if e.Ticket != "" {
+		annotations = append(annotations, fmt.Sprintf("ticket: %s", e.Ticket))
+	}
+	return strings.Join(annotations, ", ")
+}
+
+// Rule returns the location of the entry
+func (e *DriftIgnoreEntry) Rule() *IgnoreRule {
+	return &IgnoreRule{Pattern: e.Pattern, File: e.File, Line: e.Line}
+}
+
+func (e *DriftIgnoreEntry) String() string {
+	if e.File == "" {
+		return fmt.Sprintf("%s (line %d)", e.Pattern, e.Line)
+	}
+	return fmt.Sprintf("%s (%s line %d)", e.Pattern, e.File, e.Line)
+}
+
+// ExpiredDriftIgnoreEntryAlert warns that an entry no longer ignores resources
+type ExpiredDriftIgnoreEntryAlert struct {
+	entry *DriftIgnoreEntry
+}
+
+func NewExpiredDriftIgnoreEntryAlert(entry *DriftIgnoreEntry) *ExpiredDriftIgnoreEntryAlert {
+	return &ExpiredDriftIgnoreEntryAlert{entry}
+}
+
+func (a *ExpiredDriftIgnoreEntryAlert) Message() string {
+	message := fmt.Sprintf("Driftignore entry %s expired on %s and no longer ignores resources", a.entry, a.entry.Expires.Format(ExpiryDateFormat))
+	if annotations := a.entry.Annotations(); annotations != "" {
+		message = fmt.Sprintf("%s (%s)", message, annotations)
+	}
+	return message
+}
+
+func (a *ExpiredDriftIgnoreEntryAlert) ShouldIgnoreResource() bool {
+	return false
+}
+
+type DriftIgnore struct {
+	entries []*DriftIgnoreEntry
+	// active are the entries that did not expire, in the order of the files
+	active []*DriftIgnoreEntry
+}
+
+func NewDriftIgnore(path string, alerter alerter.AlerterInterface) *DriftIgnore {
+	return NewDriftIgnoreFiles([]string{path}, alerter)
+}
+
+// NewDriftIgnoreFiles merges the entries of several driftignore files, entries of a file take
+// precedence over the ones of previous files as if they were written after them
+func NewDriftIgnoreFiles(paths []string, alerter alerter.AlerterInterface) *DriftIgnore {
+	d := DriftIgnore{}
+	for _, path := range paths {
+		entries, err := ReadDriftIgnoreEntries(path)
+		if err != nil {
+			logrus.Debug(err)
+			continue
+		}
+		d.entries = append(d.entries, entries...)
+	}
+
+	now := time.Now()
+	for _, entry := range d.entries {
+		if entry.IsExpired(now) {
+			alerter.SendAlert("", NewExpiredDriftIgnoreEntryAlert(entry))
+			continue
+		}
+		d.active = append(d.active, entry)
 	}
 	return &d
 }
 
-func (r *DriftIgnore) readIgnoreFile() error {
-	file, err := os.Open(r.driftignorePath)
+// FindDriftIgnoreFiles lists the driftignore files of a directory and of its parents up to the root
+// of the git repository it belongs to, parents first so that entries of inner files take precedence as in
+// gitignore. Only the driftignore file of the directory is returned outside of a git repository
+func FindDriftIgnoreFiles(dir string) ([]string, error) {
+	own := filepath.Join(dir, DriftIgnoreFileName)
+	paths := []string{own}
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	parent := dir
+	for current := absDir; ; current = filepath.Dir(current) {
+		if _, err := os.Stat(filepath.Join(current, ".git")); err == nil {
+			return paths, nil
+		}
+		if filepath.Dir(current) == current {
+			return []string{own}, nil
+		}
+		parent = filepath.Join(parent, "..")
+		path := filepath.Join(parent, DriftIgnoreFileName)
+		if _, err := os.Stat(path); err == nil {
+			paths = append([]string{path}, paths...)
+		}
+	}
+}
+
+// ReadDriftIgnoreEntries parses the entries of a driftignore file with their annotations,
+// entries of files included with %include are read in place of the directive
+func ReadDriftIgnoreEntries(path string) ([]*DriftIgnoreEntry, error) {
+	return readDriftIgnoreEntries(filepath.Clean(path), nil)
+}
+
+// readDriftIgnoreEntries reads a driftignore file included by the given chain of files
+func readDriftIgnoreEntries(path string, includedBy []string) ([]*DriftIgnoreEntry, error) {
+	file, err := os.Open(path)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer file.Close()
 
-	var lines []gitignore.Pattern
+	var entries []*DriftIgnoreEntry
 	scanner := bufio.NewScanner(file)
 	for lineNumber := 1; scanner.Scan(); lineNumber++ {
 		line := scanner.Text()
@@ -49,28 +262,135 @@ func (r *DriftIgnore) readIgnoreFile() error {
 		if strings.HasPrefix(line, "#") {
 			continue // this is a comment
 		}
-		line = strings.ReplaceAll(line, "/", separator)
 
-		lines = append(lines, gitignore.ParsePattern(line, nil))
+		if fields := strings.Fields(line); len(fields) > 0 && fields[0] == includeDirective {
+			included, err := includeDriftIgnoreEntries(path, lineNumber, line, includedBy)
+			if err != nil {
+				logrus.WithFields(logrus.Fields{
+					"file":  path,
+					"line":  lineNumber,
+					"error": err,
+				}).Warn("Unable to include driftignore file")
+			}
+			entries = append(entries, included...)
+			continue
+		}
+
+		entry := &DriftIgnoreEntry{File: path, Line: lineNumber}
+		if loc := annotationRegex.FindStringIndex(line); loc != nil {
+			parseAnnotation(entry, line[loc[1]:])
+			line = line[:loc[0]]
+			if entry.Err != nil {
+				logrus.WithFields(logrus.Fields{
+					"file":  path,
+					"line":  lineNumber,
+					"error": entry.Err,
+				}).Warn("Invalid driftignore entry annotation")
+			}
+		}
+		entry.Pattern = line
+		if loc := conditionRegex.FindStringIndex(line); loc != nil {
+			parseCondition(entry, line[loc[1]:])
+			line = line[:loc[0]]
+			if entry.condition == nil {
+				logrus.WithFields(logrus.Fields{
+					"file":  path,
+					"line":  lineNumber,
+					"error": entry.Err,
+				}).Warn("Invalid driftignore entry condition, the entry is ignored")
+			}
+		}
+
+		line = strings.ReplaceAll(line, "/", separator)
+		entry.patterns = append(entry.patterns, gitignore.ParsePattern(line, nil))
 		if !strings.HasSuffix(line, "*") {
 			line := fmt.Sprintf("%s.*", line)
-			lines = append(lines, gitignore.ParsePattern(line, nil))
+			entry.patterns = append(entry.patterns, gitignore.ParsePattern(line, nil))
 		}
+		entries = append(entries, entry)
 	}
 
 	if err := scanner.Err(); err != nil {
-		return err
+		return nil, err
 	}
 
-	r.matcher = gitignore.NewMatcher(lines)
+	return entries, nil
+}
 
-	return nil
+// includeDriftIgnoreEntries reads the file of an %include directive, its path is relative
+// to the directory of the including file
+func includeDriftIgnoreEntries(path string, lineNumber int, line string, includedBy []string) ([]*DriftIgnoreEntry, error) {
+	if loc := annotationRegex.FindStringIndex(line); loc != nil {
+		line = line[:loc[0]]
+	}
+	includePath := strings.TrimSpace(strings.TrimPrefix(line, includeDirective))
+	if includePath == "" {
+		return nil, errors.Errorf("missing path to include at line %d of %s", lineNumber, path)
+	}
+	if !filepath.IsAbs(includePath) {
+		includePath = filepath.Join(filepath.Dir(path), includePath)
+	}
+
+	chain := append(append([]string{}, includedBy...), path)
+	for _, p := range chain {
+		if p == includePath {
+			return nil, errors.Errorf("%s is included recursively at line %d of %s", includePath, lineNumber, path)
+		}
+	}
+	return readDriftIgnoreEntries(includePath, chain)
+}
+
+// parseCondition compiles the condition of an entry
+func parseCondition(entry *DriftIgnoreEntry, condition string) {
+	entry.Condition = strings.TrimSpace(condition)
+	expr, err := BuildExpression(entry.Condition)
+	if err != nil {
+		entry.Err = errors.Wrapf(err, "invalid condition %s", entry.Condition)
+		return
+	}
+	entry.condition = NewFilterEngine(expr)
+	entry.typeCondition = !nonTypeReferenceRegex.MatchString(entry.Condition)
+	entry.attributeCondition = attributeReferenceRegex.MatchString(entry.Condition)
+}
+
+// parseAnnotation reads the expires, owner and ticket keys of an annotation,
+// the remaining words are kept as comment
+func parseAnnotation(entry *DriftIgnoreEntry, annotation string) {
+	var comment []string
+	for _, word := range strings.Fields(annotation) {
+		parts := strings.SplitN(word, "=", 2)
+		if len(parts) != 2 {
+			comment = append(comment, word)
+			continue
+		}
+		switch parts[0] {
+		case "expires":
+			expires, err := time.Parse(ExpiryDateFormat, parts[1])
+			if err != nil {
+				entry.Err = errors.Errorf("invalid expiry date %s, expected a date like %s", parts[1], ExpiryDateFormat)
+				continue
+			}
+			entry.Expires = &expires
+		case "owner":
+			entry.Owner = parts[1]
+		case "ticket":
+			entry.Ticket = parts[1]
+		default:
+			comment = append(comment, word)
+		}
+	}
+	entry.Comment = strings.Join(comment, " ")
+}
+
+// Entries returns every entry of the driftignore file, expired ones included
+func (r *DriftIgnore) Entries() []*DriftIgnoreEntry {
+	return r.entries
 }
 
 func (r *DriftIgnore) isAnyOfChildrenTypesNotIgnored(ty resource.ResourceType) bool {
 	childrenTypes := resource.GetMeta(ty).GetChildrenTypes()
 	for _, childrenType := range childrenTypes {
-		if !r.match(fmt.Sprintf("%s.*", childrenType)) {
+		if !r.isWholeTypeIgnored(childrenType) {
 			return true
 		}
 		if r.isAnyOfChildrenTypesNotIgnored(childrenType) {
@@ -87,18 +407,51 @@ func (r *DriftIgnore) IsTypeIgnored(ty resource.ResourceType) bool {
 		return false
 	}
 
-	return r.match(fmt.Sprintf("%s.*", ty))
+	return r.isWholeTypeIgnored(ty)
+}
+
+// isWholeTypeIgnored returns true when every resource of the type is ignored, entries with
+// conditions on attributes only apply to resources once they are read
+func (r *DriftIgnore) isWholeTypeIgnored(ty resource.ResourceType) bool {
+	return r.matchingEntry(fmt.Sprintf("%s.*", ty), &resource.Resource{Type: string(ty)}, true) != nil
 }
 
 func (r *DriftIgnore) IsResourceIgnored(res *resource.Resource) bool {
-	return r.match(fmt.Sprintf("%s.%s", res.ResourceType(), res.ResourceId()))
+	return r.ResourceIgnoreRule(res) != nil
 }
 
 func (r *DriftIgnore) IsFieldIgnored(res *resource.Resource, path []string) bool {
+	return r.FieldIgnoreRule(res, path) != nil
+}
+
+// ResourceIgnoreRule returns the rule ignoring the resource, nil when it is not ignored
+func (r *DriftIgnore) ResourceIgnoreRule(res *resource.Resource) *IgnoreRule {
+	return ruleOf(r.matchingEntry(fmt.Sprintf("%s.%s", res.ResourceType(), res.ResourceId()), res, false))
+}
+
+// FieldIgnoreRule returns the rule ignoring the field of the resource, nil when it is not ignored
+func (r *DriftIgnore) FieldIgnoreRule(res *resource.Resource, path []string) *IgnoreRule {
 	full := fmt.Sprintf("%s.%s.%s", res.ResourceType(), res.ResourceId(), strings.Join(path, "."))
-	return r.match(full)
+	return ruleOf(r.matchingEntry(full, res, false))
 }
 
-func (r *DriftIgnore) match(strRes string) bool {
-	return r.matcher.Match([]string{strings.ReplaceAll(strRes, "/", separator)}, false)
+func ruleOf(entry *DriftIgnoreEntry) *IgnoreRule {
+	if entry == nil {
+		return nil
+	}
+	return entry.Rule()
+}
+
+// matchingEntry returns the entry ignoring the given resource or field, the last matching entry
+// applies as in gitignore so a negated entry stops previous ones from ignoring
+func (r *DriftIgnore) matchingEntry(strRes string, res *resource.Resource, wholeType bool) *DriftIgnoreEntry {
+	for i := len(r.active) - 1; i >= 0; i-- {
+		switch r.active[i].matchResource(strRes, res, wholeType) {
+		case gitignore.Exclude:
+			return r.active[i]
+		case gitignore.Include:
+			return nil
+		}
+	}
+	return nil
 }
@@ -0,0 +1,81 @@
+package filter
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cloudskiff/driftctl/pkg/resource"
+)
+
+// AuditedResource is a resource found by a scan made without driftignore file
+type AuditedResource struct {
+	Resource *resource.Resource
+	// Drifted is false for resources in sync with IaC
+	Drifted bool
+	// ChangedFields are the paths of the attributes that changed on the cloud provider
+	ChangedFields [][]string
+}
+
+// DriftIgnoreAudit lists the entries of a driftignore file worth cleaning up
+type DriftIgnoreAudit struct {
+	Invalid []*DriftIgnoreEntry
+	Expired []*DriftIgnoreEntry
+	// NeverMatched and Stale entries are only known when scanned resources are given
+	NeverMatched []*DriftIgnoreEntry
+	// Stale entries only match resources in sync with IaC
+	Stale []*DriftIgnoreEntry
+}
+
+func (a *DriftIgnoreAudit) IsEmpty() bool {
+	return len(a.Invalid)+len(a.Expired)+len(a.NeverMatched)+len(a.Stale) == 0
+}
+
+// AuditDriftIgnore checks entries against the resources of a scan, expired entries are not checked
+// against resources as they already no longer apply
+func AuditDriftIgnore(entries []*DriftIgnoreEntry, resources []AuditedResource, now time.Time) *DriftIgnoreAudit {
+	audit := &DriftIgnoreAudit{}
+	for _, entry := range entries {
+		if entry.Err != nil {
+			audit.Invalid = append(audit.Invalid, entry)
+		}
+		if entry.IsExpired(now) {
+			audit.Expired = append(audit.Expired, entry)
+			continue
+		}
+		if resources == nil {
+			continue
+		}
+
+		matched, drifted := auditEntry(entry, resources)
+		if !matched {
+			audit.NeverMatched = append(audit.NeverMatched, entry)
+			continue
+		}
+		if !drifted && !entry.IsNegation() {
+			audit.Stale = append(audit.Stale, entry)
+		}
+	}
+	return audit
+}
+
+// auditEntry returns whether the entry matches any resource and whether one of those has drifted
+func auditEntry(entry *DriftIgnoreEntry, resources []AuditedResource) (matched, drifted bool) {
+	for _, res := range resources {
+		strRes := fmt.Sprintf("%s.%s", res.Resource.ResourceType(), res.Resource.ResourceId())
+		if entry.Matches(strRes) {
+			matched = true
+			drifted = drifted || res.Drifted
+		}
+		for _, path := range res.ChangedFields {
+			if entry.Matches(fmt.Sprintf("%s.%s", strRes, strings.Join(path, "."))) {
+				matched = true
+				drifted = true
+			}
+		}
+		if drifted {
+			return
+		}
+	}
+	return
+}
@@ -1,12 +1,16 @@
 package filter
 
 import (
+	"io/ioutil"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/cloudskiff/driftctl/pkg/alerter"
 	"github.com/cloudskiff/driftctl/pkg/resource"
 )
 
@@ -221,7 +225,7 @@ func TestDriftIgnore_IsResourceIgnored(t *testing.T) {
 			cwd, _ := os.Getwd()
 			defer func() { _ = os.Chdir(cwd) }()
 
-			r := NewDriftIgnore(tt.path)
+			r := NewDriftIgnore(tt.path, alerter.NewAlerter())
 			got := make([]bool, 0, len(tt.want))
 			for _, res := range tt.resources {
 				got = append(got, r.IsResourceIgnored(res))
@@ -425,7 +429,7 @@ func TestDriftIgnore_IsFieldIgnored(t *testing.T) {
 			cwd, _ := os.Getwd()
 			defer func() { _ = os.Chdir(cwd) }()
 
-			r := NewDriftIgnore(tt.path)
+			r := NewDriftIgnore(tt.path, alerter.NewAlerter())
 			for _, arg := range tt.args {
 				got := r.IsFieldIgnored(arg.Res, arg.Path)
 				if arg.Want != got {
@@ -525,7 +529,7 @@ func TestDriftIgnore_IsTypeIgnored(t *testing.T) {
 			cwd, _ := os.Getwd()
 			defer func() { _ = os.Chdir(cwd) }()
 
-			r := NewDriftIgnore(tt.path)
+			r := NewDriftIgnore(tt.path, alerter.NewAlerter())
 			got := make([]bool, 0, len(tt.want))
 			for _, res := range tt.resources {
 				got = append(got, r.IsTypeIgnored(resource.ResourceType(res.ResourceType())))
@@ -534,3 +538,241 @@ func TestDriftIgnore_IsTypeIgnored(t *testing.T) {
 		})
 	}
 }
+
+func TestDriftIgnore_Annotations(t *testing.T) {
+	alr := alerter.NewAlerter()
+	r := NewDriftIgnore("testdata/drift_ignore_annotations/.driftignore", alr)
+
+	expires := time.Date(2999, 12, 31, 0, 0, 0, 0, time.UTC)
+	entries := r.Entries()
+	assert.Len(t, entries, 6)
+	assert.Equal(t, "expiring_resource.id2", entries[1].Pattern)
+	assert.Equal(t, 3, entries[1].Line)
+	assert.Equal(t, &expires, entries[1].Expires)
+	assert.Equal(t, "bob", entries[1].Owner)
+	assert.Equal(t, "waiting for the migration", entries[1].Comment)
+	assert.Equal(t, "OPS-456", entries[2].Ticket)
+	assert.EqualError(t, entries[3].Err, "invalid expiry date tomorrow, expected a date like 2006-01-02")
+	assert.Equal(t, "resource_type.id#with#hashes", entries[4].Pattern)
+
+	assert.False(t, r.IsResourceIgnored(&resource.Resource{Type: "expired_resource", Id: "id1"}))
+	assert.True(t, r.IsResourceIgnored(&resource.Resource{Type: "expiring_resource", Id: "id2"}))
+	assert.True(t, r.IsResourceIgnored(&resource.Resource{Type: "annotated_resource", Id: "id3"}))
+	assert.True(t, r.IsResourceIgnored(&resource.Resource{Type: "invalid_resource", Id: "id4"}))
+	assert.True(t, r.IsResourceIgnored(&resource.Resource{Type: "resource_type", Id: "id#with#hashes"}))
+	assert.False(t, r.IsResourceIgnored(&resource.Resource{Type: "other_resource", Id: "id5"}))
+
+	alerts := alr.Retrieve()[""]
+	assert.Len(t, alerts, 2)
+	assert.Equal(t, "Driftignore entry expired_resource.id1 (testdata/drift_ignore_annotations/.driftignore line 2) expired on 2000-01-31 and no longer ignores resources (owner: alice, ticket: OPS-123)", alerts[0].Message())
+	assert.Equal(t, "Driftignore entry * (testdata/drift_ignore_annotations/.driftignore line 7) expired on 2000-01-01 and no longer ignores resources", alerts[1].Message())
+}
+
+func TestDriftIgnoreEntry_IsExpired(t *testing.T) {
+	expires := time.Date(2022, 3, 15, 0, 0, 0, 0, time.UTC)
+	entry := &DriftIgnoreEntry{Expires: &expires}
+	assert.False(t, entry.IsExpired(time.Date(2022, 3, 15, 23, 59, 0, 0, time.UTC)))
+	assert.True(t, entry.IsExpired(time.Date(2022, 3, 16, 0, 0, 0, 0, time.UTC)))
+	assert.False(t, (&DriftIgnoreEntry{}).IsExpired(time.Now()))
+}
+
+func TestAuditDriftIgnore(t *testing.T) {
+	entries, err := ReadDriftIgnoreEntries("testdata/drift_ignore_annotations/.driftignore")
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2022, 3, 15, 0, 0, 0, 0, time.UTC)
+
+	audit := AuditDriftIgnore(entries, nil, now)
+	assert.Equal(t, []*DriftIgnoreEntry{entries[0], entries[5]}, audit.Expired)
+	assert.Equal(t, []*DriftIgnoreEntry{entries[3]}, audit.Invalid)
+	assert.Empty(t, audit.NeverMatched)
+	assert.Empty(t, audit.Stale)
+
+	audit = AuditDriftIgnore(entries, []AuditedResource{
+		{Resource: &resource.Resource{Type: "expiring_resource", Id: "id2"}, Drifted: true},
+		{Resource: &resource.Resource{Type: "annotated_resource", Id: "id3"}},
+		{Resource: &resource.Resource{Type: "resource_type", Id: "id#with#hashes"}, Drifted: true, ChangedFields: [][]string{{"tags", "Name"}}},
+	}, now)
+	assert.Equal(t, []*DriftIgnoreEntry{entries[0], entries[5]}, audit.Expired)
+	assert.Equal(t, []*DriftIgnoreEntry{entries[3]}, audit.NeverMatched)
+	assert.Equal(t, []*DriftIgnoreEntry{entries[2]}, audit.Stale)
+	assert.False(t, audit.IsEmpty())
+}
+
+func TestDriftIgnore_IgnoreRule(t *testing.T) {
+	path := "testdata/drift_ignore_rules/.driftignore"
+	r := NewDriftIgnore(path, alerter.NewAlerter())
+
+	assert.Equal(t, &IgnoreRule{Pattern: "aws_s3_bucket.*", File: path, Line: 2}, r.ResourceIgnoreRule(&resource.Resource{Type: "aws_s3_bucket", Id: "logs"}))
+	assert.Nil(t, r.ResourceIgnoreRule(&resource.Resource{Type: "aws_s3_bucket", Id: "kept"}))
+	assert.Nil(t, r.ResourceIgnoreRule(&resource.Resource{Type: "aws_instance", Id: "web"}))
+	assert.Equal(t, &IgnoreRule{Pattern: "aws_instance.web.tags.*", File: path, Line: 4}, r.FieldIgnoreRule(&resource.Resource{Type: "aws_instance", Id: "web"}, []string{"tags", "Name"}))
+	assert.Nil(t, r.FieldIgnoreRule(&resource.Resource{Type: "aws_instance", Id: "web"}, []string{"ami"}))
+}
+
+func TestReadIgnoredTypes(t *testing.T) {
+	path := "testdata/drift_ignore_type/.driftignore"
+	r := NewDriftIgnore(path, alerter.NewAlerter())
+	f := ReadIgnoredTypes(r)
+
+	assert.True(t, r.IsTypeIgnored("type_3"))
+	assert.False(t, f.IsTypeIgnored("type_3"))
+	assert.True(t, f.IsResourceIgnored(&resource.Resource{Type: "type_3", Id: "foo"}))
+	assert.Equal(t, &IgnoreRule{Pattern: "type_3.*", File: path, Line: 3}, r.ResourceIgnoreRule(&resource.Resource{Type: "type_3", Id: "foo"}))
+}
+
+func TestDriftIgnore_Include(t *testing.T) {
+	entries, err := ReadDriftIgnoreEntries("testdata/drift_ignore_include/.driftignore")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		got = append(got, entry.Rule().File+":"+entry.Pattern)
+	}
+	assert.Equal(t, []string{
+		"testdata/drift_ignore_include/shared/baseline.driftignore:aws_s3_bucket.*",
+		"testdata/drift_ignore_include/shared/baseline.driftignore:aws_iam_user.ci",
+		"testdata/drift_ignore_include/.driftignore:!aws_s3_bucket.product-data",
+		"testdata/drift_ignore_include/.driftignore:aws_sqs_queue.*",
+		"testdata/drift_ignore_include/cycle.driftignore:aws_sns_topic.cycle",
+	}, got)
+
+	r := NewDriftIgnore("testdata/drift_ignore_include/.driftignore", alerter.NewAlerter())
+	assert.True(t, r.IsResourceIgnored(&resource.Resource{Type: "aws_s3_bucket", Id: "logs"}))
+	assert.False(t, r.IsResourceIgnored(&resource.Resource{Type: "aws_s3_bucket", Id: "product-data"}))
+	assert.True(t, r.IsResourceIgnored(&resource.Resource{Type: "aws_iam_user", Id: "ci"}))
+	assert.True(t, r.IsResourceIgnored(&resource.Resource{Type: "aws_sns_topic", Id: "cycle"}))
+}
+
+func TestNewDriftIgnoreFiles(t *testing.T) {
+	r := NewDriftIgnoreFiles([]string{
+		"testdata/drift_ignore_files/global.driftignore",
+		"testdata/drift_ignore_files/.driftignore",
+		"testdata/drift_ignore_files/missing.driftignore",
+	}, alerter.NewAlerter())
+
+	assert.Len(t, r.Entries(), 4)
+	assert.True(t, r.IsResourceIgnored(&resource.Resource{Type: "aws_s3_bucket", Id: "logs"}))
+	assert.False(t, r.IsResourceIgnored(&resource.Resource{Type: "aws_s3_bucket", Id: "product-data"}))
+	assert.True(t, r.IsResourceIgnored(&resource.Resource{Type: "aws_iam_user", Id: "ci"}))
+	assert.True(t, r.IsResourceIgnored(&resource.Resource{Type: "aws_sqs_queue", Id: "jobs"}))
+	assert.Equal(t, &IgnoreRule{Pattern: "aws_s3_bucket.*", File: "testdata/drift_ignore_files/global.driftignore", Line: 1}, r.ResourceIgnoreRule(&resource.Resource{Type: "aws_s3_bucket", Id: "logs"}))
+}
+
+func TestFindDriftIgnoreFiles(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{".git", "team/product", "other"} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0700); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, path := range []string{".driftignore", "team/product/.driftignore"} {
+		if err := ioutil.WriteFile(filepath.Join(root, path), []byte("aws_s3_bucket.*\n"), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	outside := t.TempDir()
+
+	tests := []struct {
+		name string
+		dir  string
+		want []string
+	}{
+		{
+			name: "innermost files come last",
+			dir:  filepath.Join(root, "team/product"),
+			want: []string{filepath.Join(root, ".driftignore"), filepath.Join(root, "team/product/.driftignore")},
+		},
+		{
+			name: "missing file of the directory is kept",
+			dir:  filepath.Join(root, "other"),
+			want: []string{filepath.Join(root, ".driftignore"), filepath.Join(root, "other/.driftignore")},
+		},
+		{
+			name: "root of the repository",
+			dir:  root,
+			want: []string{filepath.Join(root, ".driftignore")},
+		},
+		{
+			name: "outside of a git repository",
+			dir:  outside,
+			want: []string{filepath.Join(outside, ".driftignore")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FindDriftIgnoreFiles(tt.dir)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestDriftIgnore_Conditions(t *testing.T) {
+	r := NewDriftIgnore("testdata/drift_ignore_conditions/.driftignore", alerter.NewAlerter())
+
+	entries := r.Entries()
+	assert.Len(t, entries, 5)
+	assert.Equal(t, "aws_iam_role.* %where Attr.path=='/aws-service-role/'", entries[0].Pattern)
+	assert.Equal(t, "Attr.path=='/aws-service-role/'", entries[0].Condition)
+	assert.Equal(t, "platform", entries[1].Owner)
+	assert.EqualError(t, entries[4].Err, "invalid condition Attr.name ===: SyntaxError: Invalid token: tUnknown")
+	assert.True(t, entries[0].HasAttributeCondition())
+	assert.False(t, entries[3].HasAttributeCondition())
+
+	tests := []struct {
+		name string
+		res  *resource.Resource
+		want bool
+	}{
+		{
+			name: "service linked role",
+			res:  &resource.Resource{Type: "aws_iam_role", Id: "AWSServiceRoleForSupport", Attrs: &resource.Attributes{"path": "/aws-service-role/"}},
+			want: true,
+		},
+		{
+			name: "other role",
+			res:  &resource.Resource{Type: "aws_iam_role", Id: "admin", Attrs: &resource.Attributes{"path": "/"}},
+			want: false,
+		},
+		{
+			name: "role without attributes",
+			res:  &resource.Resource{Type: "aws_iam_role", Id: "admin"},
+			want: false,
+		},
+		{
+			name: "resource tagged by karpenter",
+			res:  &resource.Resource{Type: "aws_instance", Id: "i-1", Attrs: &resource.Attributes{"tags": map[string]interface{}{"ManagedBy": "karpenter"}}},
+			want: true,
+		},
+		{
+			name: "resource kept by a negated condition",
+			res:  &resource.Resource{Type: "aws_instance", Id: "i-2", Attrs: &resource.Attributes{"tags": map[string]interface{}{"ManagedBy": "karpenter", "Keep": "true"}}},
+			want: false,
+		},
+		{
+			name: "condition on the type",
+			res:  &resource.Resource{Type: "aws_cloudwatch_metric_alarm", Id: "cpu"},
+			want: true,
+		},
+		{
+			name: "invalid condition",
+			res:  &resource.Resource{Type: "aws_sqs_queue", Id: "jobs", Attrs: &resource.Attributes{"name": "jobs"}},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, r.IsResourceIgnored(tt.res))
+		})
+	}
+
+	assert.True(t, r.IsFieldIgnored(tests[0].res, []string{"description"}))
+	assert.True(t, r.IsTypeIgnored("aws_cloudwatch_metric_alarm"))
+	assert.False(t, r.IsTypeIgnored("aws_iam_role"))
+	assert.False(t, r.IsTypeIgnored("aws_instance"))
+	assert.False(t, r.IsTypeIgnored("aws_sqs_queue"))
+}
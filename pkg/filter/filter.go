@@ -7,3 +7,32 @@ type Filter interface {
 	IsResourceIgnored(res *resource.Resource) bool
 	IsFieldIgnored(res *resource.Resource, path []string) bool
 }
+
+// IgnoreRule locates the driftignore entry that ignored a resource or a field
+type IgnoreRule struct {
+	Pattern string `json:"pattern"`
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+}
+
+// RuleFilter is a filter able to tell which rule ignored a resource or a field,
+// rules are nil for resources and fields that are not ignored
+type RuleFilter interface {
+	Filter
+	ResourceIgnoreRule(res *resource.Resource) *IgnoreRule
+	FieldIgnoreRule(res *resource.Resource, path []string) *IgnoreRule
+}
+
+// ReadIgnoredTypes lets IaC sources read the resources of types ignored as a whole,
+// they are then filtered out one by one by the analysis, which reports the rule that ignored them
+func ReadIgnoredTypes(f Filter) Filter {
+	return readIgnoredTypes{f}
+}
+
+type readIgnoredTypes struct {
+	Filter
+}
+
+func (readIgnoredTypes) IsTypeIgnored(resource.ResourceType) bool {
+	return false
+}
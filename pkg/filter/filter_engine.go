@@ -33,7 +33,10 @@ func (e *FilterEngine) Run(resources []*resource.Resource) ([]*resource.Resource
 		// We need to serialize all attributes to untyped interface from JMESPath to work
 		// map[string]string and map[string]SomeThing will not work without it
 		// https://github.com/jmespath/go-jmespath/issues/22
-		var attrs map[string]interface{} = *res.Attributes()
+		var attrs map[string]interface{}
+		if res.Attributes() != nil {
+			attrs = *res.Attributes()
+		}
 
 		f := filtrableResource{
 			Attr: attrs,
@@ -62,3 +65,12 @@ func (e *FilterEngine) Run(resources []*resource.Resource) ([]*resource.Resource
 
 	return results, nil
 }
+
+// Match returns true when the expression keeps the given resource
+func (e *FilterEngine) Match(res *resource.Resource) (bool, error) {
+	results, err := e.Run([]*resource.Resource{res})
+	if err != nil {
+		return false, err
+	}
+	return len(results) == 1, nil
+}
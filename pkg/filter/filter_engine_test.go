@@ -296,3 +296,27 @@ func TestFilterEngine_Run(t *testing.T) {
 		})
 	}
 }
+
+func TestFilterEngine_Match(t *testing.T) {
+	expr, err := BuildExpression("Attr.tags.ManagedBy=='karpenter'")
+	if err != nil {
+		t.Fatal(err)
+	}
+	engine := NewFilterEngine(expr)
+
+	matched, err := engine.Match(&resource.Resource{Type: "aws_instance", Attrs: &resource.Attributes{"tags": map[string]interface{}{"ManagedBy": "karpenter"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !matched {
+		t.Error("resource tagged by karpenter should match")
+	}
+
+	matched, err = engine.Match(&resource.Resource{Type: "aws_instance"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if matched {
+		t.Error("resource without attributes should not match")
+	}
+}
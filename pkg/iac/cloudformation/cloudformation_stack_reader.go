@@ -0,0 +1,178 @@
+package cloudformation
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/cloudskiff/driftctl/pkg/filter"
+	"github.com/cloudskiff/driftctl/pkg/iac/config"
+	"github.com/cloudskiff/driftctl/pkg/iac/terraform/state/backend"
+	"github.com/cloudskiff/driftctl/pkg/output"
+	"github.com/cloudskiff/driftctl/pkg/resource"
+	resourceaws "github.com/cloudskiff/driftctl/pkg/resource/aws"
+)
+
+const CloudformationStackReaderSupplier = "cloudformation"
+
+// cloudformationTypes maps cloudformation types to terraform ones for which
+// the physical id of the resource is the terraform id
+var cloudformationTypes = map[string]string{
+	"AWS::ApiGateway::RestApi":        resourceaws.AwsApiGatewayRestApiResourceType,
+	"AWS::ApiGatewayV2::Api":          resourceaws.AwsApiGatewayV2ApiResourceType,
+	"AWS::CloudFormation::Stack":      resourceaws.AwsCloudformationStackResourceType,
+	"AWS::CloudFront::Distribution":   resourceaws.AwsCloudfrontDistributionResourceType,
+	"AWS::DynamoDB::Table":            resourceaws.AwsDynamodbTableResourceType,
+	"AWS::EC2::Instance":              resourceaws.AwsInstanceResourceType,
+	"AWS::EC2::InternetGateway":       resourceaws.AwsInternetGatewayResourceType,
+	"AWS::EC2::KeyPair":               resourceaws.AwsKeyPairResourceType,
+	"AWS::EC2::NatGateway":            resourceaws.AwsNatGatewayResourceType,
+	"AWS::EC2::NetworkAcl":            resourceaws.AwsNetworkACLResourceType,
+	"AWS::EC2::RouteTable":            resourceaws.AwsRouteTableResourceType,
+	"AWS::EC2::SecurityGroup":         resourceaws.AwsSecurityGroupResourceType,
+	"AWS::EC2::Subnet":                resourceaws.AwsSubnetResourceType,
+	"AWS::EC2::Volume":                resourceaws.AwsEbsVolumeResourceType,
+	"AWS::EC2::VPC":                   resourceaws.AwsVpcResourceType,
+	"AWS::ECR::Repository":            resourceaws.AwsEcrRepositoryResourceType,
+	"AWS::IAM::ManagedPolicy":         resourceaws.AwsIamPolicyResourceType,
+	"AWS::IAM::Role":                  resourceaws.AwsIamRoleResourceType,
+	"AWS::IAM::User":                  resourceaws.AwsIamUserResourceType,
+	"AWS::KMS::Alias":                 resourceaws.AwsKmsAliasResourceType,
+	"AWS::KMS::Key":                   resourceaws.AwsKmsKeyResourceType,
+	"AWS::Lambda::EventSourceMapping": resourceaws.AwsLambdaEventSourceMappingResourceType,
+	"AWS::Lambda::Function":           resourceaws.AwsLambdaFunctionResourceType,
+	"AWS::RDS::DBCluster":             resourceaws.AwsRDSClusterResourceType,
+	"AWS::RDS::DBInstance":            resourceaws.AwsDbInstanceResourceType,
+	"AWS::RDS::DBSubnetGroup":         resourceaws.AwsDbSubnetGroupResourceType,
+	"AWS::Route53::HealthCheck":       resourceaws.AwsRoute53HealthCheckResourceType,
+	"AWS::Route53::HostedZone":        resourceaws.AwsRoute53ZoneResourceType,
+	"AWS::S3::Bucket":                 resourceaws.AwsS3BucketResourceType,
+	"AWS::SNS::Topic":                 resourceaws.AwsSnsTopicResourceType,
+	"AWS::SQS::Queue":                 resourceaws.AwsSqsQueueResourceType,
+}
+
+// Resources in those states do not exist on the cloud provider
+var ignoredStatuses = map[string]struct{}{
+	"CREATE_FAILED":   {},
+	"DELETE_COMPLETE": {},
+}
+
+// stackResources accepts the output of both "aws cloudformation describe-stack-resources"
+// and "aws cloudformation list-stack-resources"
+type stackResources struct {
+	StackResources         []stackResource `json:"StackResources"`
+	StackResourceSummaries []stackResource `json:"StackResourceSummaries"`
+}
+
+type stackResource struct {
+	StackName          string `json:"StackName"`
+	StackId            string `json:"StackId"`
+	LogicalResourceId  string `json:"LogicalResourceId"`
+	PhysicalResourceId string `json:"PhysicalResourceId"`
+	ResourceType       string `json:"ResourceType"`
+	ResourceStatus     string `json:"ResourceStatus"`
+}
+
+type CloudformationStackReader struct {
+	config         config.SupplierConfig
+	backendOptions *backend.Options
+	progress       output.Progress
+	filter         filter.Filter
+}
+
+func NewReader(config config.SupplierConfig, backendOpts *backend.Options, progress output.Progress, filter filter.Filter) (*CloudformationStackReader, error) {
+	if backend.IsStateStore(config.Backend) {
+		return nil, errors.Errorf("backend '%s' is not supported by the %s IaC source", config.Backend, CloudformationStackReaderSupplier)
+	}
+	return &CloudformationStackReader{
+		config:         config,
+		backendOptions: backendOpts,
+		progress:       progress,
+		filter:         filter,
+	}, nil
+}
+
+func (r *CloudformationStackReader) Resources() ([]*resource.Resource, error) {
+	logrus.WithFields(logrus.Fields{
+		"path":    r.config.Path,
+		"backend": r.config.Backend,
+	}).Debug("Reading resources from cloudformation stack resources")
+	r.progress.Inc()
+
+	stackRes, err := r.read()
+	if err != nil {
+		return nil, errors.Wrap(err, r.config.String())
+	}
+
+	results := make([]*resource.Resource, 0, len(stackRes))
+	// Stacks themselves are managed by cloudformation, only describe-stack-resources provides their id
+	stacks := map[string]string{}
+	for _, res := range stackRes {
+		if res.StackId != "" {
+			stacks[res.StackId] = res.StackName
+		}
+
+		if _, ignored := ignoredStatuses[res.ResourceStatus]; ignored || res.PhysicalResourceId == "" {
+			continue
+		}
+		ty, supported := cloudformationTypes[res.ResourceType]
+		if !supported {
+			logrus.WithFields(logrus.Fields{
+				"logical_id": res.LogicalResourceId,
+				"type":       res.ResourceType,
+			}).Debug("Ignored unsupported resource from cloudformation stack")
+			continue
+		}
+		if !r.isTypeIncluded(ty) {
+			continue
+		}
+
+		results = append(results, &resource.Resource{
+			Id:     res.PhysicalResourceId,
+			Type:   ty,
+			Attrs:  &resource.Attributes{},
+			Source: resource.NewCloudformationStackSource(r.config.String(), res.StackName, res.LogicalResourceId),
+		})
+	}
+
+	if r.isTypeIncluded(resourceaws.AwsCloudformationStackResourceType) {
+		for id, name := range stacks {
+			results = append(results, &resource.Resource{
+				Id:     id,
+				Type:   resourceaws.AwsCloudformationStackResourceType,
+				Attrs:  &resource.Attributes{},
+				Source: resource.NewCloudformationStackSource(r.config.String(), name, name),
+			})
+		}
+	}
+
+	return resource.Sort(results), nil
+}
+
+func (r *CloudformationStackReader) isTypeIncluded(ty string) bool {
+	if r.filter != nil && r.filter.IsTypeIgnored(resource.ResourceType(ty)) {
+		logrus.WithFields(logrus.Fields{
+			"type": ty,
+		}).Debug("Ignored resource from cloudformation stack since it is ignored in filter")
+		return false
+	}
+	return true
+}
+
+func (r *CloudformationStackReader) read() ([]stackResource, error) {
+	b, err := backend.GetBackend(r.config, r.backendOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer b.Close()
+
+	listing := &stackResources{}
+	if err := json.NewDecoder(b).Decode(listing); err != nil {
+		return nil, errors.Wrap(err, "unable to parse cloudformation stack resources")
+	}
+	if listing.StackResources == nil && listing.StackResourceSummaries == nil {
+		return nil, errors.New("given file is not a valid cloudformation stack resources listing, use 'aws cloudformation describe-stack-resources' to generate it")
+	}
+	return append(listing.StackResources, listing.StackResourceSummaries...), nil
+}
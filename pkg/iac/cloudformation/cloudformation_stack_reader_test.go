@@ -0,0 +1,125 @@
+package cloudformation
+
+import (
+	"testing"
+
+	"github.com/cloudskiff/driftctl/pkg/filter"
+	"github.com/cloudskiff/driftctl/pkg/iac/config"
+	"github.com/cloudskiff/driftctl/pkg/iac/terraform/state/backend"
+	"github.com/cloudskiff/driftctl/pkg/output"
+	"github.com/cloudskiff/driftctl/pkg/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCloudformationStackReader_Resources(t *testing.T) {
+	stackId := "arn:aws:cloudformation:us-east-1:123456789012:stack/network/6b2d3a50-0b1e-11ec-8d3d-0a1b2c3d4e5f"
+
+	cases := []struct {
+		name     string
+		path     string
+		mocks    func(f *filter.MockFilter)
+		expected []*resource.Resource
+		err      string
+	}{
+		{
+			name: "test describe-stack-resources output",
+			path: "testdata/describe-stack-resources.json",
+			mocks: func(f *filter.MockFilter) {
+				f.On("IsTypeIgnored", mock.Anything).Return(false)
+			},
+			expected: []*resource.Resource{
+				{
+					Id:     stackId,
+					Type:   "aws_cloudformation_stack",
+					Attrs:  &resource.Attributes{},
+					Source: resource.NewCloudformationStackSource("cloudformation://testdata/describe-stack-resources.json", "network", "network"),
+				},
+				{
+					Id:     "subnet-0a1b2c3d",
+					Type:   "aws_subnet",
+					Attrs:  &resource.Attributes{},
+					Source: resource.NewCloudformationStackSource("cloudformation://testdata/describe-stack-resources.json", "network", "PublicSubnet"),
+				},
+				{
+					Id:     "vpc-0a1b2c3d",
+					Type:   "aws_vpc",
+					Attrs:  &resource.Attributes{},
+					Source: resource.NewCloudformationStackSource("cloudformation://testdata/describe-stack-resources.json", "network", "Vpc"),
+				},
+			},
+		},
+		{
+			name: "test list-stack-resources output",
+			path: "testdata/list-stack-resources.json",
+			mocks: func(f *filter.MockFilter) {
+				f.On("IsTypeIgnored", mock.Anything).Return(false)
+			},
+			expected: []*resource.Resource{
+				{
+					Id:     "assets-bucket-1a2b3c",
+					Type:   "aws_s3_bucket",
+					Attrs:  &resource.Attributes{},
+					Source: resource.NewCloudformationStackSource("cloudformation://testdata/list-stack-resources.json", "", "Bucket"),
+				},
+			},
+		},
+		{
+			name: "test filtered resource types",
+			path: "testdata/describe-stack-resources.json",
+			mocks: func(f *filter.MockFilter) {
+				f.On("IsTypeIgnored", resource.ResourceType("aws_vpc")).Return(false)
+				f.On("IsTypeIgnored", mock.Anything).Return(true)
+			},
+			expected: []*resource.Resource{
+				{
+					Id:     "vpc-0a1b2c3d",
+					Type:   "aws_vpc",
+					Attrs:  &resource.Attributes{},
+					Source: resource.NewCloudformationStackSource("cloudformation://testdata/describe-stack-resources.json", "network", "Vpc"),
+				},
+			},
+		},
+		{
+			name:  "test invalid listing",
+			path:  "testdata/invalid.json",
+			mocks: func(f *filter.MockFilter) {},
+			err:   "cloudformation://testdata/invalid.json: given file is not a valid cloudformation stack resources listing, use 'aws cloudformation describe-stack-resources' to generate it",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			f := &filter.MockFilter{}
+			c.mocks(f)
+			progress := &output.MockProgress{}
+			progress.On("Inc").Return().Times(1)
+
+			reader, err := NewReader(config.SupplierConfig{
+				Key:  CloudformationStackReaderSupplier,
+				Path: c.path,
+			}, nil, progress, f)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			got, err := reader.Resources()
+			progress.AssertExpectations(t)
+			if c.err != "" {
+				assert.EqualError(t, err, c.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, c.expected, got)
+		})
+	}
+}
+
+func TestCloudformationStackReader_UnsupportedBackend(t *testing.T) {
+	_, err := NewReader(config.SupplierConfig{
+		Key:     CloudformationStackReaderSupplier,
+		Backend: backend.BackendKeyTFCloud,
+		Path:    "stack-resources.json",
+	}, nil, nil, nil)
+	assert.EqualError(t, err, "backend 'tfcloud' is not supported by the cloudformation IaC source")
+}
@@ -0,0 +1,172 @@
+package pulumi
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/cloudskiff/driftctl/pkg/filter"
+	"github.com/cloudskiff/driftctl/pkg/iac/config"
+	"github.com/cloudskiff/driftctl/pkg/iac/terraform/state/backend"
+	"github.com/cloudskiff/driftctl/pkg/output"
+	"github.com/cloudskiff/driftctl/pkg/resource"
+	resourceaws "github.com/cloudskiff/driftctl/pkg/resource/aws"
+	resourceazure "github.com/cloudskiff/driftctl/pkg/resource/azurerm"
+	resourcegithub "github.com/cloudskiff/driftctl/pkg/resource/github"
+	resourcegoogle "github.com/cloudskiff/driftctl/pkg/resource/google"
+)
+
+const PulumiStackReaderSupplier = "pulumi"
+
+// pulumiTypes maps pulumi type tokens to terraform types for which the id of the resource is the one
+// driftctl finds on the cloud provider. Pulumi providers bridged from terraform share ids with terraform,
+// but middlewares are not applied to pulumi stacks so types whose driftctl ids are built by middlewares are left out
+var pulumiTypes = map[string]string{
+	"aws:apigateway/restApi:RestApi":                   resourceaws.AwsApiGatewayRestApiResourceType,
+	"aws:apigatewayv2/api:Api":                         resourceaws.AwsApiGatewayV2ApiResourceType,
+	"aws:cloudformation/stack:Stack":                   resourceaws.AwsCloudformationStackResourceType,
+	"aws:cloudfront/distribution:Distribution":         resourceaws.AwsCloudfrontDistributionResourceType,
+	"aws:dynamodb/table:Table":                         resourceaws.AwsDynamodbTableResourceType,
+	"aws:ebs/volume:Volume":                            resourceaws.AwsEbsVolumeResourceType,
+	"aws:ec2/instance:Instance":                        resourceaws.AwsInstanceResourceType,
+	"aws:ec2/internetGateway:InternetGateway":          resourceaws.AwsInternetGatewayResourceType,
+	"aws:ec2/keyPair:KeyPair":                          resourceaws.AwsKeyPairResourceType,
+	"aws:ec2/natGateway:NatGateway":                    resourceaws.AwsNatGatewayResourceType,
+	"aws:ec2/networkAcl:NetworkAcl":                    resourceaws.AwsNetworkACLResourceType,
+	"aws:ec2/routeTable:RouteTable":                    resourceaws.AwsRouteTableResourceType,
+	"aws:ec2/securityGroup:SecurityGroup":              resourceaws.AwsSecurityGroupResourceType,
+	"aws:ec2/subnet:Subnet":                            resourceaws.AwsSubnetResourceType,
+	"aws:ec2/vpc:Vpc":                                  resourceaws.AwsVpcResourceType,
+	"aws:ecr/repository:Repository":                    resourceaws.AwsEcrRepositoryResourceType,
+	"aws:iam/policy:Policy":                            resourceaws.AwsIamPolicyResourceType,
+	"aws:iam/role:Role":                                resourceaws.AwsIamRoleResourceType,
+	"aws:iam/user:User":                                resourceaws.AwsIamUserResourceType,
+	"aws:kms/alias:Alias":                              resourceaws.AwsKmsAliasResourceType,
+	"aws:kms/key:Key":                                  resourceaws.AwsKmsKeyResourceType,
+	"aws:lambda/eventSourceMapping:EventSourceMapping": resourceaws.AwsLambdaEventSourceMappingResourceType,
+	"aws:lambda/function:Function":                     resourceaws.AwsLambdaFunctionResourceType,
+	"aws:rds/cluster:Cluster":                          resourceaws.AwsRDSClusterResourceType,
+	"aws:rds/instance:Instance":                        resourceaws.AwsDbInstanceResourceType,
+	"aws:rds/subnetGroup:SubnetGroup":                  resourceaws.AwsDbSubnetGroupResourceType,
+	"aws:route53/healthCheck:HealthCheck":              resourceaws.AwsRoute53HealthCheckResourceType,
+	"aws:route53/zone:Zone":                            resourceaws.AwsRoute53ZoneResourceType,
+	"aws:s3/bucket:Bucket":                             resourceaws.AwsS3BucketResourceType,
+	"aws:s3/bucketPolicy:BucketPolicy":                 resourceaws.AwsS3BucketPolicyResourceType,
+	"aws:sns/topic:Topic":                              resourceaws.AwsSnsTopicResourceType,
+	"aws:sqs/queue:Queue":                              resourceaws.AwsSqsQueueResourceType,
+	"azure:core/resourceGroup:ResourceGroup":           resourceazure.AzureResourceGroupResourceType,
+	"azure:network/virtualNetwork:VirtualNetwork":      resourceazure.AzureVirtualNetworkResourceType,
+	"azure:storage/account:Account":                    resourceazure.AzureStorageAccountResourceType,
+	"gcp:storage/bucket:Bucket":                        resourcegoogle.GoogleStorageBucketResourceType,
+	"github:index/repository:Repository":               resourcegithub.GithubRepositoryResourceType,
+	"github:index/team:Team":                           resourcegithub.GithubTeamResourceType,
+}
+
+// stackExport holds the parts of the output of "pulumi stack export" we rely on
+type stackExport struct {
+	Version    int `json:"version"`
+	Deployment *struct {
+		Resources []stackResource `json:"resources"`
+	} `json:"deployment"`
+}
+
+type stackResource struct {
+	Urn    string `json:"urn"`
+	Custom bool   `json:"custom"`
+	Delete bool   `json:"delete"`
+	Id     string `json:"id"`
+	Type   string `json:"type"`
+}
+
+type PulumiStackReader struct {
+	config         config.SupplierConfig
+	backendOptions *backend.Options
+	progress       output.Progress
+	filter         filter.Filter
+}
+
+func NewReader(config config.SupplierConfig, backendOpts *backend.Options, progress output.Progress, filter filter.Filter) (*PulumiStackReader, error) {
+	if backend.IsStateStore(config.Backend) {
+		return nil, errors.Errorf("backend '%s' is not supported by the %s IaC source", config.Backend, PulumiStackReaderSupplier)
+	}
+	return &PulumiStackReader{
+		config:         config,
+		backendOptions: backendOpts,
+		progress:       progress,
+		filter:         filter,
+	}, nil
+}
+
+func (r *PulumiStackReader) Resources() ([]*resource.Resource, error) {
+	logrus.WithFields(logrus.Fields{
+		"path":    r.config.Path,
+		"backend": r.config.Backend,
+	}).Debug("Reading resources from pulumi stack export")
+	r.progress.Inc()
+
+	export, err := r.read()
+	if err != nil {
+		return nil, errors.Wrap(err, r.config.String())
+	}
+
+	results := make([]*resource.Resource, 0, len(export.Deployment.Resources))
+	for _, stackRes := range export.Deployment.Resources {
+		// Component and provider resources do not exist on the cloud provider
+		if !stackRes.Custom || stackRes.Delete || stackRes.Id == "" {
+			continue
+		}
+		ty, supported := pulumiTypes[stackRes.Type]
+		if !supported {
+			logrus.WithFields(logrus.Fields{
+				"urn":  stackRes.Urn,
+				"type": stackRes.Type,
+			}).Debug("Ignored unsupported resource from pulumi stack")
+			continue
+		}
+		if r.filter != nil && r.filter.IsTypeIgnored(resource.ResourceType(ty)) {
+			logrus.WithFields(logrus.Fields{
+				"urn":  stackRes.Urn,
+				"type": ty,
+			}).Debug("Ignored resource from pulumi stack since it is ignored in filter")
+			continue
+		}
+
+		stack, name := parseUrn(stackRes.Urn)
+		results = append(results, &resource.Resource{
+			Id:     stackRes.Id,
+			Type:   ty,
+			Attrs:  &resource.Attributes{},
+			Source: resource.NewPulumiStackSource(r.config.String(), stack, name),
+		})
+	}
+
+	return results, nil
+}
+
+func (r *PulumiStackReader) read() (*stackExport, error) {
+	b, err := backend.GetBackend(r.config, r.backendOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer b.Close()
+
+	export := &stackExport{}
+	if err := json.NewDecoder(b).Decode(export); err != nil {
+		return nil, errors.Wrap(err, "unable to parse pulumi stack export")
+	}
+	if export.Deployment == nil {
+		return nil, errors.New("given file is not a valid pulumi stack export, use 'pulumi stack export' to generate it")
+	}
+	return export, nil
+}
+
+// parseUrn extracts the stack and resource names from urn:pulumi:<stack>::<project>::<type>::<name>
+func parseUrn(urn string) (string, string) {
+	parts := strings.Split(urn, "::")
+	if len(parts) < 4 {
+		return "", urn
+	}
+	return strings.TrimPrefix(parts[0], "urn:pulumi:"), parts[len(parts)-1]
+}
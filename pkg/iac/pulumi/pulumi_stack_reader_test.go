@@ -0,0 +1,121 @@
+package pulumi
+
+import (
+	"testing"
+
+	"github.com/cloudskiff/driftctl/pkg/filter"
+	"github.com/cloudskiff/driftctl/pkg/iac/config"
+	"github.com/cloudskiff/driftctl/pkg/output"
+	"github.com/cloudskiff/driftctl/pkg/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestPulumiStackReader_Resources(t *testing.T) {
+	cases := []struct {
+		name     string
+		path     string
+		mocks    func(f *filter.MockFilter)
+		expected []*resource.Resource
+		err      string
+	}{
+		{
+			name: "test stack export",
+			path: "testdata/stack.json",
+			mocks: func(f *filter.MockFilter) {
+				f.On("IsTypeIgnored", mock.Anything).Return(false)
+			},
+			expected: []*resource.Resource{
+				{
+					Id:     "logs-4d1e2f3",
+					Type:   "aws_s3_bucket",
+					Attrs:  &resource.Attributes{},
+					Source: resource.NewPulumiStackSource("pulumi://testdata/stack.json", "dev", "logs"),
+				},
+				{
+					Id:     "logs-4d1e2f3",
+					Type:   "aws_s3_bucket_policy",
+					Attrs:  &resource.Attributes{},
+					Source: resource.NewPulumiStackSource("pulumi://testdata/stack.json", "dev", "logs-policy"),
+				},
+				{
+					Id:     "i-0123456789abcdef0",
+					Type:   "aws_instance",
+					Attrs:  &resource.Attributes{},
+					Source: resource.NewPulumiStackSource("pulumi://testdata/stack.json", "dev", "web"),
+				},
+				{
+					Id:     "db-instance",
+					Type:   "aws_db_instance",
+					Attrs:  &resource.Attributes{},
+					Source: resource.NewPulumiStackSource("pulumi://testdata/stack.json", "dev", "db"),
+				},
+			},
+		},
+		{
+			name: "test filtered resource types",
+			path: "testdata/stack.json",
+			mocks: func(f *filter.MockFilter) {
+				f.On("IsTypeIgnored", resource.ResourceType("aws_instance")).Return(false)
+				f.On("IsTypeIgnored", mock.Anything).Return(true)
+			},
+			expected: []*resource.Resource{
+				{
+					Id:     "i-0123456789abcdef0",
+					Type:   "aws_instance",
+					Attrs:  &resource.Attributes{},
+					Source: resource.NewPulumiStackSource("pulumi://testdata/stack.json", "dev", "web"),
+				},
+			},
+		},
+		{
+			name:  "test invalid stack export",
+			path:  "testdata/invalid.json",
+			mocks: func(f *filter.MockFilter) {},
+			err:   "pulumi://testdata/invalid.json: given file is not a valid pulumi stack export, use 'pulumi stack export' to generate it",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			f := &filter.MockFilter{}
+			c.mocks(f)
+			progress := &output.MockProgress{}
+			progress.On("Inc").Return().Times(1)
+
+			reader, err := NewReader(config.SupplierConfig{
+				Key:  PulumiStackReaderSupplier,
+				Path: c.path,
+			}, nil, progress, f)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			got, err := reader.Resources()
+			progress.AssertExpectations(t)
+			if c.err != "" {
+				assert.EqualError(t, err, c.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, c.expected, got)
+		})
+	}
+}
+
+func TestPulumiTypes(t *testing.T) {
+	for token, ty := range pulumiTypes {
+		assert.True(t, resource.IsResourceTypeSupported(ty), "%s is mapped to the unsupported type %s", token, ty)
+	}
+
+	// Types whose driftctl ids are built by middlewares would be reported as both missing and unmanaged
+	for _, token := range []string{
+		"aws:iam/rolePolicyAttachment:RolePolicyAttachment",
+		"aws:ec2/securityGroupRule:SecurityGroupRule",
+		"aws:route53/record:Record",
+		"aws:ec2/route:Route",
+	} {
+		_, exist := pulumiTypes[token]
+		assert.False(t, exist, token)
+	}
+}
@@ -11,15 +11,25 @@ import (
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 
+	"github.com/cloudskiff/driftctl/pkg/iac/cloudformation"
 	"github.com/cloudskiff/driftctl/pkg/iac/config"
+	"github.com/cloudskiff/driftctl/pkg/iac/pulumi"
 
 	"github.com/cloudskiff/driftctl/pkg/iac/terraform/state"
+	"github.com/cloudskiff/driftctl/pkg/iac/terraform/tfbackend"
+	"github.com/cloudskiff/driftctl/pkg/iac/terraform/tfconfig"
+	"github.com/cloudskiff/driftctl/pkg/iac/terraform/tfplan"
 
 	"github.com/cloudskiff/driftctl/pkg/resource"
 )
 
 var supportedSuppliers = []string{
 	state.TerraformStateReaderSupplier,
+	tfbackend.TerraformBackendReaderSupplier,
+	tfconfig.TerraformConfigReaderSupplier,
+	tfplan.TerraformPlanReaderSupplier,
+	pulumi.PulumiStackReaderSupplier,
+	cloudformation.CloudformationStackReaderSupplier,
 }
 
 func IsSupplierSupported(supplierKey string) bool {
@@ -52,6 +62,16 @@ func GetIACSupplier(configs []config.SupplierConfig,
 		switch config.Key {
 		case state.TerraformStateReaderSupplier:
 			supplier, err = state.NewReader(config, library, backendOpts, progress, alerter, deserializer, filter)
+		case tfbackend.TerraformBackendReaderSupplier:
+			supplier, err = tfbackend.NewReader(config, library, backendOpts, progress, alerter, deserializer, filter)
+		case tfconfig.TerraformConfigReaderSupplier:
+			supplier, err = tfconfig.NewReader(config, filter)
+		case tfplan.TerraformPlanReaderSupplier:
+			supplier, err = tfplan.NewReader(config, library, backendOpts, progress, deserializer, filter)
+		case pulumi.PulumiStackReaderSupplier:
+			supplier, err = pulumi.NewReader(config, backendOpts, progress, filter)
+		case cloudformation.CloudformationStackReaderSupplier:
+			supplier, err = cloudformation.NewReader(config, backendOpts, progress, filter)
 		default:
 			return nil, errors.Errorf("Unsupported supplier '%s'", config.Key)
 		}
@@ -76,13 +96,31 @@ func GetSupportedSuppliers() []string {
 }
 
 func GetSupportedSchemes() []string {
-	schemes := []string{
-		"tfstate://",
-	}
+	schemes := make([]string, 0)
 	for _, supplier := range supportedSuppliers {
-		for _, backend := range backend.GetSupportedBackends() {
+		schemes = append(schemes, fmt.Sprintf("%s://", supplier))
+		for _, backend := range getSupportedBackends(supplier) {
 			schemes = append(schemes, fmt.Sprintf("%s+%s://", supplier, backend))
 		}
 	}
 	return schemes
 }
+
+// getSupportedBackends returns the backends a supplier can read from in addition to local files
+func getSupportedBackends(supplierKey string) []string {
+	switch supplierKey {
+	case state.TerraformStateReaderSupplier:
+		return backend.GetSupportedBackends()
+	case tfbackend.TerraformBackendReaderSupplier, tfconfig.TerraformConfigReaderSupplier:
+		return nil
+	}
+
+	// Other suppliers read exported files, they cannot be read from backends storing terraform states only
+	backends := make([]string, 0)
+	for _, b := range backend.GetSupportedBackends() {
+		if !backend.IsStateStore(b) {
+			backends = append(backends, b)
+		}
+	}
+	return backends
+}
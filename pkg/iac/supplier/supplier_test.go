@@ -69,6 +69,67 @@ func TestGetIACSupplier(t *testing.T) {
 			},
 			wantErr: nil,
 		},
+		{
+			name: "test tfbackend with backend",
+			args: args{
+				config: []config.SupplierConfig{
+					{Key: "tfbackend", Backend: "s3", Path: "infra"},
+				},
+				options: &backend.Options{
+					Headers: map[string]string{},
+				},
+			},
+			wantErr: fmt.Errorf("backends are not supported by the tfbackend IaC source"),
+		},
+		{
+			name: "test valid tfconfig://infra",
+			args: args{
+				config: []config.SupplierConfig{
+					{Key: "tfconfig", Backend: "", Path: "infra"},
+				},
+				options: &backend.Options{
+					Headers: map[string]string{},
+				},
+			},
+			wantErr: nil,
+		},
+		{
+			name: "test tfconfig with backend",
+			args: args{
+				config: []config.SupplierConfig{
+					{Key: "tfconfig", Backend: "s3", Path: "infra"},
+				},
+				options: &backend.Options{
+					Headers: map[string]string{},
+				},
+			},
+			wantErr: fmt.Errorf("backends are not supported by the tfconfig IaC source"),
+		},
+		{
+			name: "test valid tfplan://plan.json",
+			args: args{
+				config: []config.SupplierConfig{
+					{Key: "tfplan", Backend: "", Path: "plan.json"},
+				},
+				options: &backend.Options{
+					Headers: map[string]string{},
+				},
+			},
+			wantErr: nil,
+		},
+		{
+			name: "test valid pulumi and cloudformation sources",
+			args: args{
+				config: []config.SupplierConfig{
+					{Key: "pulumi", Backend: "", Path: "stack.json"},
+					{Key: "cloudformation", Backend: "s3", Path: "bucket/stack-resources.json"},
+				},
+				options: &backend.Options{
+					Headers: map[string]string{},
+				},
+			},
+			wantErr: nil,
+		},
 		{
 			name: "test valid multiples states",
 			args: args{
@@ -110,9 +171,34 @@ func TestGetSupportedSchemes(t *testing.T) {
 	want := []string{
 		"tfstate://",
 		"tfstate+s3://",
+		"tfstate+gs://",
+		"tfstate+azurerm://",
 		"tfstate+http://",
 		"tfstate+https://",
 		"tfstate+tfcloud://",
+		"tfstate+consul://",
+		"tfstate+pg://",
+		"tfstate+kubernetes://",
+		"tfbackend://",
+		"tfconfig://",
+		"tfplan://",
+		"tfplan+s3://",
+		"tfplan+gs://",
+		"tfplan+azurerm://",
+		"tfplan+http://",
+		"tfplan+https://",
+		"pulumi://",
+		"pulumi+s3://",
+		"pulumi+gs://",
+		"pulumi+azurerm://",
+		"pulumi+http://",
+		"pulumi+https://",
+		"cloudformation://",
+		"cloudformation+s3://",
+		"cloudformation+gs://",
+		"cloudformation+azurerm://",
+		"cloudformation+http://",
+		"cloudformation+https://",
 	}
 
 	if got := GetSupportedSchemes(); !reflect.DeepEqual(got, want) {
@@ -0,0 +1,151 @@
+package backend
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+	"github.com/pkg/errors"
+)
+
+const (
+	azureStorageOAuthScope = "https://storage.azure.com/.default"
+
+	// Shared key of the storage account, when not set the azure credential chain is used
+	AzureStorageKeyEnv = "AZURE_STORAGE_KEY"
+	// Overrides the blob endpoint of the storage account, e.g. http://127.0.0.1:10000/devstoreaccount1 for Azurite
+	AzureStorageBlobEndpointEnv = "AZURE_STORAGE_BLOB_ENDPOINT"
+)
+
+type AzureBlob struct {
+	Name string
+	Size int64
+}
+
+// AzureBlobClient wraps the blob storage operations needed to read states
+type AzureBlobClient struct {
+	service azblob.ServiceClient
+}
+
+// NewAzureBlobClient authenticates with the storage account shared key when set in AZURE_STORAGE_KEY,
+// otherwise with the same credential chain as the azurerm remote
+func NewAzureBlobClient(account string) (*AzureBlobClient, error) {
+	endpoint := os.Getenv(AzureStorageBlobEndpointEnv)
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://%s.blob.core.windows.net", account)
+	}
+
+	if key := os.Getenv(AzureStorageKeyEnv); key != "" {
+		return NewAzureBlobClientWithSharedKey(endpoint, account, key)
+	}
+
+	cred, err := azidentity.NewDefaultAzureCredential(&azidentity.DefaultAzureCredentialOptions{})
+	if err != nil {
+		return nil, err
+	}
+	service, err := azblob.NewServiceClient(endpoint, azureStorageCredential{cred}, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &AzureBlobClient{service: service}, nil
+}
+
+func NewAzureBlobClientWithSharedKey(endpoint, account, key string) (*AzureBlobClient, error) {
+	cred, err := azblob.NewSharedKeyCredential(account, key)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid storage account key")
+	}
+	service, err := azblob.NewServiceClient(strings.TrimSuffix(endpoint, "/"), cred, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &AzureBlobClient{service: service}, nil
+}
+
+func (c *AzureBlobClient) GetBlob(ctx context.Context, container, name string) (io.ReadCloser, error) {
+	blob := c.service.NewContainerClient(container).NewBlobClient(name)
+	res, err := blob.Download(ctx, nil)
+	if err != nil {
+		return nil, azureError(err)
+	}
+	return res.Body(azblob.RetryReaderOptions{}), nil
+}
+
+func (c *AzureBlobClient) ListBlobs(ctx context.Context, container, prefix string) ([]AzureBlob, error) {
+	containerClient := c.service.NewContainerClient(container)
+	blobs := make([]AzureBlob, 0)
+	marker := ""
+	for {
+		options := &azblob.ContainerListBlobFlatSegmentOptions{}
+		if prefix != "" {
+			options.Prefix = &prefix
+		}
+		if marker != "" {
+			options.Marker = &marker
+		}
+
+		// The pager of this SDK version mistakes the next marker for an url,
+		// so a new pager is started from the marker of each page
+		pager := containerClient.ListBlobsFlat(options)
+		if !pager.NextPage(ctx) {
+			if err := pager.Err(); err != nil {
+				return nil, azureError(err)
+			}
+			return blobs, nil
+		}
+
+		page := pager.PageResponse()
+		if page.Segment != nil {
+			for _, blob := range page.Segment.BlobItems {
+				if blob.Name == nil {
+					continue
+				}
+				item := AzureBlob{Name: *blob.Name}
+				if blob.Properties != nil && blob.Properties.ContentLength != nil {
+					item.Size = *blob.Properties.ContentLength
+				}
+				blobs = append(blobs, item)
+			}
+		}
+		if page.NextMarker == nil || *page.NextMarker == "" {
+			return blobs, nil
+		}
+		marker = *page.NextMarker
+	}
+}
+
+// azureError shortens storage errors of the SDK, which embed the whole request and response, to their status and code
+func azureError(err error) error {
+	var storageErr *azblob.StorageError
+	if errors.As(err, &storageErr) && storageErr.ErrorCode != "" {
+		return errors.Errorf("%d %s (%s)", storageErr.StatusCode(), http.StatusText(storageErr.StatusCode()), storageErr.ErrorCode)
+	}
+	var responseErr azblob.ResponseError
+	if errors.As(err, &responseErr) && responseErr.RawResponse() != nil {
+		res := responseErr.RawResponse()
+		code := res.Header.Get("x-ms-error-code")
+		if code == "" {
+			code = "unknown error"
+		}
+		return errors.Errorf("%s (%s)", res.Status, code)
+	}
+	return err
+}
+
+// azureStorageCredential requests tokens for the storage scope, this SDK version does not set any scope
+type azureStorageCredential struct {
+	azcore.TokenCredential
+}
+
+func (c azureStorageCredential) NewAuthenticationPolicy(options runtime.AuthenticationOptions) policy.Policy {
+	options.TokenRequest.Scopes = []string{azureStorageOAuthScope}
+	return c.TokenCredential.NewAuthenticationPolicy(options)
+}
@@ -0,0 +1,62 @@
+package backend
+
+import (
+	"context"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const BackendKeyAzureRM = "azurerm"
+
+type AzureRMBackend struct {
+	account   string
+	container string
+	blob      string
+	reader    io.ReadCloser
+	client    *AzureBlobClient
+}
+
+func NewAzureRMReader(path string) (*AzureRMBackend, error) {
+	blobPath := strings.Split(path, "/")
+	if len(blobPath) < 3 {
+		return nil, errors.Errorf("Unable to parse azurerm path: %s. Must be STORAGE_ACCOUNT/CONTAINER/PATH/TO/BLOB", path)
+	}
+
+	return &AzureRMBackend{
+		account:   blobPath[0],
+		container: blobPath[1],
+		blob:      strings.Join(blobPath[2:], "/"),
+	}, nil
+}
+
+func (a *AzureRMBackend) Read(p []byte) (n int, err error) {
+	if a.reader == nil {
+		if a.client == nil {
+			a.client, err = NewAzureBlobClient(a.account)
+			if err != nil {
+				return 0, errors.Wrap(err, "Unable to create azure blob client")
+			}
+		}
+		reader, err := a.client.GetBlob(context.Background(), a.container, a.blob)
+		if err != nil {
+			return 0, errors.Errorf(
+				"Error reading state '%s' from azurerm container '%s' of storage account '%s': %s",
+				a.blob,
+				a.container,
+				a.account,
+				err,
+			)
+		}
+		a.reader = reader
+	}
+	return a.reader.Read(p)
+}
+
+func (a *AzureRMBackend) Close() error {
+	if a.reader != nil {
+		return a.reader.Close()
+	}
+	return errors.New("Unable to close reader as nothing was opened")
+}
@@ -0,0 +1,98 @@
+package backend
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	azurermtest "github.com/cloudskiff/driftctl/test/azurerm"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewAzureRMReaderInvalid(t *testing.T) {
+	got, err := NewAzureRMReader("account/container")
+	assert.Nil(t, got)
+	assert.EqualError(t, err, "Unable to parse azurerm path: account/container. Must be STORAGE_ACCOUNT/CONTAINER/PATH/TO/BLOB")
+}
+
+func TestNewAzureRMReader(t *testing.T) {
+	reader, err := NewAzureRMReader("account/container/path/to/state.tfstate")
+	assert.NoError(t, err)
+	assert.Equal(t, "account", reader.account)
+	assert.Equal(t, "container", reader.container)
+	assert.Equal(t, "path/to/state.tfstate", reader.blob)
+}
+
+func TestAzureRMBackend_Read(t *testing.T) {
+	state, err := ioutil.ReadFile("testdata/valid.tfstate")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		key     string
+		want    []byte
+		wantErr string
+	}{
+		{
+			name: "test read state",
+			path: "devstoreaccount1/states/path/to/state.tfstate",
+			key:  azurermtest.AzuriteKey,
+			want: state,
+		},
+		{
+			name: "test read state with special characters",
+			path: "devstoreaccount1/states/env:/100%?#/state.tfstate",
+			key:  azurermtest.AzuriteKey,
+			want: state,
+		},
+		{
+			name:    "test unknown state",
+			path:    "devstoreaccount1/states/path/to/unknown.tfstate",
+			key:     azurermtest.AzuriteKey,
+			wantErr: "Error reading state 'path/to/unknown.tfstate' from azurerm container 'states' of storage account 'devstoreaccount1': 404 Not Found (BlobNotFound)",
+		},
+		{
+			name:    "test unknown container",
+			path:    "devstoreaccount1/unknown/state.tfstate",
+			key:     azurermtest.AzuriteKey,
+			wantErr: "Error reading state 'state.tfstate' from azurerm container 'unknown' of storage account 'devstoreaccount1': 404 Not Found (ContainerNotFound)",
+		},
+		{
+			name:    "test invalid key",
+			path:    "devstoreaccount1/states/path/to/state.tfstate",
+			key:     "not base64",
+			wantErr: "Unable to create azure blob client: invalid storage account key: decode account key: illegal base64 data at input byte 3",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, endpoint := azurermtest.NewFakeBlobServer(map[string]map[string][]byte{
+				"states": {
+					"path/to/state.tfstate":     state,
+					"env:/100%?#/state.tfstate": state,
+				},
+			})
+			defer server.Close()
+			os.Setenv(AzureStorageBlobEndpointEnv, endpoint)
+			os.Setenv(AzureStorageKeyEnv, tt.key)
+			defer os.Unsetenv(AzureStorageBlobEndpointEnv)
+			defer os.Unsetenv(AzureStorageKeyEnv)
+
+			reader, err := NewAzureRMReader(tt.path)
+			if !assert.NoError(t, err) {
+				return
+			}
+			got, err := ioutil.ReadAll(reader)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.NoError(t, reader.Close())
+		})
+	}
+}
@@ -4,17 +4,32 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 
 	"github.com/cloudskiff/driftctl/pkg/iac/config"
+	"github.com/cloudskiff/driftctl/pkg/iac/terraform/state/decryption"
 	"github.com/pkg/errors"
 )
 
 var supportedBackends = []string{
 	BackendKeyFile,
 	BackendKeyS3,
+	BackendKeyGS,
+	BackendKeyAzureRM,
 	BackendKeyHTTP,
 	BackendKeyHTTPS,
 	BackendKeyTFCloud,
+	BackendKeyConsul,
+	BackendKeyPg,
+	BackendKeyKubernetes,
+}
+
+// Those backends store terraform states only, they cannot be used to read other kind of files
+var stateStoreBackends = []string{
+	BackendKeyTFCloud,
+	BackendKeyConsul,
+	BackendKeyPg,
+	BackendKeyKubernetes,
 }
 
 type Backend io.ReadCloser
@@ -23,6 +38,18 @@ type Options struct {
 	Headers         map[string]string
 	TFCloudToken    string
 	TFCloudEndpoint string
+	// AllWorkspaces expands state locations into the states of every terraform workspace
+	AllWorkspaces bool
+	// WorkspaceKeyPrefix is the prefix of non default workspaces states in s3 buckets
+	WorkspaceKeyPrefix string
+	// TFCloudWorkspacePrefix restricts the terraform cloud workspaces expanded with AllWorkspaces
+	// to those of a remote backend configured with a workspaces prefix
+	TFCloudWorkspacePrefix string
+	// StateDecryption holds the keys of client-side encrypted states
+	StateDecryption decryption.Options
+	// PgCredentials are the credentials of the pg backend configuration of a tfbackend source,
+	// they are kept out of the state location so they never show up in the output
+	PgCredentials *url.Userinfo
 }
 
 func IsSupported(backend string) bool {
@@ -35,6 +62,16 @@ func IsSupported(backend string) bool {
 	return false
 }
 
+func IsStateStore(backend string) bool {
+	for _, b := range stateStoreBackends {
+		if b == backend {
+			return true
+		}
+	}
+
+	return false
+}
+
 func GetBackend(config config.SupplierConfig, opts *Options) (Backend, error) {
 	backend := config.Backend
 
@@ -47,12 +84,22 @@ func GetBackend(config config.SupplierConfig, opts *Options) (Backend, error) {
 		return NewFileReader(config.Path)
 	case BackendKeyS3:
 		return NewS3Reader(config.Path)
+	case BackendKeyGS:
+		return NewGSReader(config.Path)
+	case BackendKeyAzureRM:
+		return NewAzureRMReader(config.Path)
 	case BackendKeyHTTP:
 		fallthrough
 	case BackendKeyHTTPS:
 		return NewHTTPReader(&http.Client{}, fmt.Sprintf("%s://%s", config.Backend, config.Path), opts)
 	case BackendKeyTFCloud:
 		return NewTFCloudReader(&http.Client{}, config.Path, opts)
+	case BackendKeyConsul:
+		return NewConsulReader(config.Path)
+	case BackendKeyPg:
+		return NewPgReader(config.Path, opts)
+	case BackendKeyKubernetes:
+		return NewKubernetesReader(config.Path)
 	default:
 		return nil, errors.Errorf("Unsupported backend '%s'", backend)
 	}
@@ -0,0 +1,181 @@
+package backend
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/md5"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	pkghttp "github.com/cloudskiff/driftctl/pkg/http"
+	"github.com/pkg/errors"
+)
+
+const BackendKeyConsul = "consul"
+
+// ConsulClient reads keys through the consul HTTP API,
+// it honors the CONSUL_HTTP_TOKEN and CONSUL_HTTP_SSL env vars like terraform does
+type ConsulClient struct {
+	client  pkghttp.HTTPClient
+	baseURL string
+	token   string
+}
+
+func NewConsulClient(client pkghttp.HTTPClient, address string) *ConsulClient {
+	scheme := "http"
+	if ssl, _ := strconv.ParseBool(os.Getenv("CONSUL_HTTP_SSL")); ssl {
+		scheme = "https"
+	}
+	return &ConsulClient{
+		client:  client,
+		baseURL: fmt.Sprintf("%s://%s/v1/kv/", scheme, address),
+		token:   os.Getenv("CONSUL_HTTP_TOKEN"),
+	}
+}
+
+// Get returns the raw value of a key, nil is returned when the key does not exist
+func (c *ConsulClient) Get(key string) ([]byte, error) {
+	res, err := c.do(key, url.Values{"raw": {""}})
+	if err != nil || res == nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	return ioutil.ReadAll(res.Body)
+}
+
+// Keys lists the keys starting with the given prefix
+func (c *ConsulClient) Keys(prefix string) ([]string, error) {
+	res, err := c.do(prefix, url.Values{"keys": {""}})
+	if err != nil || res == nil {
+		return []string{}, err
+	}
+	defer res.Body.Close()
+	keys := make([]string, 0)
+	if err := json.NewDecoder(res.Body).Decode(&keys); err != nil {
+		return nil, errors.Wrap(err, "unable to parse consul keys")
+	}
+	return keys, nil
+}
+
+func (c *ConsulClient) do(key string, query url.Values) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, c.baseURL+strings.TrimPrefix(key, "/"), nil)
+	if err != nil {
+		return nil, err
+	}
+	// consul flags are query parameters without value
+	req.URL.RawQuery = strings.ReplaceAll(query.Encode(), "=", "")
+	if c.token != "" {
+		req.Header.Set("X-Consul-Token", c.token)
+	}
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode == http.StatusNotFound {
+		res.Body.Close()
+		return nil, nil
+	}
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		defer res.Body.Close()
+		body, _ := ioutil.ReadAll(res.Body)
+		return nil, errors.Errorf("consul responded with status code %d: %s", res.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return res, nil
+}
+
+type ConsulBackend struct {
+	key    string
+	reader io.ReadCloser
+	client *ConsulClient
+}
+
+func NewConsulReader(path string) (*ConsulBackend, error) {
+	addressPath := strings.SplitN(path, "/", 2)
+	if len(addressPath) < 2 || addressPath[1] == "" {
+		return nil, errors.Errorf("Unable to parse consul path: %s. Must be ADDRESS/PATH/TO/KEY", path)
+	}
+
+	return &ConsulBackend{
+		key:    addressPath[1],
+		client: NewConsulClient(&http.Client{}, addressPath[0]),
+	}, nil
+}
+
+func (c *ConsulBackend) Read(p []byte) (n int, err error) {
+	if c.reader == nil {
+		payload, err := c.read()
+		if err != nil {
+			return 0, errors.Errorf("Error reading state '%s' from consul: %s", c.key, err)
+		}
+		c.reader = ioutil.NopCloser(bytes.NewReader(payload))
+	}
+	return c.reader.Read(p)
+}
+
+// read follows the storage format of the terraform consul backend: large states are split
+// into chunks listed in the value of the key, and states may be gzipped
+func (c *ConsulBackend) read() ([]byte, error) {
+	value, err := c.client.Get(c.key)
+	if err != nil {
+		return nil, err
+	}
+	if value == nil {
+		return nil, errors.New("key not found")
+	}
+
+	payload := value
+	chunked := struct {
+		Hash   string   `json:"current-hash"`
+		Chunks []string `json:"chunks"`
+	}{}
+	if err := json.Unmarshal(value, &chunked); err == nil && chunked.Hash != "" {
+		payload = make([]byte, 0)
+		for _, chunk := range chunked.Chunks {
+			chunkValue, err := c.client.Get(chunk)
+			if err != nil {
+				return nil, err
+			}
+			if chunkValue == nil {
+				return nil, errors.Errorf("chunk %s not found", chunk)
+			}
+			payload = append(payload, chunkValue...)
+		}
+	}
+
+	if len(payload) > 0 && payload[0] == '\x1f' {
+		payload, err = gunzip(payload)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if chunked.Hash != "" && fmt.Sprintf("%x", md5.Sum(payload)) != chunked.Hash {
+		return nil, errors.New("state does not match the expected hash")
+	}
+
+	return payload, nil
+}
+
+func (c *ConsulBackend) Close() error {
+	if c.reader != nil {
+		return c.reader.Close()
+	}
+	return errors.New("Unable to close reader as nothing was opened")
+}
+
+func gunzip(data []byte) ([]byte, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+	return ioutil.ReadAll(gz)
+}
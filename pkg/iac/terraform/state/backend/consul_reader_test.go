@@ -0,0 +1,116 @@
+package backend
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/md5"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	consultest "github.com/cloudskiff/driftctl/test/consul"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewConsulReaderInvalid(t *testing.T) {
+	got, err := NewConsulReader("localhost:8500")
+	assert.Nil(t, got)
+	assert.EqualError(t, err, "Unable to parse consul path: localhost:8500. Must be ADDRESS/PATH/TO/KEY")
+}
+
+func TestConsulBackend_Read(t *testing.T) {
+	state, err := ioutil.ReadFile("testdata/valid.tfstate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	gzipped := &bytes.Buffer{}
+	gz := gzip.NewWriter(gzipped)
+	_, _ = gz.Write(state)
+	_ = gz.Close()
+	hash := fmt.Sprintf("%x", md5.Sum(state))
+	half := len(gzipped.Bytes()) / 2
+
+	kv := map[string][]byte{
+		"states/plain":   state,
+		"states/gzipped": gzipped.Bytes(),
+		"states/chunked": []byte(fmt.Sprintf(`{"current-hash":"%s","chunks":["states/chunked/tfstate.%s/0","states/chunked/tfstate.%s/1"]}`, hash, hash, hash)),
+		fmt.Sprintf("states/chunked/tfstate.%s/0", hash): gzipped.Bytes()[:half],
+		fmt.Sprintf("states/chunked/tfstate.%s/1", hash): gzipped.Bytes()[half:],
+		"states/corrupted": []byte(fmt.Sprintf(`{"current-hash":"invalid","chunks":["states/chunked/tfstate.%s/0","states/chunked/tfstate.%s/1"]}`, hash, hash)),
+		"states/missing":   []byte(`{"current-hash":"invalid","chunks":["states/missing/tfstate.invalid/0"]}`),
+	}
+
+	tests := []struct {
+		name    string
+		key     string
+		token   string
+		want    []byte
+		wantErr string
+	}{
+		{
+			name:  "test plain state",
+			key:   "states/plain",
+			token: consultest.Token,
+			want:  state,
+		},
+		{
+			name:  "test gzipped state",
+			key:   "states/gzipped",
+			token: consultest.Token,
+			want:  state,
+		},
+		{
+			name:  "test chunked state",
+			key:   "states/chunked",
+			token: consultest.Token,
+			want:  state,
+		},
+		{
+			name:    "test chunked state with invalid hash",
+			key:     "states/corrupted",
+			token:   consultest.Token,
+			wantErr: "Error reading state 'states/corrupted' from consul: state does not match the expected hash",
+		},
+		{
+			name:    "test chunked state with missing chunk",
+			key:     "states/missing",
+			token:   consultest.Token,
+			wantErr: "Error reading state 'states/missing' from consul: chunk states/missing/tfstate.invalid/0 not found",
+		},
+		{
+			name:    "test unknown key",
+			key:     "states/unknown",
+			token:   consultest.Token,
+			wantErr: "Error reading state 'states/unknown' from consul: key not found",
+		},
+		{
+			name:    "test invalid token",
+			key:     "states/plain",
+			token:   "invalid",
+			wantErr: "Error reading state 'states/plain' from consul: consul responded with status code 403: Permission denied",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := consultest.NewFakeKVServer(kv)
+			defer server.Close()
+			os.Setenv("CONSUL_HTTP_TOKEN", tt.token)
+			defer os.Unsetenv("CONSUL_HTTP_TOKEN")
+
+			reader, err := NewConsulReader(strings.TrimPrefix(server.URL, "http://") + "/" + tt.key)
+			if !assert.NoError(t, err) {
+				return
+			}
+			got, err := ioutil.ReadAll(reader)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.NoError(t, reader.Close())
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package backend
+
+import (
+	"context"
+	"io"
+	"strings"
+
+	"cloud.google.com/go/storage"
+	"github.com/cloudskiff/driftctl/pkg/envproxy"
+	"github.com/pkg/errors"
+	"google.golang.org/api/option"
+)
+
+const BackendKeyGS = "gs"
+
+type GSBackend struct {
+	bucket        string
+	object        string
+	reader        io.ReadCloser
+	client        *storage.Client
+	clientOptions []option.ClientOption
+}
+
+func NewGSReader(path string, opts ...option.ClientOption) (*GSBackend, error) {
+	bucketPath := strings.Split(path, "/")
+	if len(bucketPath) < 2 {
+		return nil, errors.Errorf("Unable to parse GS path: %s. Must be BUCKET_NAME/PATH/TO/OBJECT", path)
+	}
+
+	return &GSBackend{
+		bucket:        bucketPath[0],
+		object:        strings.Join(bucketPath[1:], "/"),
+		clientOptions: opts,
+	}, nil
+}
+
+// NewGSClient creates a storage client, DCTL_GS_ prefixed env vars take precedence over GOOGLE_ ones
+// so credentials used to read states can differ from the ones used to scan
+func NewGSClient(ctx context.Context, opts ...option.ClientOption) (*storage.Client, error) {
+	envProxy := envproxy.NewEnvProxy("DCTL_GS_", "GOOGLE_")
+	envProxy.Apply()
+	defer envProxy.Restore()
+	return storage.NewClient(ctx, opts...)
+}
+
+func (g *GSBackend) Read(p []byte) (n int, err error) {
+	if g.reader == nil {
+		ctx := context.Background()
+		if g.client == nil {
+			g.client, err = NewGSClient(ctx, g.clientOptions...)
+			if err != nil {
+				return 0, errors.Wrap(err, "Unable to create GS client")
+			}
+		}
+		reader, err := g.client.Bucket(g.bucket).Object(g.object).NewReader(ctx)
+		if err != nil {
+			return 0, errors.Errorf(
+				"Error reading state '%s' from gs bucket '%s': %s",
+				g.object,
+				g.bucket,
+				err,
+			)
+		}
+		g.reader = reader
+	}
+	return g.reader.Read(p)
+}
+
+func (g *GSBackend) Close() error {
+	if g.client != nil {
+		defer g.client.Close()
+	}
+	if g.reader != nil {
+		return g.reader.Close()
+	}
+	return errors.New("Unable to close reader as nothing was opened")
+}
@@ -0,0 +1,70 @@
+package backend
+
+import (
+	"io/ioutil"
+	"testing"
+
+	googletest "github.com/cloudskiff/driftctl/test/google"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewGSReaderInvalid(t *testing.T) {
+	got, err := NewGSReader("foobar")
+	assert.Nil(t, got)
+	assert.EqualError(t, err, "Unable to parse GS path: foobar. Must be BUCKET_NAME/PATH/TO/OBJECT")
+}
+
+func TestNewGSReader(t *testing.T) {
+	reader, err := NewGSReader("sample_bucket/path/to/state.tfstate")
+	assert.NoError(t, err)
+	assert.Equal(t, "sample_bucket", reader.bucket)
+	assert.Equal(t, "path/to/state.tfstate", reader.object)
+}
+
+func TestGSBackend_Read(t *testing.T) {
+	state, err := ioutil.ReadFile("testdata/valid.tfstate")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		want    []byte
+		wantErr string
+	}{
+		{
+			name: "test read state",
+			path: "sample_bucket/path/to/state.tfstate",
+			want: state,
+		},
+		{
+			name:    "test unknown state",
+			path:    "sample_bucket/path/to/unknown.tfstate",
+			wantErr: "Error reading state 'path/to/unknown.tfstate' from gs bucket 'sample_bucket': storage: object doesn't exist",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, opts := googletest.NewFakeStorageServer(map[string]map[string][]byte{
+				"sample_bucket": {
+					"path/to/state.tfstate": state,
+				},
+			})
+			defer server.Close()
+
+			reader, err := NewGSReader(tt.path, opts...)
+			if !assert.NoError(t, err) {
+				return
+			}
+			got, err := ioutil.ReadAll(reader)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.NoError(t, reader.Close())
+		})
+	}
+}
@@ -0,0 +1,413 @@
+package backend
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/mitchellh/go-homedir"
+	"github.com/pkg/errors"
+)
+
+const BackendKeyKubernetes = "kubernetes"
+
+const (
+	kubernetesStateKey         = "tfstate"
+	kubernetesStateLabel       = "tfstate=true"
+	kubernetesServiceAccount   = "/var/run/secrets/kubernetes.io/serviceaccount"
+	kubernetesConfigPathEnv    = "KUBE_CONFIG_PATH"
+	kubernetesConfigContextEnv = "KUBE_CTX"
+)
+
+type kubeconfig struct {
+	CurrentContext string `json:"current-context"`
+	Contexts       []struct {
+		Name    string `json:"name"`
+		Context struct {
+			Cluster string `json:"cluster"`
+			User    string `json:"user"`
+		} `json:"context"`
+	} `json:"contexts"`
+	Clusters []struct {
+		Name    string `json:"name"`
+		Cluster struct {
+			Server                   string `json:"server"`
+			CertificateAuthority     string `json:"certificate-authority"`
+			CertificateAuthorityData string `json:"certificate-authority-data"`
+			InsecureSkipTLSVerify    bool   `json:"insecure-skip-tls-verify"`
+		} `json:"cluster"`
+	} `json:"clusters"`
+	Users []struct {
+		Name string `json:"name"`
+		User struct {
+			Token                 string          `json:"token"`
+			ClientCertificate     string          `json:"client-certificate"`
+			ClientCertificateData string          `json:"client-certificate-data"`
+			ClientKey             string          `json:"client-key"`
+			ClientKeyData         string          `json:"client-key-data"`
+			Exec                  *kubeconfigExec `json:"exec"`
+		} `json:"user"`
+	} `json:"users"`
+}
+
+// kubeconfigExec configures a credential plugin, used to authenticate on EKS, GKE or AKS clusters
+type kubeconfigExec struct {
+	APIVersion string   `json:"apiVersion"`
+	Command    string   `json:"command"`
+	Args       []string `json:"args"`
+	Env        []struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+	} `json:"env"`
+	InstallHint string `json:"installHint"`
+}
+
+// execCredential is the output of credential plugins
+type execCredential struct {
+	Status *struct {
+		Token                 string `json:"token"`
+		ClientCertificateData string `json:"clientCertificateData"`
+		ClientKeyData         string `json:"clientKeyData"`
+	} `json:"status"`
+}
+
+type kubernetesSecret struct {
+	Metadata struct {
+		Name string `json:"name"`
+	} `json:"metadata"`
+	Data map[string]string `json:"data"`
+}
+
+type kubernetesSecretList struct {
+	Items    []kubernetesSecret `json:"items"`
+	Metadata struct {
+		Continue string `json:"continue"`
+	} `json:"metadata"`
+}
+
+// KubernetesClient reads secrets through the kubernetes API
+type KubernetesClient struct {
+	client *http.Client
+	server string
+	token  string
+}
+
+func NewKubernetesClient(client *http.Client, server, token string) *KubernetesClient {
+	return &KubernetesClient{
+		client: client,
+		server: strings.TrimSuffix(server, "/"),
+		token:  token,
+	}
+}
+
+// NewKubernetesClientFromConfig uses the kubeconfig file found in KUBE_CONFIG_PATH, KUBECONFIG or ~/.kube/config,
+// the context can be overridden with KUBE_CTX. Service account credentials are used when running in a pod.
+func NewKubernetesClientFromConfig() (*KubernetesClient, error) {
+	path, err := kubeconfigPath()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(path); os.IsNotExist(err) && os.Getenv("KUBERNETES_SERVICE_HOST") != "" {
+		return newInClusterKubernetesClient()
+	}
+
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read kubeconfig")
+	}
+	config := kubeconfig{}
+	if err := yaml.Unmarshal(content, &config); err != nil {
+		return nil, errors.Wrap(err, "unable to parse kubeconfig")
+	}
+	return newKubernetesClientFromKubeconfig(config, os.Getenv(kubernetesConfigContextEnv), filepath.Dir(path))
+}
+
+func kubeconfigPath() (string, error) {
+	if path := os.Getenv(kubernetesConfigPathEnv); path != "" {
+		return path, nil
+	}
+	if paths := filepath.SplitList(os.Getenv("KUBECONFIG")); len(paths) > 0 {
+		return paths[0], nil
+	}
+	return homedir.Expand("~/.kube/config")
+}
+
+func newKubernetesClientFromKubeconfig(config kubeconfig, contextName, dir string) (*KubernetesClient, error) {
+	if contextName == "" {
+		contextName = config.CurrentContext
+	}
+	var clusterName, userName string
+	found := false
+	for _, c := range config.Contexts {
+		if c.Name == contextName {
+			clusterName, userName, found = c.Context.Cluster, c.Context.User, true
+			break
+		}
+	}
+	if !found {
+		return nil, errors.Errorf("context '%s' not found in kubeconfig", contextName)
+	}
+
+	tlsConfig := &tls.Config{}
+	server := ""
+	for _, c := range config.Clusters {
+		if c.Name != clusterName {
+			continue
+		}
+		server = c.Cluster.Server
+		tlsConfig.InsecureSkipVerify = c.Cluster.InsecureSkipTLSVerify
+		ca, err := kubeconfigData(c.Cluster.CertificateAuthorityData, c.Cluster.CertificateAuthority, dir)
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to read cluster certificate authority")
+		}
+		if ca != nil {
+			tlsConfig.RootCAs = x509.NewCertPool()
+			tlsConfig.RootCAs.AppendCertsFromPEM(ca)
+		}
+	}
+	if server == "" {
+		return nil, errors.Errorf("cluster '%s' not found in kubeconfig", clusterName)
+	}
+
+	token := ""
+	for _, u := range config.Users {
+		if u.Name != userName {
+			continue
+		}
+		token = u.User.Token
+		cert, err := kubeconfigData(u.User.ClientCertificateData, u.User.ClientCertificate, dir)
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to read client certificate")
+		}
+		key, err := kubeconfigData(u.User.ClientKeyData, u.User.ClientKey, dir)
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to read client key")
+		}
+		if u.User.Exec != nil {
+			credential, err := runExecCredentialPlugin(u.User.Exec, dir)
+			if err != nil {
+				return nil, errors.Wrapf(err, "unable to get credentials of user '%s' from exec plugin %s", userName, u.User.Exec.Command)
+			}
+			if credential.Status.Token != "" {
+				token = credential.Status.Token
+			}
+			if credential.Status.ClientCertificateData != "" && credential.Status.ClientKeyData != "" {
+				cert, key = []byte(credential.Status.ClientCertificateData), []byte(credential.Status.ClientKeyData)
+			}
+		}
+		if cert != nil && key != nil {
+			pair, err := tls.X509KeyPair(cert, key)
+			if err != nil {
+				return nil, errors.Wrap(err, "invalid client certificate")
+			}
+			tlsConfig.Certificates = []tls.Certificate{pair}
+		}
+	}
+
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig}}
+	return NewKubernetesClient(client, server, token), nil
+}
+
+// runExecCredentialPlugin runs the credential plugin of a kubeconfig user like kubectl does,
+// see https://kubernetes.io/docs/reference/access-authn-authz/authentication/#client-go-credential-plugins
+func runExecCredentialPlugin(plugin *kubeconfigExec, dir string) (*execCredential, error) {
+	// Commands with a relative path are relative to the kubeconfig, others are looked up in PATH
+	command := plugin.Command
+	if strings.ContainsRune(command, filepath.Separator) && !filepath.IsAbs(command) {
+		command = filepath.Join(dir, command)
+	}
+
+	execInfo, err := json.Marshal(map[string]interface{}{
+		"apiVersion": plugin.APIVersion,
+		"kind":       "ExecCredential",
+		"spec":       map[string]interface{}{"interactive": false},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := exec.Command(command, plugin.Args...)
+	cmd.Env = os.Environ()
+	for _, env := range plugin.Env {
+		cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", env.Name, env.Value))
+	}
+	cmd.Env = append(cmd.Env, fmt.Sprintf("KUBERNETES_EXEC_INFO=%s", execInfo))
+	stderr := &bytes.Buffer{}
+	cmd.Stderr = stderr
+
+	output, err := cmd.Output()
+	if err != nil {
+		if errors.Is(err, exec.ErrNotFound) && plugin.InstallHint != "" {
+			return nil, errors.Errorf("%s\n%s", err, strings.TrimSpace(plugin.InstallHint))
+		}
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return nil, errors.Errorf("%s: %s", err, message)
+		}
+		return nil, err
+	}
+
+	credential := &execCredential{}
+	if err := json.Unmarshal(output, credential); err != nil {
+		return nil, errors.Wrap(err, "invalid plugin output")
+	}
+	if credential.Status == nil || (credential.Status.Token == "" && credential.Status.ClientCertificateData == "") {
+		return nil, errors.New("plugin output has neither a token nor a client certificate")
+	}
+	return credential, nil
+}
+
+// kubeconfigData returns base64 inline data or the content of a file relative to the kubeconfig
+func kubeconfigData(data, path, dir string) ([]byte, error) {
+	if data != "" {
+		return base64.StdEncoding.DecodeString(data)
+	}
+	if path == "" {
+		return nil, nil
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+	return ioutil.ReadFile(path)
+}
+
+func newInClusterKubernetesClient() (*KubernetesClient, error) {
+	token, err := ioutil.ReadFile(filepath.Join(kubernetesServiceAccount, "token"))
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read service account token")
+	}
+	ca, err := ioutil.ReadFile(filepath.Join(kubernetesServiceAccount, "ca.crt"))
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read service account certificate authority")
+	}
+	pool := x509.NewCertPool()
+	pool.AppendCertsFromPEM(ca)
+
+	server := fmt.Sprintf("https://%s:%s", os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT"))
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool}}}
+	return NewKubernetesClient(client, server, strings.TrimSpace(string(token))), nil
+}
+
+func (c *KubernetesClient) getSecret(namespace, name string) (*kubernetesSecret, error) {
+	secret := &kubernetesSecret{}
+	err := c.get(fmt.Sprintf("/api/v1/namespaces/%s/secrets/%s", url.PathEscape(namespace), url.PathEscape(name)), nil, secret)
+	return secret, err
+}
+
+// ListStateSecrets returns the names of the secrets labelled as terraform states
+func (c *KubernetesClient) ListStateSecrets(namespace string) ([]string, error) {
+	names := make([]string, 0)
+	query := url.Values{"labelSelector": {kubernetesStateLabel}}
+	for {
+		list := &kubernetesSecretList{}
+		if err := c.get(fmt.Sprintf("/api/v1/namespaces/%s/secrets", url.PathEscape(namespace)), query, list); err != nil {
+			return nil, err
+		}
+		for _, secret := range list.Items {
+			names = append(names, secret.Metadata.Name)
+		}
+		if list.Metadata.Continue == "" {
+			return names, nil
+		}
+		query.Set("continue", list.Metadata.Continue)
+	}
+}
+
+func (c *KubernetesClient) get(path string, query url.Values, out interface{}) error {
+	req, err := http.NewRequest(http.MethodGet, c.server+path, nil)
+	if err != nil {
+		return err
+	}
+	req.URL.RawQuery = query.Encode()
+	req.Header.Set("Accept", "application/json")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		status := struct {
+			Message string `json:"message"`
+		}{}
+		if err := json.NewDecoder(res.Body).Decode(&status); err != nil || status.Message == "" {
+			status.Message = res.Status
+		}
+		return errors.New(status.Message)
+	}
+	return json.NewDecoder(res.Body).Decode(out)
+}
+
+type KubernetesBackend struct {
+	namespace string
+	secret    string
+	reader    io.ReadCloser
+	client    *KubernetesClient
+}
+
+func NewKubernetesReader(path string) (*KubernetesBackend, error) {
+	namespaceSecret := strings.Split(path, "/")
+	if len(namespaceSecret) != 2 || namespaceSecret[0] == "" || namespaceSecret[1] == "" {
+		return nil, errors.Errorf("Unable to parse kubernetes path: %s. Must be NAMESPACE/SECRET_NAME", path)
+	}
+
+	return &KubernetesBackend{
+		namespace: namespaceSecret[0],
+		secret:    namespaceSecret[1],
+	}, nil
+}
+
+func (k *KubernetesBackend) Read(p []byte) (n int, err error) {
+	if k.reader == nil {
+		if k.client == nil {
+			k.client, err = NewKubernetesClientFromConfig()
+			if err != nil {
+				return 0, errors.Wrap(err, "Unable to create kubernetes client")
+			}
+		}
+		payload, err := k.read()
+		if err != nil {
+			return 0, errors.Errorf("Error reading state from secret '%s' of namespace '%s': %s", k.secret, k.namespace, err)
+		}
+		k.reader = ioutil.NopCloser(bytes.NewReader(payload))
+	}
+	return k.reader.Read(p)
+}
+
+// read decodes states stored by the terraform kubernetes backend, gzipped in the tfstate key of the secret
+func (k *KubernetesBackend) read() ([]byte, error) {
+	secret, err := k.client.getSecret(k.namespace, k.secret)
+	if err != nil {
+		return nil, err
+	}
+	data, exist := secret.Data[kubernetesStateKey]
+	if !exist {
+		return nil, errors.Errorf("secret has no %s key", kubernetesStateKey)
+	}
+	decoded, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return nil, err
+	}
+	return gunzip(decoded)
+}
+
+func (k *KubernetesBackend) Close() error {
+	if k.reader != nil {
+		return k.reader.Close()
+	}
+	return errors.New("Unable to close reader as nothing was opened")
+}
@@ -0,0 +1,170 @@
+package backend
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newFakeKubernetesServer(secrets map[string]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer kube-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_ = json.NewEncoder(w).Encode(map[string]string{"message": "Unauthorized"})
+			return
+		}
+		for name, data := range secrets {
+			if r.URL.Path == "/api/v1/namespaces/terraform/secrets/"+name {
+				_ = json.NewEncoder(w).Encode(map[string]interface{}{
+					"metadata": map[string]string{"name": name},
+					"data":     map[string]string{"tfstate": data},
+				})
+				return
+			}
+		}
+		w.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(w).Encode(map[string]string{"message": `secrets "unknown" not found`})
+	}))
+}
+
+func TestNewKubernetesReaderInvalid(t *testing.T) {
+	got, err := NewKubernetesReader("terraform")
+	assert.Nil(t, got)
+	assert.EqualError(t, err, "Unable to parse kubernetes path: terraform. Must be NAMESPACE/SECRET_NAME")
+}
+
+func TestKubernetesBackend_Read(t *testing.T) {
+	state, err := ioutil.ReadFile("testdata/valid.tfstate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	gzipped := &bytes.Buffer{}
+	gz := gzip.NewWriter(gzipped)
+	_, _ = gz.Write(state)
+	_ = gz.Close()
+
+	tests := []struct {
+		name    string
+		path    string
+		token   string
+		want    []byte
+		wantErr string
+	}{
+		{
+			name:  "test read state",
+			path:  "terraform/tfstate-default-infra",
+			token: "kube-token",
+			want:  state,
+		},
+		{
+			name:    "test invalid state",
+			path:    "terraform/tfstate-default-invalid",
+			token:   "kube-token",
+			wantErr: "Error reading state from secret 'tfstate-default-invalid' of namespace 'terraform': gzip: invalid header",
+		},
+		{
+			name:    "test unknown secret",
+			path:    "terraform/unknown",
+			token:   "kube-token",
+			wantErr: "Error reading state from secret 'unknown' of namespace 'terraform': secrets \"unknown\" not found",
+		},
+		{
+			name:    "test unauthorized",
+			path:    "terraform/tfstate-default-infra",
+			token:   "invalid",
+			wantErr: "Error reading state from secret 'tfstate-default-infra' of namespace 'terraform': Unauthorized",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newFakeKubernetesServer(map[string]string{
+				"tfstate-default-infra":   base64.StdEncoding.EncodeToString(gzipped.Bytes()),
+				"tfstate-default-invalid": base64.StdEncoding.EncodeToString(state),
+			})
+			defer server.Close()
+
+			reader, err := NewKubernetesReader(tt.path)
+			if !assert.NoError(t, err) {
+				return
+			}
+			reader.client = NewKubernetesClient(server.Client(), server.URL, tt.token)
+
+			got, err := ioutil.ReadAll(reader)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.NoError(t, reader.Close())
+		})
+	}
+}
+
+func TestNewKubernetesClientFromConfig(t *testing.T) {
+	tests := []struct {
+		name       string
+		context    string
+		wantServer string
+		wantToken  string
+		wantErr    string
+	}{
+		{
+			name:       "test current context",
+			wantServer: "https://dev.example.com:6443",
+			wantToken:  "dev-token",
+		},
+		{
+			name:       "test context from env",
+			context:    "prod",
+			wantServer: "https://prod.example.com",
+			wantToken:  "prod-token",
+		},
+		{
+			name:    "test unknown context",
+			context: "unknown",
+			wantErr: "context 'unknown' not found in kubeconfig",
+		},
+		{
+			name:       "test exec credential plugin",
+			context:    "eks",
+			wantServer: "https://prod.example.com",
+			wantToken:  "eks-token",
+		},
+		{
+			name:    "test failing exec credential plugin",
+			context: "failing",
+			wantErr: "unable to get credentials of user 'failing' from exec plugin ./exec-plugin.sh: exit status 1: error: you must be logged in to the server",
+		},
+		{
+			name:    "test missing exec credential plugin",
+			context: "not-installed",
+			wantErr: "unable to get credentials of user 'not-installed' from exec plugin driftctl-test-unknown-plugin: exec: \"driftctl-test-unknown-plugin\": executable file not found in $PATH\nInstall the plugin to read states from this cluster",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Setenv(kubernetesConfigPathEnv, "testdata/kubernetes/kubeconfig")
+			os.Setenv(kubernetesConfigContextEnv, tt.context)
+			defer os.Unsetenv(kubernetesConfigPathEnv)
+			defer os.Unsetenv(kubernetesConfigContextEnv)
+
+			got, err := NewKubernetesClientFromConfig()
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantServer, got.server)
+			assert.Equal(t, tt.wantToken, got.token)
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package backend
+
+import (
+	"bytes"
+	"database/sql"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"strings"
+
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+)
+
+const BackendKeyPg = "pg"
+
+const (
+	pgDefaultSchema    = "terraform_remote_state"
+	pgDefaultWorkspace = "default"
+	pgStatesTable      = "states"
+)
+
+// PgConfig is parsed from HOST[:PORT]/DATABASE[?PARAMS], schema_name and workspace parameters select the state
+// like the terraform pg backend does, the other ones are given to the driver.
+// Credentials should be given using the PGUSER and PGPASSWORD env vars to keep them out of the output.
+type PgConfig struct {
+	ConnStr   string
+	Schema    string
+	Workspace string
+}
+
+func ParsePgPath(path string) (*PgConfig, error) {
+	u, err := url.Parse("postgres://" + path)
+	if err != nil || u.Host == "" || strings.Trim(u.Path, "/") == "" {
+		return nil, errors.Errorf("Unable to parse pg path: %s. Must be HOST[:PORT]/DATABASE[?schema_name=SCHEMA&workspace=WORKSPACE]", path)
+	}
+
+	query := u.Query()
+	config := &PgConfig{
+		Schema:    query.Get("schema_name"),
+		Workspace: query.Get("workspace"),
+	}
+	if config.Schema == "" {
+		config.Schema = pgDefaultSchema
+	}
+	if config.Workspace == "" {
+		config.Workspace = pgDefaultWorkspace
+	}
+	query.Del("schema_name")
+	query.Del("workspace")
+	u.RawQuery = query.Encode()
+	config.ConnStr = u.String()
+
+	return config, nil
+}
+
+// PgWorkspacePath returns the pg path of another workspace of the same database
+func PgWorkspacePath(path, workspace string) string {
+	u, _ := url.Parse("postgres://" + path)
+	query := u.Query()
+	query.Set("workspace", workspace)
+	u.RawQuery = query.Encode()
+	return strings.TrimPrefix(u.String(), "postgres://")
+}
+
+func (c *PgConfig) StatesTable() string {
+	return fmt.Sprintf("%s.%s", pq.QuoteIdentifier(c.Schema), pgStatesTable)
+}
+
+// OpenPgDatabase connects with the given credentials when set, the driver reads PGUSER and PGPASSWORD otherwise
+func OpenPgDatabase(config *PgConfig, credentials *url.Userinfo) (*sql.DB, error) {
+	connStr, err := config.connStrWith(credentials)
+	if err != nil {
+		return nil, err
+	}
+	return sql.Open("postgres", connStr)
+}
+
+func (c *PgConfig) connStrWith(credentials *url.Userinfo) (string, error) {
+	if credentials == nil {
+		return c.ConnStr, nil
+	}
+	u, err := url.Parse(c.ConnStr)
+	if err != nil {
+		return "", err
+	}
+	u.User = credentials
+	return u.String(), nil
+}
+
+type PgBackend struct {
+	config      *PgConfig
+	credentials *url.Userinfo
+	db          *sql.DB
+	reader      io.ReadCloser
+}
+
+func NewPgReader(path string, opts *Options) (*PgBackend, error) {
+	config, err := ParsePgPath(path)
+	if err != nil {
+		return nil, err
+	}
+	reader := &PgBackend{
+		config: config,
+	}
+	if opts != nil {
+		reader.credentials = opts.PgCredentials
+	}
+	return reader, nil
+}
+
+func (p *PgBackend) Read(b []byte) (n int, err error) {
+	if p.reader == nil {
+		if p.db == nil {
+			p.db, err = OpenPgDatabase(p.config, p.credentials)
+			if err != nil {
+				return 0, errors.Wrap(err, "Unable to connect to pg")
+			}
+		}
+
+		var data []byte
+		err = p.db.QueryRow(fmt.Sprintf("SELECT data FROM %s WHERE name = $1", p.config.StatesTable()), p.config.Workspace).Scan(&data)
+		if err == sql.ErrNoRows {
+			return 0, errors.Errorf("Error reading state of workspace '%s' from pg schema '%s': state not found", p.config.Workspace, p.config.Schema)
+		}
+		if err != nil {
+			return 0, errors.Errorf("Error reading state of workspace '%s' from pg schema '%s': %s", p.config.Workspace, p.config.Schema, err)
+		}
+		p.reader = ioutil.NopCloser(bytes.NewReader(data))
+	}
+	return p.reader.Read(b)
+}
+
+func (p *PgBackend) Close() error {
+	if p.db != nil {
+		defer p.db.Close()
+	}
+	if p.reader != nil {
+		return p.reader.Close()
+	}
+	return errors.New("Unable to close reader as nothing was opened")
+}
@@ -0,0 +1,12 @@
+#!/bin/sh
+if [ "$1" = "fail" ]; then
+  echo "error: you must be logged in to the server" >&2
+  exit 1
+fi
+case "$KUBERNETES_EXEC_INFO" in
+  *'"kind":"ExecCredential"'*) ;;
+  *) echo "missing KUBERNETES_EXEC_INFO" >&2; exit 1 ;;
+esac
+cat <<JSON
+{"apiVersion":"client.authentication.k8s.io/v1beta1","kind":"ExecCredential","status":{"token":"$PLUGIN_TOKEN"}}
+JSON
@@ -44,7 +44,12 @@ func NewTFCloudReader(client pkghttp.HTTPClient, workspaceId string, opts *Optio
 }
 
 func (t *TFCloudBackend) authorize() error {
-	token := t.opts.TFCloudToken
+	return authorizeTFCloudRequest(t.request, t.opts)
+}
+
+// authorizeTFCloudRequest uses the token given in options or the one of the terraform CLI configuration for the request host
+func authorizeTFCloudRequest(req *http.Request, opts *Options) error {
+	token := opts.TFCloudToken
 	if token == "" {
 		tfConfigFile, err := getTerraformConfigFile()
 		if err != nil {
@@ -56,12 +61,12 @@ func (t *TFCloudBackend) authorize() error {
 		}
 		defer file.Close()
 		reader := NewTFCloudConfigReader(file)
-		token, err = reader.GetToken(t.request.URL.Host)
+		token, err = reader.GetToken(req.URL.Host)
 		if err != nil {
 			return err
 		}
 	}
-	t.request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
+	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
 	return nil
 }
 
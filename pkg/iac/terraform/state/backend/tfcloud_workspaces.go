@@ -0,0 +1,110 @@
+package backend
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	pkghttp "github.com/cloudskiff/driftctl/pkg/http"
+	"github.com/pkg/errors"
+)
+
+type TFCloudWorkspace struct {
+	Id   string
+	Name string
+}
+
+type tfcloudWorkspacesBody struct {
+	Data []struct {
+		Id         string `json:"id"`
+		Attributes struct {
+			Name string `json:"name"`
+		} `json:"attributes"`
+	} `json:"data"`
+	Meta struct {
+		Pagination struct {
+			NextPage *int `json:"next-page"`
+		} `json:"pagination"`
+	} `json:"meta"`
+}
+
+// ListTFCloudWorkspaces returns every workspace of a terraform cloud organization
+func ListTFCloudWorkspaces(client pkghttp.HTTPClient, organization string, opts *Options) ([]TFCloudWorkspace, error) {
+	workspaces := make([]TFCloudWorkspace, 0)
+	page := 1
+	for {
+		req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/organizations/%s/workspaces", opts.TFCloudEndpoint, url.PathEscape(organization)), nil)
+		if err != nil {
+			return nil, err
+		}
+		req.URL.RawQuery = url.Values{
+			"page[number]": {fmt.Sprintf("%d", page)},
+			"page[size]":   {"100"},
+		}.Encode()
+		req.Header.Add("Content-Type", "application/vnd.api+json")
+		if err := authorizeTFCloudRequest(req, opts); err != nil {
+			return nil, err
+		}
+
+		res, err := client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		if res.StatusCode < 200 || res.StatusCode >= 400 {
+			res.Body.Close()
+			return nil, errors.Errorf("error listing terraform cloud workspaces of organization '%s': status code: %d", organization, res.StatusCode)
+		}
+
+		body := tfcloudWorkspacesBody{}
+		err = json.NewDecoder(res.Body).Decode(&body)
+		res.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		for _, workspace := range body.Data {
+			workspaces = append(workspaces, TFCloudWorkspace{
+				Id:   workspace.Id,
+				Name: workspace.Attributes.Name,
+			})
+		}
+
+		if body.Meta.Pagination.NextPage == nil {
+			return workspaces, nil
+		}
+		page = *body.Meta.Pagination.NextPage
+	}
+}
+
+type tfcloudWorkspaceBody struct {
+	Data struct {
+		Id string `json:"id"`
+	} `json:"data"`
+}
+
+// GetTFCloudWorkspaceId resolves the id of a terraform cloud workspace from its name
+func GetTFCloudWorkspaceId(client pkghttp.HTTPClient, organization, name string, opts *Options) (string, error) {
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/organizations/%s/workspaces/%s", opts.TFCloudEndpoint, url.PathEscape(organization), url.PathEscape(name)), nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Add("Content-Type", "application/vnd.api+json")
+	if err := authorizeTFCloudRequest(req, opts); err != nil {
+		return "", err
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode >= 400 {
+		return "", errors.Errorf("error reading terraform cloud workspace '%s' of organization '%s': status code: %d", name, organization, res.StatusCode)
+	}
+
+	body := tfcloudWorkspaceBody{}
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+		return "", err
+	}
+	return body.Data.Id, nil
+}
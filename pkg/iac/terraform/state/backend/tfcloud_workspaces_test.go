@@ -0,0 +1,77 @@
+package backend
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListTFCloudWorkspaces(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	opts := &Options{
+		TFCloudToken:    "TOKEN",
+		TFCloudEndpoint: "https://app.terraform.io/api/v2",
+	}
+
+	tests := []struct {
+		name     string
+		mock     func()
+		expected []TFCloudWorkspace
+		wantErr  string
+	}{
+		{
+			name: "Should follow pagination",
+			mock: func() {
+				httpmock.Reset()
+				httpmock.RegisterResponderWithQuery(
+					"GET",
+					"https://app.terraform.io/api/v2/organizations/org/workspaces",
+					"page%5Bnumber%5D=1&page%5Bsize%5D=100",
+					func(req *http.Request) (*http.Response, error) {
+						assert.Equal(t, "Bearer TOKEN", req.Header.Get("Authorization"))
+						return httpmock.NewStringResponse(http.StatusOK, `{"data":[{"id":"ws-1","attributes":{"name":"infra"}}],"meta":{"pagination":{"next-page":2}}}`), nil
+					},
+				)
+				httpmock.RegisterResponderWithQuery(
+					"GET",
+					"https://app.terraform.io/api/v2/organizations/org/workspaces",
+					"page%5Bnumber%5D=2&page%5Bsize%5D=100",
+					httpmock.NewStringResponder(http.StatusOK, `{"data":[{"id":"ws-2","attributes":{"name":"network"}}],"meta":{"pagination":{"next-page":null}}}`),
+				)
+			},
+			expected: []TFCloudWorkspace{
+				{Id: "ws-1", Name: "infra"},
+				{Id: "ws-2", Name: "network"},
+			},
+		},
+		{
+			name: "Should fail with wrong organization",
+			mock: func() {
+				httpmock.Reset()
+				httpmock.RegisterResponder(
+					"GET",
+					"https://app.terraform.io/api/v2/organizations/org/workspaces",
+					httpmock.NewStringResponder(http.StatusNotFound, `{"errors":[{"status":"404","title":"not found"}]}`),
+				)
+			},
+			wantErr: "error listing terraform cloud workspaces of organization 'org': status code: 404",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := ListTFCloudWorkspaces(&http.Client{}, "org", opts)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
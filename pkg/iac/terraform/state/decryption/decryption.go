@@ -0,0 +1,62 @@
+package decryption
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Options holds the keys used to decrypt client-side encrypted states
+type Options struct {
+	// TofuPassphrase is the passphrase of the OpenTofu pbkdf2 key provider
+	TofuPassphrase string
+	// SopsAgeKeyFile contains age identities, SOPS_AGE_KEY and SOPS_AGE_KEY_FILE env vars are used otherwise
+	SopsAgeKeyFile string
+	// SopsPGPKeyFile contains an armored or binary PGP secret keyring
+	SopsPGPKeyFile string
+}
+
+// Decrypter handles a format of encrypted state
+type Decrypter interface {
+	// Format names the encryption format in logs
+	Format() string
+	// Detect returns true when the content is encrypted using the format of the decrypter
+	Detect(content []byte) bool
+	Decrypt(content []byte) ([]byte, error)
+}
+
+func GetDecrypters(opts *Options) []Decrypter {
+	if opts == nil {
+		opts = &Options{}
+	}
+	return []Decrypter{
+		NewTofuDecrypter(opts.TofuPassphrase),
+		NewSopsDecrypter(opts.SopsAgeKeyFile, opts.SopsPGPKeyFile),
+	}
+}
+
+// NewReader returns the plaintext content of a state, states that are not encrypted are returned as is
+func NewReader(reader io.Reader, opts *Options) (io.Reader, error) {
+	content, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, decrypter := range GetDecrypters(opts) {
+		if !decrypter.Detect(content) {
+			continue
+		}
+		logrus.WithFields(logrus.Fields{
+			"format": decrypter.Format(),
+		}).Debug("Found encrypted state")
+		content, err = decrypter.Decrypt(content)
+		if err != nil {
+			return nil, err
+		}
+		break
+	}
+
+	return bytes.NewReader(content), nil
+}
@@ -0,0 +1,125 @@
+package decryption
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewReader(t *testing.T) {
+	plain, err := ioutil.ReadFile("testdata/plain.tfstate")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	tests := []struct {
+		name string
+		path string
+		opts *Options
+		err  string
+	}{
+		{
+			name: "test plaintext state",
+			path: "testdata/plain.tfstate",
+		},
+		{
+			name: "test OpenTofu encrypted state",
+			path: "testdata/tofu.tfstate",
+			opts: &Options{TofuPassphrase: "correct-horse-battery-staple"},
+		},
+		{
+			name: "test OpenTofu encrypted state without passphrase",
+			path: "testdata/tofu.tfstate",
+			err:  "state is encrypted by OpenTofu, a passphrase must be given with --tofu-passphrase",
+		},
+		{
+			name: "test OpenTofu encrypted state with wrong passphrase",
+			path: "testdata/tofu.tfstate",
+			opts: &Options{TofuPassphrase: "wrong"},
+			err:  "unable to decrypt state encrypted by OpenTofu, check the passphrase",
+		},
+		{
+			name: "test sops encrypted state with age key",
+			path: "testdata/sops.tfstate",
+			opts: &Options{SopsAgeKeyFile: "testdata/age.txt"},
+		},
+		{
+			name: "test sops encrypted state with PGP key",
+			path: "testdata/sops.tfstate",
+			opts: &Options{SopsAgeKeyFile: "testdata/other-age.txt", SopsPGPKeyFile: "testdata/pgp.asc"},
+		},
+		{
+			name: "test sops encrypted state without matching key",
+			path: "testdata/sops.tfstate",
+			opts: &Options{SopsAgeKeyFile: "testdata/other-age.txt"},
+			err:  "unable to decrypt sops data key, no matching age or PGP key was found",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file, err := os.Open(tt.path)
+			if !assert.NoError(t, err) {
+				return
+			}
+			defer file.Close()
+
+			reader, err := NewReader(file, tt.opts)
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+				return
+			}
+			if !assert.NoError(t, err) {
+				return
+			}
+			got, err := ioutil.ReadAll(reader)
+			assert.NoError(t, err)
+			assert.JSONEq(t, string(plain), string(got))
+		})
+	}
+}
+
+func TestSopsDecrypter_AgeKeyEnv(t *testing.T) {
+	key, err := ioutil.ReadFile("testdata/age.txt")
+	if !assert.NoError(t, err) {
+		return
+	}
+	os.Setenv("SOPS_AGE_KEY", string(key))
+	defer os.Unsetenv("SOPS_AGE_KEY")
+
+	content, err := ioutil.ReadFile("testdata/sops.tfstate")
+	if !assert.NoError(t, err) {
+		return
+	}
+	d := NewSopsDecrypter("", "")
+	assert.True(t, d.Detect(content))
+	_, err = d.Decrypt(content)
+	assert.NoError(t, err)
+}
+
+func TestDecrypters_Detect(t *testing.T) {
+	plain, err := ioutil.ReadFile("testdata/plain.tfstate")
+	if !assert.NoError(t, err) {
+		return
+	}
+	tofu, err := ioutil.ReadFile("testdata/tofu.tfstate")
+	if !assert.NoError(t, err) {
+		return
+	}
+	sops, err := ioutil.ReadFile("testdata/sops.tfstate")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	tofuDecrypter := NewTofuDecrypter("")
+	assert.False(t, tofuDecrypter.Detect(plain))
+	assert.True(t, tofuDecrypter.Detect(tofu))
+	assert.False(t, tofuDecrypter.Detect(sops))
+
+	sopsDecrypter := NewSopsDecrypter("", "")
+	assert.False(t, sopsDecrypter.Detect(plain))
+	assert.False(t, sopsDecrypter.Detect(tofu))
+	assert.True(t, sopsDecrypter.Detect(sops))
+	assert.False(t, sopsDecrypter.Detect([]byte("not json")))
+}
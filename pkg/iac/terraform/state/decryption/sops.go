@@ -0,0 +1,277 @@
+package decryption
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"filippo.io/age"
+	agearmor "filippo.io/age/armor"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/crypto/openpgp"                //nolint:staticcheck
+	pgparmor "golang.org/x/crypto/openpgp/armor" //nolint:staticcheck
+)
+
+const (
+	sopsMetadataKey    = "sops"
+	sopsAgeKeyEnv      = "SOPS_AGE_KEY"
+	sopsAgeKeyFileEnv  = "SOPS_AGE_KEY_FILE"
+	sopsAgeKeyUserPath = "sops/age/keys.txt"
+)
+
+var sopsEncryptedValue = regexp.MustCompile(`^ENC\[AES256_GCM,data:(.+),iv:(.+),tag:(.+),type:(.+)\]`)
+
+// sopsMetadata is the part of the sops metadata needed to retrieve the data key
+type sopsMetadata struct {
+	Mac     string `json:"mac"`
+	AgeKeys []struct {
+		Recipient        string `json:"recipient"`
+		EncryptedDataKey string `json:"enc"`
+	} `json:"age"`
+	PGPKeys []struct {
+		Fingerprint      string `json:"fp"`
+		EncryptedDataKey string `json:"enc"`
+	} `json:"pgp"`
+}
+
+// SopsDecrypter decrypts JSON states encrypted with sops using local age or PGP keys.
+// Values are authenticated one by one using AES-GCM, the MAC of the whole document is not verified.
+type SopsDecrypter struct {
+	ageKeyFile string
+	pgpKeyFile string
+}
+
+func NewSopsDecrypter(ageKeyFile, pgpKeyFile string) *SopsDecrypter {
+	return &SopsDecrypter{
+		ageKeyFile: ageKeyFile,
+		pgpKeyFile: pgpKeyFile,
+	}
+}
+
+func (d *SopsDecrypter) Format() string {
+	return "sops"
+}
+
+func (d *SopsDecrypter) Detect(content []byte) bool {
+	document := map[string]json.RawMessage{}
+	if err := json.Unmarshal(content, &document); err != nil {
+		return false
+	}
+	metadata := sopsMetadata{}
+	if err := json.Unmarshal(document[sopsMetadataKey], &metadata); err != nil {
+		return false
+	}
+	return metadata.Mac != ""
+}
+
+func (d *SopsDecrypter) Decrypt(content []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.UseNumber()
+	document := map[string]interface{}{}
+	if err := decoder.Decode(&document); err != nil {
+		return nil, errors.Wrap(err, "unable to parse sops encrypted state")
+	}
+
+	rawMetadata, err := json.Marshal(document[sopsMetadataKey])
+	if err != nil {
+		return nil, err
+	}
+	metadata := sopsMetadata{}
+	if err := json.Unmarshal(rawMetadata, &metadata); err != nil {
+		return nil, errors.Wrap(err, "unable to parse sops metadata")
+	}
+	delete(document, sopsMetadataKey)
+
+	key, err := d.dataKey(&metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	decrypted, err := sopsDecryptValue(document, key, nil)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(decrypted)
+}
+
+// dataKey tries every age and PGP master key of the document
+func (d *SopsDecrypter) dataKey(metadata *sopsMetadata) ([]byte, error) {
+	if len(metadata.AgeKeys) > 0 {
+		identities, err := d.ageIdentities()
+		if err != nil {
+			return nil, err
+		}
+		for _, ageKey := range metadata.AgeKeys {
+			reader, err := age.Decrypt(agearmor.NewReader(strings.NewReader(ageKey.EncryptedDataKey)), identities...)
+			if err != nil {
+				logrus.WithFields(logrus.Fields{
+					"recipient": ageKey.Recipient,
+					"err":       err,
+				}).Debug("Unable to decrypt sops data key with age")
+				continue
+			}
+			return ioutil.ReadAll(reader)
+		}
+	}
+
+	if len(metadata.PGPKeys) > 0 && d.pgpKeyFile != "" {
+		keyring, err := readPGPKeyring(d.pgpKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		for _, pgpKey := range metadata.PGPKeys {
+			block, err := pgparmor.Decode(strings.NewReader(pgpKey.EncryptedDataKey))
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid sops data key of PGP key %s", pgpKey.Fingerprint)
+			}
+			message, err := openpgp.ReadMessage(block.Body, keyring, nil, nil)
+			if err != nil {
+				logrus.WithFields(logrus.Fields{
+					"fingerprint": pgpKey.Fingerprint,
+					"err":         err,
+				}).Debug("Unable to decrypt sops data key with PGP")
+				continue
+			}
+			return ioutil.ReadAll(message.UnverifiedBody)
+		}
+	}
+
+	return nil, errors.New("unable to decrypt sops data key, no matching age or PGP key was found")
+}
+
+// ageIdentities loads identities the same way sops does
+func (d *SopsDecrypter) ageIdentities() ([]age.Identity, error) {
+	if d.ageKeyFile == "" {
+		if key, ok := os.LookupEnv(sopsAgeKeyEnv); ok {
+			return age.ParseIdentities(strings.NewReader(key))
+		}
+	}
+
+	path := d.ageKeyFile
+	if path == "" {
+		path = os.Getenv(sopsAgeKeyFileEnv)
+	}
+	if path == "" {
+		configDir, err := os.UserConfigDir()
+		if err != nil {
+			return nil, err
+		}
+		path = filepath.Join(configDir, sopsAgeKeyUserPath)
+	}
+
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	identities, err := age.ParseIdentities(file)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to read age identities from %s", path)
+	}
+	return identities, nil
+}
+
+func readPGPKeyring(path string) (openpgp.EntityList, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	keyring, err := openpgp.ReadArmoredKeyRing(bytes.NewReader(content))
+	if err != nil {
+		keyring, err = openpgp.ReadKeyRing(bytes.NewReader(content))
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to read PGP keyring from %s", path)
+	}
+	return keyring, nil
+}
+
+// sopsDecryptValue walks the document, the path of a value is authenticated along with it
+func sopsDecryptValue(value interface{}, key []byte, path []string) (interface{}, error) {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for k, v := range value {
+			decrypted, err := sopsDecryptValue(v, key, append(path, k))
+			if err != nil {
+				return nil, err
+			}
+			value[k] = decrypted
+		}
+		return value, nil
+	case []interface{}:
+		for i, v := range value {
+			decrypted, err := sopsDecryptValue(v, key, path)
+			if err != nil {
+				return nil, err
+			}
+			value[i] = decrypted
+		}
+		return value, nil
+	case string:
+		if !strings.HasPrefix(value, "ENC[") {
+			return value, nil
+		}
+		decrypted, err := sopsDecryptString(value, key, strings.Join(path, ":")+":")
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to decrypt sops value of %s", strings.Join(path, "."))
+		}
+		return decrypted, nil
+	}
+	return value, nil
+}
+
+func sopsDecryptString(value string, key []byte, additionalData string) (interface{}, error) {
+	matches := sopsEncryptedValue.FindStringSubmatch(value)
+	if matches == nil {
+		return nil, errors.New("value does not match sops format")
+	}
+	parts := make([][]byte, 0, 3)
+	for _, match := range matches[1:4] {
+		part, err := base64.StdEncoding.DecodeString(match)
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, part)
+	}
+	data, iv, tag := parts[0], parts[1], parts[2]
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCMWithNonceSize(block, len(iv))
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := gcm.Open(nil, iv, append(data, tag...), []byte(additionalData))
+	if err != nil {
+		return nil, err
+	}
+
+	switch matches[4] {
+	case "str":
+		return string(plaintext), nil
+	case "int", "float":
+		if _, err := strconv.ParseFloat(string(plaintext), 64); err != nil {
+			return nil, err
+		}
+		return json.Number(plaintext), nil
+	case "bool":
+		return strconv.ParseBool(string(plaintext))
+	case "bytes":
+		return string(plaintext), nil
+	}
+	return nil, errors.Errorf("unsupported sops value type %s", matches[4])
+}
@@ -0,0 +1,124 @@
+package decryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/json"
+	"hash"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/pbkdf2"
+)
+
+const (
+	tofuEncryptionVersion     = "v0"
+	tofuPBKDF2KeyProviderMeta = "key_provider.pbkdf2."
+)
+
+// tofuEncryptedState is the envelope written by OpenTofu when state encryption is enabled
+type tofuEncryptedState struct {
+	Meta    map[string][]byte `json:"meta"`
+	Data    []byte            `json:"encrypted_data"`
+	Version string            `json:"encryption_version"`
+}
+
+// tofuPBKDF2Metadata is stored in the envelope by the pbkdf2 key provider to derive the key again
+type tofuPBKDF2Metadata struct {
+	Salt         []byte `json:"salt"`
+	Iterations   int    `json:"iterations"`
+	HashFunction string `json:"hash_function"`
+	KeyLength    int    `json:"key_length"`
+}
+
+// TofuDecrypter decrypts states encrypted by OpenTofu using a pbkdf2 key provider and the aes_gcm method
+type TofuDecrypter struct {
+	passphrase string
+}
+
+func NewTofuDecrypter(passphrase string) *TofuDecrypter {
+	return &TofuDecrypter{passphrase}
+}
+
+func (d *TofuDecrypter) Format() string {
+	return "OpenTofu"
+}
+
+func (d *TofuDecrypter) Detect(content []byte) bool {
+	state := tofuEncryptedState{}
+	if err := json.Unmarshal(content, &state); err != nil {
+		return false
+	}
+	return state.Version != "" && state.Data != nil
+}
+
+func (d *TofuDecrypter) Decrypt(content []byte) ([]byte, error) {
+	state := tofuEncryptedState{}
+	if err := json.Unmarshal(content, &state); err != nil {
+		return nil, errors.Wrap(err, "unable to parse OpenTofu encrypted state")
+	}
+	if state.Version != tofuEncryptionVersion {
+		return nil, errors.Errorf("OpenTofu state encryption version %s is not supported", state.Version)
+	}
+	if d.passphrase == "" {
+		return nil, errors.New("state is encrypted by OpenTofu, a passphrase must be given with --tofu-passphrase")
+	}
+
+	providers := make([]string, 0, len(state.Meta))
+	for provider := range state.Meta {
+		if strings.HasPrefix(provider, tofuPBKDF2KeyProviderMeta) {
+			providers = append(providers, provider)
+		}
+	}
+	if len(providers) == 0 {
+		return nil, errors.New("state is encrypted by OpenTofu using an unsupported key provider, only pbkdf2 is supported")
+	}
+	sort.Strings(providers)
+
+	for _, provider := range providers {
+		metadata := tofuPBKDF2Metadata{}
+		if err := json.Unmarshal(state.Meta[provider], &metadata); err != nil {
+			return nil, errors.Wrapf(err, "invalid metadata of OpenTofu key provider %s", provider)
+		}
+		hashFunction, err := tofuHashFunction(metadata.HashFunction)
+		if err != nil {
+			return nil, err
+		}
+		key := pbkdf2.Key([]byte(d.passphrase), metadata.Salt, metadata.Iterations, metadata.KeyLength, hashFunction)
+		plaintext, err := decryptAESGCM(key, state.Data)
+		if err == nil {
+			return plaintext, nil
+		}
+	}
+
+	return nil, errors.New("unable to decrypt state encrypted by OpenTofu, check the passphrase")
+}
+
+func tofuHashFunction(name string) (func() hash.Hash, error) {
+	switch name {
+	case "sha256":
+		return sha256.New, nil
+	case "sha512":
+		return sha512.New, nil
+	}
+	return nil, errors.Errorf("unsupported pbkdf2 hash function '%s'", name)
+}
+
+// decryptAESGCM opens data made of the nonce followed by the sealed content
+func decryptAESGCM(key, data []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < gcm.NonceSize() {
+		return nil, errors.New("encrypted data is too short")
+	}
+	return gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], nil)
+}
@@ -0,0 +1,72 @@
+package enumerator
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"github.com/pkg/errors"
+
+	"github.com/cloudskiff/driftctl/pkg/iac/config"
+	"github.com/cloudskiff/driftctl/pkg/iac/terraform/state/backend"
+)
+
+type AzureRMEnumerator struct {
+	config config.SupplierConfig
+	client *backend.AzureBlobClient
+}
+
+func NewAzureRMEnumerator(config config.SupplierConfig) *AzureRMEnumerator {
+	return &AzureRMEnumerator{
+		config: config,
+	}
+}
+
+func (s *AzureRMEnumerator) Origin() string {
+	return s.config.String()
+}
+
+func (s *AzureRMEnumerator) Enumerate() ([]string, error) {
+	blobPath := strings.Split(s.config.Path, "/")
+	if len(blobPath) < 3 {
+		return nil, errors.Errorf("Unable to parse azurerm path: %s. Must be STORAGE_ACCOUNT/CONTAINER/PREFIX", s.config.Path)
+	}
+
+	account := blobPath[0]
+	container := blobPath[1]
+	// prefix should contains everything that does not have a glob pattern
+	// Pattern should be the glob matcher string
+	prefix, pattern := GlobS3(strings.Join(blobPath[2:], "/"))
+
+	fullPattern := strings.Join([]string{prefix, pattern}, "/")
+	fullPattern = strings.Trim(fullPattern, "/")
+
+	if s.client == nil {
+		client, err := backend.NewAzureBlobClient(account)
+		if err != nil {
+			return nil, errors.Wrap(err, "Unable to create azure blob client")
+		}
+		s.client = client
+	}
+
+	blobs, err := s.client.ListBlobs(context.Background(), container, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]string, 0)
+	for _, blob := range blobs {
+		if blob.Size > 0 {
+			if match, _ := doublestar.Match(fullPattern, blob.Name); match {
+				files = append(files, strings.Join([]string{account, container, blob.Name}, "/"))
+			}
+		}
+	}
+
+	if len(files) == 0 {
+		return files, fmt.Errorf("no Terraform state was found in %s, exiting", s.config.Path)
+	}
+
+	return files, nil
+}
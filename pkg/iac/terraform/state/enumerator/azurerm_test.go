@@ -0,0 +1,98 @@
+package enumerator
+
+import (
+	"testing"
+
+	"github.com/cloudskiff/driftctl/pkg/iac/config"
+	"github.com/cloudskiff/driftctl/pkg/iac/terraform/state/backend"
+	azurermtest "github.com/cloudskiff/driftctl/test/azurerm"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAzureRMEnumerator_Enumerate(t *testing.T) {
+	blobs := map[string]map[string][]byte{
+		"states": {
+			"a/nested/prefix/state1":                    []byte("{}"),
+			"a/nested/prefix/state2":                    []byte("{}"),
+			"a/nested/prefix/state3.tfstate":            []byte("{}"),
+			"a/nested/prefix/empty.tfstate":             {},
+			"a/nested/prefix/folder/state4.tfstate":     []byte("{}"),
+			"a/nested/prefix/folder/sub/state5.tfstate": []byte("{}"),
+			"another/prefix/state6.tfstate":             []byte("{}"),
+		},
+	}
+
+	tests := []struct {
+		name   string
+		config config.SupplierConfig
+		want   []string
+		err    string
+	}{
+		{
+			name: "test results with glob",
+			config: config.SupplierConfig{
+				Path: "devstoreaccount1/states/a/nested/prefix/*.tfstate",
+			},
+			want: []string{
+				"devstoreaccount1/states/a/nested/prefix/state3.tfstate",
+			},
+		},
+		{
+			name: "test results with double star glob across pages",
+			config: config.SupplierConfig{
+				Path: "devstoreaccount1/states/**/*.tfstate",
+			},
+			want: []string{
+				"devstoreaccount1/states/a/nested/prefix/folder/state4.tfstate",
+				"devstoreaccount1/states/a/nested/prefix/folder/sub/state5.tfstate",
+				"devstoreaccount1/states/a/nested/prefix/state3.tfstate",
+				"devstoreaccount1/states/another/prefix/state6.tfstate",
+			},
+		},
+		{
+			name: "test results without glob",
+			config: config.SupplierConfig{
+				Path: "devstoreaccount1/states/a/nested/prefix",
+			},
+			want: []string{},
+			err:  "no Terraform state was found in devstoreaccount1/states/a/nested/prefix, exiting",
+		},
+		{
+			name: "test invalid path",
+			config: config.SupplierConfig{
+				Path: "devstoreaccount1/states",
+			},
+			err: "Unable to parse azurerm path: devstoreaccount1/states. Must be STORAGE_ACCOUNT/CONTAINER/PREFIX",
+		},
+		{
+			name: "test unknown container",
+			config: config.SupplierConfig{
+				Path: "devstoreaccount1/unknown/*.tfstate",
+			},
+			err: "404 Not Found (ContainerNotFound)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, endpoint := azurermtest.NewFakeBlobServer(blobs)
+			defer server.Close()
+
+			client, err := backend.NewAzureBlobClientWithSharedKey(endpoint, azurermtest.AzuriteAccount, azurermtest.AzuriteKey)
+			if !assert.NoError(t, err) {
+				return
+			}
+			s := NewAzureRMEnumerator(tt.config)
+			s.client = client
+
+			got, err := s.Enumerate()
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+			} else {
+				assert.NoError(t, err)
+			}
+			if tt.want != nil {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
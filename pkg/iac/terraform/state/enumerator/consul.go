@@ -0,0 +1,76 @@
+package enumerator
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"github.com/pkg/errors"
+
+	"github.com/cloudskiff/driftctl/pkg/iac/config"
+	"github.com/cloudskiff/driftctl/pkg/iac/terraform/state/backend"
+)
+
+type ConsulEnumerator struct {
+	config  config.SupplierConfig
+	address string
+	client  *backend.ConsulClient
+}
+
+func NewConsulEnumerator(config config.SupplierConfig) *ConsulEnumerator {
+	address := strings.SplitN(config.Path, "/", 2)[0]
+	return &ConsulEnumerator{
+		config:  config,
+		address: address,
+		client:  backend.NewConsulClient(&http.Client{}, address),
+	}
+}
+
+func (s *ConsulEnumerator) Origin() string {
+	return s.config.String()
+}
+
+func (s *ConsulEnumerator) Enumerate() ([]string, error) {
+	addressPath := strings.SplitN(s.config.Path, "/", 2)
+	if len(addressPath) < 2 || addressPath[1] == "" {
+		return nil, errors.Errorf("Unable to parse consul path: %s. Must be ADDRESS/PREFIX", s.config.Path)
+	}
+
+	// prefix should contains everything that does not have a glob pattern
+	// Pattern should be the glob matcher string
+	prefix, pattern := GlobS3(addressPath[1])
+
+	fullPattern := strings.Join([]string{prefix, pattern}, "/")
+	fullPattern = strings.Trim(fullPattern, "/")
+
+	keys, err := s.client.Keys(prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]string, 0)
+	for _, key := range keys {
+		if isConsulStateMetadataKey(key) {
+			continue
+		}
+		if match, _ := doublestar.Match(fullPattern, key); match {
+			files = append(files, strings.Join([]string{s.address, key}, "/"))
+		}
+	}
+
+	if len(files) == 0 {
+		return files, fmt.Errorf("no Terraform state was found in %s, exiting", s.config.Path)
+	}
+
+	return files, nil
+}
+
+// isConsulStateMetadataKey returns true for keys written by terraform next to states: locks and chunks of large states
+func isConsulStateMetadataKey(key string) bool {
+	if strings.HasSuffix(key, "/") || strings.HasSuffix(key, "/.lock") || strings.HasSuffix(key, "/.lockinfo") {
+		return true
+	}
+	parts := strings.Split(key, "/")
+	return len(parts) > 1 && strings.HasPrefix(parts[len(parts)-2], "tfstate.")
+}
@@ -0,0 +1,84 @@
+package enumerator
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/cloudskiff/driftctl/pkg/iac/config"
+	consultest "github.com/cloudskiff/driftctl/test/consul"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConsulEnumerator_Enumerate(t *testing.T) {
+	server := consultest.NewFakeKVServer(map[string][]byte{
+		"states/infra":                  []byte("{}"),
+		"states/infra/.lock":            []byte("{}"),
+		"states/infra/.lockinfo":        []byte("{}"),
+		"states/infra-env:prod":         []byte("{}"),
+		"states/large":                  []byte(`{"current-hash":"abcdef","chunks":["states/large/tfstate.abcdef/0"]}`),
+		"states/large/tfstate.abcdef/0": []byte("{}"),
+		"states/nested/network":         []byte("{}"),
+		"other/state":                   []byte("{}"),
+	})
+	defer server.Close()
+	os.Setenv("CONSUL_HTTP_TOKEN", consultest.Token)
+	defer os.Unsetenv("CONSUL_HTTP_TOKEN")
+	address := strings.TrimPrefix(server.URL, "http://")
+
+	tests := []struct {
+		name string
+		path string
+		want []string
+		err  string
+	}{
+		{
+			name: "test results with glob",
+			path: address + "/states/*",
+			want: []string{
+				address + "/states/infra",
+				address + "/states/infra-env:prod",
+				address + "/states/large",
+			},
+		},
+		{
+			name: "test results with double star glob",
+			path: address + "/states/**",
+			want: []string{
+				address + "/states/infra",
+				address + "/states/infra-env:prod",
+				address + "/states/large",
+				address + "/states/nested/network",
+			},
+		},
+		{
+			name: "test results without glob",
+			path: address + "/states/infra",
+			want: []string{
+				address + "/states/infra",
+			},
+		},
+		{
+			name: "test no results",
+			path: address + "/unknown/*",
+			err:  "no Terraform state was found in " + address + "/unknown/*, exiting",
+		},
+		{
+			name: "test invalid path",
+			path: address,
+			err:  "Unable to parse consul path: " + address + ". Must be ADDRESS/PREFIX",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewConsulEnumerator(config.SupplierConfig{Path: tt.path})
+			got, err := s.Enumerate()
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package enumerator
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"cloud.google.com/go/storage"
+	"github.com/bmatcuk/doublestar/v4"
+	"github.com/pkg/errors"
+	"google.golang.org/api/iterator"
+	"google.golang.org/api/option"
+
+	"github.com/cloudskiff/driftctl/pkg/iac/config"
+	"github.com/cloudskiff/driftctl/pkg/iac/terraform/state/backend"
+)
+
+type GSEnumerator struct {
+	config        config.SupplierConfig
+	clientOptions []option.ClientOption
+}
+
+func NewGSEnumerator(config config.SupplierConfig, opts ...option.ClientOption) *GSEnumerator {
+	return &GSEnumerator{
+		config,
+		opts,
+	}
+}
+
+func (s *GSEnumerator) Origin() string {
+	return s.config.String()
+}
+
+func (s *GSEnumerator) Enumerate() ([]string, error) {
+	bucketPath := strings.Split(s.config.Path, "/")
+	if len(bucketPath) < 2 {
+		return nil, errors.Errorf("Unable to parse GS path: %s. Must be BUCKET_NAME/PREFIX", s.config.Path)
+	}
+
+	bucket := bucketPath[0]
+	// prefix should contains everything that does not have a glob pattern
+	// Pattern should be the glob matcher string
+	prefix, pattern := GlobS3(strings.Join(bucketPath[1:], "/"))
+
+	fullPattern := strings.Join([]string{prefix, pattern}, "/")
+	fullPattern = strings.Trim(fullPattern, "/")
+
+	ctx := context.Background()
+	client, err := backend.NewGSClient(ctx, s.clientOptions...)
+	if err != nil {
+		return nil, errors.Wrap(err, "Unable to create GS client")
+	}
+	defer client.Close()
+
+	files := make([]string, 0)
+	it := client.Bucket(bucket).Objects(ctx, &storage.Query{Prefix: prefix})
+	for {
+		attrs, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if attrs.Size > 0 {
+			if match, _ := doublestar.Match(fullPattern, attrs.Name); match {
+				files = append(files, strings.Join([]string{bucket, attrs.Name}, "/"))
+			}
+		}
+	}
+
+	if len(files) == 0 {
+		return files, fmt.Errorf("no Terraform state was found in %s, exiting", s.config.Path)
+	}
+
+	return files, nil
+}
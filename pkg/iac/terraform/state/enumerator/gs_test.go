@@ -0,0 +1,90 @@
+package enumerator
+
+import (
+	"testing"
+
+	"github.com/cloudskiff/driftctl/pkg/iac/config"
+	googletest "github.com/cloudskiff/driftctl/test/google"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGSEnumerator_Enumerate(t *testing.T) {
+	objects := map[string]map[string][]byte{
+		"bucket-name": {
+			"a/nested/prefix/state1":                    []byte("{}"),
+			"a/nested/prefix/state2":                    []byte("{}"),
+			"a/nested/prefix/state3.tfstate":            []byte("{}"),
+			"a/nested/prefix/empty.tfstate":             {},
+			"a/nested/prefix/folder/state4.tfstate":     []byte("{}"),
+			"a/nested/prefix/folder/sub/state5.tfstate": []byte("{}"),
+			"another/prefix/state6.tfstate":             []byte("{}"),
+		},
+	}
+
+	tests := []struct {
+		name   string
+		config config.SupplierConfig
+		want   []string
+		err    string
+	}{
+		{
+			name: "test results with glob",
+			config: config.SupplierConfig{
+				Path: "bucket-name/a/nested/prefix/*.tfstate",
+			},
+			want: []string{
+				"bucket-name/a/nested/prefix/state3.tfstate",
+			},
+		},
+		{
+			name: "test results with double star glob",
+			config: config.SupplierConfig{
+				Path: "bucket-name/a/nested/prefix/**/*.tfstate",
+			},
+			want: []string{
+				"bucket-name/a/nested/prefix/folder/state4.tfstate",
+				"bucket-name/a/nested/prefix/folder/sub/state5.tfstate",
+				"bucket-name/a/nested/prefix/state3.tfstate",
+			},
+		},
+		{
+			name: "test results without glob",
+			config: config.SupplierConfig{
+				Path: "bucket-name/a/nested/prefix",
+			},
+			want: []string{},
+			err:  "no Terraform state was found in bucket-name/a/nested/prefix, exiting",
+		},
+		{
+			name: "test invalid path",
+			config: config.SupplierConfig{
+				Path: "bucket-name",
+			},
+			err: "Unable to parse GS path: bucket-name. Must be BUCKET_NAME/PREFIX",
+		},
+		{
+			name: "test unknown bucket",
+			config: config.SupplierConfig{
+				Path: "unknown-bucket/*.tfstate",
+			},
+			err: "storage: bucket doesn't exist",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, opts := googletest.NewFakeStorageServer(objects)
+			defer server.Close()
+
+			s := NewGSEnumerator(tt.config, opts...)
+			got, err := s.Enumerate()
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+			} else {
+				assert.NoError(t, err)
+			}
+			if tt.want != nil {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
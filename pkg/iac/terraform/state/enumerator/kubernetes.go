@@ -0,0 +1,62 @@
+package enumerator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"github.com/pkg/errors"
+
+	"github.com/cloudskiff/driftctl/pkg/iac/config"
+	"github.com/cloudskiff/driftctl/pkg/iac/terraform/state/backend"
+)
+
+// KubernetesEnumerator lists secrets labelled as terraform states in a namespace
+type KubernetesEnumerator struct {
+	config config.SupplierConfig
+	client *backend.KubernetesClient
+}
+
+func NewKubernetesEnumerator(config config.SupplierConfig) *KubernetesEnumerator {
+	return &KubernetesEnumerator{
+		config: config,
+	}
+}
+
+func (s *KubernetesEnumerator) Origin() string {
+	return s.config.String()
+}
+
+func (s *KubernetesEnumerator) Enumerate() ([]string, error) {
+	namespaceSecret := strings.Split(s.config.Path, "/")
+	if len(namespaceSecret) != 2 || namespaceSecret[0] == "" || namespaceSecret[1] == "" {
+		return nil, errors.Errorf("Unable to parse kubernetes path: %s. Must be NAMESPACE/SECRET_NAME", s.config.Path)
+	}
+	namespace, pattern := namespaceSecret[0], namespaceSecret[1]
+
+	if s.client == nil {
+		client, err := backend.NewKubernetesClientFromConfig()
+		if err != nil {
+			return nil, errors.Wrap(err, "Unable to create kubernetes client")
+		}
+		s.client = client
+	}
+
+	secrets, err := s.client.ListStateSecrets(namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]string, 0)
+	for _, secret := range secrets {
+		if match, _ := doublestar.Match(pattern, secret); match {
+			files = append(files, strings.Join([]string{namespace, secret}, "/"))
+		}
+	}
+
+	if len(files) == 0 {
+		return files, fmt.Errorf("no Terraform state was found in %s, exiting", s.config.Path)
+	}
+
+	return files, nil
+}
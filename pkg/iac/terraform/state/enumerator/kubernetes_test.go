@@ -0,0 +1,87 @@
+package enumerator
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cloudskiff/driftctl/pkg/iac/config"
+	"github.com/cloudskiff/driftctl/pkg/iac/terraform/state/backend"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKubernetesEnumerator_Enumerate(t *testing.T) {
+	// Secrets are returned in two pages
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/namespaces/terraform/secrets" || r.URL.Query().Get("labelSelector") != "tfstate=true" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		page := map[string]interface{}{
+			"metadata": map[string]string{"continue": "next"},
+			"items": []interface{}{
+				map[string]interface{}{"metadata": map[string]string{"name": "tfstate-default-infra"}},
+				map[string]interface{}{"metadata": map[string]string{"name": "tfstate-default-network"}},
+			},
+		}
+		if r.URL.Query().Get("continue") == "next" {
+			page = map[string]interface{}{
+				"metadata": map[string]string{},
+				"items": []interface{}{
+					map[string]interface{}{"metadata": map[string]string{"name": "tfstate-prod-infra"}},
+				},
+			}
+		}
+		_ = json.NewEncoder(w).Encode(page)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name string
+		path string
+		want []string
+		err  string
+	}{
+		{
+			name: "test all workspaces of a suffix",
+			path: "terraform/tfstate-*-infra",
+			want: []string{
+				"terraform/tfstate-default-infra",
+				"terraform/tfstate-prod-infra",
+			},
+		},
+		{
+			name: "test all states",
+			path: "terraform/*",
+			want: []string{
+				"terraform/tfstate-default-infra",
+				"terraform/tfstate-default-network",
+				"terraform/tfstate-prod-infra",
+			},
+		},
+		{
+			name: "test no results",
+			path: "terraform/tfstate-dev-infra",
+			err:  "no Terraform state was found in terraform/tfstate-dev-infra, exiting",
+		},
+		{
+			name: "test invalid path",
+			path: "terraform",
+			err:  "Unable to parse kubernetes path: terraform. Must be NAMESPACE/SECRET_NAME",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewKubernetesEnumerator(config.SupplierConfig{Path: tt.path})
+			s.client = backend.NewKubernetesClient(server.Client(), server.URL, "")
+			got, err := s.Enumerate()
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
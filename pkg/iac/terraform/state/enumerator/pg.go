@@ -0,0 +1,77 @@
+package enumerator
+
+import (
+	"database/sql"
+	"fmt"
+	"net/url"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"github.com/pkg/errors"
+
+	"github.com/cloudskiff/driftctl/pkg/iac/config"
+	"github.com/cloudskiff/driftctl/pkg/iac/terraform/state/backend"
+)
+
+// PgEnumerator lists workspaces stored in a pg schema, workspace=* enumerates all of them
+type PgEnumerator struct {
+	config      config.SupplierConfig
+	credentials *url.Userinfo
+	// db is only set by tests, each enumeration opens its own connection otherwise
+	db *sql.DB
+}
+
+func NewPgEnumerator(config config.SupplierConfig, opts *backend.Options) *PgEnumerator {
+	e := &PgEnumerator{
+		config: config,
+	}
+	if opts != nil {
+		e.credentials = opts.PgCredentials
+	}
+	return e
+}
+
+func (s *PgEnumerator) Origin() string {
+	return s.config.String()
+}
+
+func (s *PgEnumerator) Enumerate() ([]string, error) {
+	pgConfig, err := backend.ParsePgPath(s.config.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	db := s.db
+	if db == nil {
+		db, err = backend.OpenPgDatabase(pgConfig, s.credentials)
+		if err != nil {
+			return nil, errors.Wrap(err, "Unable to connect to pg")
+		}
+		defer db.Close()
+	}
+
+	rows, err := db.Query(fmt.Sprintf("SELECT name FROM %s ORDER BY name", pgConfig.StatesTable()))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	files := make([]string, 0)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		if match, _ := doublestar.Match(pgConfig.Workspace, name); match {
+			files = append(files, backend.PgWorkspacePath(s.config.Path, name))
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(files) == 0 {
+		return files, fmt.Errorf("no Terraform state was found in %s, exiting", s.config.Path)
+	}
+
+	return files, nil
+}
@@ -0,0 +1,94 @@
+package enumerator
+
+import (
+	"testing"
+
+	"github.com/cloudskiff/driftctl/pkg/iac/config"
+	"github.com/cloudskiff/driftctl/test/pg"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPgEnumerator_Enumerate(t *testing.T) {
+	tests := []struct {
+		name   string
+		path   string
+		schema string
+		want   []string
+		err    string
+	}{
+		{
+			name:   "test all workspaces",
+			path:   "localhost/terraform?workspace=*",
+			schema: "terraform_remote_state",
+			want: []string{
+				"localhost/terraform?workspace=default",
+				"localhost/terraform?workspace=prod-eu",
+				"localhost/terraform?workspace=prod-us",
+				"localhost/terraform?workspace=staging",
+			},
+		},
+		{
+			name:   "test workspaces with glob",
+			path:   "localhost/terraform?schema_name=infra&workspace=prod-*",
+			schema: "infra",
+			want: []string{
+				"localhost/terraform?schema_name=infra&workspace=prod-eu",
+				"localhost/terraform?schema_name=infra&workspace=prod-us",
+			},
+		},
+		{
+			name:   "test default workspace",
+			path:   "localhost/terraform",
+			schema: "terraform_remote_state",
+			want: []string{
+				"localhost/terraform?workspace=default",
+			},
+		},
+		{
+			name:   "test no results",
+			path:   "localhost/terraform?workspace=dev",
+			schema: "terraform_remote_state",
+			err:    "no Terraform state was found in localhost/terraform?workspace=dev, exiting",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := pg.NewFakeStatesDB(pg.FakeStates{
+				Schema: tt.schema,
+				States: map[string][]byte{
+					"default": []byte("{}"),
+					"prod-eu": []byte("{}"),
+					"prod-us": []byte("{}"),
+					"staging": []byte("{}"),
+				},
+			})
+			defer db.Close()
+
+			s := NewPgEnumerator(config.SupplierConfig{Path: tt.path}, nil)
+			s.db = db
+			// Enumerating again must not fail on a connection closed by the first enumeration
+			for i := 0; i < 2; i++ {
+				got, err := s.Enumerate()
+				if tt.err != "" {
+					assert.EqualError(t, err, tt.err)
+				} else {
+					assert.NoError(t, err)
+					assert.Equal(t, tt.want, got)
+				}
+			}
+			assert.NoError(t, db.Ping())
+		})
+	}
+}
+
+func TestPgEnumerator_EnumerateTwice(t *testing.T) {
+	s := NewPgEnumerator(config.SupplierConfig{Path: "127.0.0.1:1/terraform?sslmode=disable"}, nil)
+
+	_, err := s.Enumerate()
+	assert.Error(t, err)
+	// The connection opened by the first enumeration is not reused once closed
+	_, secondErr := s.Enumerate()
+	assert.Error(t, secondErr)
+	assert.NotContains(t, secondErr.Error(), "database is closed")
+	assert.Equal(t, err.Error(), secondErr.Error())
+}
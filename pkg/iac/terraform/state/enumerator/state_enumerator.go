@@ -11,13 +11,23 @@ type StateEnumerator interface {
 	Enumerate() ([]string, error)
 }
 
-func GetEnumerator(config config.SupplierConfig) StateEnumerator {
+func GetEnumerator(config config.SupplierConfig, opts *backend.Options) StateEnumerator {
 
 	switch config.Backend {
 	case backend.BackendKeyFile:
 		return NewFileEnumerator(config)
 	case backend.BackendKeyS3:
 		return NewS3Enumerator(config)
+	case backend.BackendKeyGS:
+		return NewGSEnumerator(config)
+	case backend.BackendKeyAzureRM:
+		return NewAzureRMEnumerator(config)
+	case backend.BackendKeyConsul:
+		return NewConsulEnumerator(config)
+	case backend.BackendKeyPg:
+		return NewPgEnumerator(config, opts)
+	case backend.BackendKeyKubernetes:
+		return NewKubernetesEnumerator(config)
 	}
 
 	logrus.WithFields(logrus.Fields{
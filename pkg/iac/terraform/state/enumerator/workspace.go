@@ -0,0 +1,220 @@
+package enumerator
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/cloudskiff/driftctl/pkg/iac/config"
+	"github.com/cloudskiff/driftctl/pkg/iac/terraform/state/backend"
+)
+
+const (
+	defaultWorkspace          = "default"
+	defaultWorkspaceKeyPrefix = "env:"
+)
+
+// Workspace is the state location of a terraform workspace
+type Workspace struct {
+	Name string
+	Path string
+}
+
+// WorkspaceEnumerator expands the state location of the default workspace into the states of every workspace
+type WorkspaceEnumerator interface {
+	Origin() string
+	EnumerateWorkspaces() ([]Workspace, error)
+}
+
+// GetWorkspaceEnumerator follows the layout each terraform backend uses to store workspaces states,
+// the path given in config must be the one of the default workspace state
+func GetWorkspaceEnumerator(config config.SupplierConfig, opts *backend.Options) (WorkspaceEnumerator, error) {
+	switch config.Backend {
+	case backend.BackendKeyFile:
+		// terraform.tfstate.d/<workspace>/terraform.tfstate next to the default state
+		if info, err := os.Stat(config.Path); err == nil && info.IsDir() {
+			config.Path = filepath.Join(config.Path, "terraform.tfstate")
+		}
+		dir, file := filepath.Split(config.Path)
+		return newGlobWorkspaceEnumerator(config, opts, true, filepath.Join(dir, "terraform.tfstate.d", "*", file)), nil
+	case backend.BackendKeyS3:
+		// <bucket>/<workspace_key_prefix>/<workspace>/<key>
+		prefix := defaultWorkspaceKeyPrefix
+		if opts != nil && opts.WorkspaceKeyPrefix != "" {
+			prefix = opts.WorkspaceKeyPrefix
+		}
+		bucketKey := strings.SplitN(config.Path, "/", 2)
+		if len(bucketKey) < 2 {
+			return nil, errors.Errorf("Unable to parse S3 path: %s. Must be BUCKET_NAME/PATH/TO/OBJECT", config.Path)
+		}
+		return newGlobWorkspaceEnumerator(config, opts, true, strings.Join([]string{bucketKey[0], prefix, "*", bucketKey[1]}, "/")), nil
+	case backend.BackendKeyGS:
+		// <bucket>/<prefix>/<workspace>.tfstate
+		dir, file := path.Split(config.Path)
+		if file != defaultWorkspace+".tfstate" {
+			return nil, errors.Errorf("Unable to expand workspaces of %s, path must end with %s.tfstate", config.Path, defaultWorkspace)
+		}
+		return newGlobWorkspaceEnumerator(config, opts, false, dir+"*.tfstate"), nil
+	case backend.BackendKeyAzureRM, backend.BackendKeyConsul:
+		// <key>env:<workspace> for azurerm, <path>-env:<workspace> for consul
+		separator := "env:"
+		if config.Backend == backend.BackendKeyConsul {
+			separator = "-env:"
+		}
+		return newGlobWorkspaceEnumerator(config, opts, true, config.Path+separator+"*"), nil
+	case backend.BackendKeyKubernetes:
+		// <namespace>/tfstate-<workspace>-<secret_suffix>
+		namespace, secret := path.Split(config.Path)
+		prefix := fmt.Sprintf("tfstate-%s-", defaultWorkspace)
+		if !strings.HasPrefix(secret, prefix) {
+			return nil, errors.Errorf("Unable to expand workspaces of %s, secret name must start with %s", config.Path, prefix)
+		}
+		return newGlobWorkspaceEnumerator(config, opts, false, namespace+"tfstate-*-"+strings.TrimPrefix(secret, prefix)), nil
+	case backend.BackendKeyPg:
+		pgConfig, err := backend.ParsePgPath(config.Path)
+		if err != nil {
+			return nil, err
+		}
+		e := newGlobWorkspaceEnumerator(config, opts, false, backend.PgWorkspacePath(config.Path, "*"))
+		e.workspaceName = func(key string) (string, bool) {
+			keyConfig, err := backend.ParsePgPath(key)
+			if err != nil || keyConfig.Schema != pgConfig.Schema {
+				return "", false
+			}
+			return keyConfig.Workspace, true
+		}
+		return e, nil
+	case backend.BackendKeyTFCloud:
+		return &TFCloudWorkspaceEnumerator{config: config, opts: opts}, nil
+	}
+
+	return nil, errors.Errorf("workspaces expansion is not supported by the %s backend", config.Backend)
+}
+
+// globWorkspaceEnumerator lists states matching a pattern where the only wildcard stands for the workspace name
+type globWorkspaceEnumerator struct {
+	config config.SupplierConfig
+	opts   *backend.Options
+	// withDefault is true when the default workspace state is stored out of the pattern, at the configured path
+	withDefault   bool
+	pattern       string
+	workspaceName func(key string) (string, bool)
+}
+
+func newGlobWorkspaceEnumerator(config config.SupplierConfig, opts *backend.Options, withDefault bool, pattern string) *globWorkspaceEnumerator {
+	e := &globWorkspaceEnumerator{
+		config:      config,
+		opts:        opts,
+		withDefault: withDefault,
+		pattern:     pattern,
+	}
+	e.workspaceName = e.wildcardValue
+	return e
+}
+
+func (e *globWorkspaceEnumerator) Origin() string {
+	return e.config.String()
+}
+
+func (e *globWorkspaceEnumerator) EnumerateWorkspaces() ([]Workspace, error) {
+	workspaces := make([]Workspace, 0)
+
+	if e.withDefault {
+		keys, err := e.enumerate(e.config.Path)
+		if err != nil {
+			logrus.WithFields(logrus.Fields{
+				"path": e.config.Path,
+				"err":  err,
+			}).Debug("No state found for the default workspace")
+		}
+		for _, key := range keys {
+			workspaces = append(workspaces, Workspace{Name: defaultWorkspace, Path: key})
+		}
+	}
+
+	keys, err := e.enumerate(e.pattern)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"pattern": e.pattern,
+			"err":     err,
+		}).Debug("No state found for non default workspaces")
+	}
+	for _, key := range keys {
+		name, ok := e.workspaceName(key)
+		if !ok {
+			continue
+		}
+		workspaces = append(workspaces, Workspace{Name: name, Path: key})
+	}
+
+	if len(workspaces) == 0 {
+		return nil, fmt.Errorf("no Terraform workspace was found in %s, exiting", e.config.Path)
+	}
+	return workspaces, nil
+}
+
+func (e *globWorkspaceEnumerator) enumerate(path string) ([]string, error) {
+	c := e.config
+	c.Path = path
+	enumerator := GetEnumerator(c, e.opts)
+	if enumerator == nil {
+		return nil, errors.Errorf("no enumerator for backend %s", c.Backend)
+	}
+	return enumerator.Enumerate()
+}
+
+// wildcardValue returns the part of the key matched by the wildcard of the pattern
+func (e *globWorkspaceEnumerator) wildcardValue(key string) (string, bool) {
+	pattern, key := e.pattern, key
+	if e.config.Backend == backend.BackendKeyFile {
+		pattern, key = filepath.Clean(pattern), filepath.Clean(key)
+	}
+	i := strings.Index(pattern, "*")
+	prefix, suffix := pattern[:i], pattern[i+1:]
+	if len(key) <= len(prefix)+len(suffix) || !strings.HasPrefix(key, prefix) || !strings.HasSuffix(key, suffix) {
+		return "", false
+	}
+	return key[len(prefix) : len(key)-len(suffix)], true
+}
+
+// TFCloudWorkspaceEnumerator lists the workspaces of the organization given as path,
+// only those starting with TFCloudWorkspacePrefix when set in options
+type TFCloudWorkspaceEnumerator struct {
+	config config.SupplierConfig
+	opts   *backend.Options
+}
+
+func (e *TFCloudWorkspaceEnumerator) Origin() string {
+	return e.config.String()
+}
+
+func (e *TFCloudWorkspaceEnumerator) EnumerateWorkspaces() ([]Workspace, error) {
+	tfcWorkspaces, err := backend.ListTFCloudWorkspaces(&http.Client{}, e.config.Path, e.opts)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := ""
+	if e.opts != nil {
+		prefix = e.opts.TFCloudWorkspacePrefix
+	}
+
+	workspaces := make([]Workspace, 0, len(tfcWorkspaces))
+	for _, workspace := range tfcWorkspaces {
+		// Like terraform, workspaces of a prefix are named without it
+		if !strings.HasPrefix(workspace.Name, prefix) || workspace.Name == prefix {
+			continue
+		}
+		workspaces = append(workspaces, Workspace{Name: strings.TrimPrefix(workspace.Name, prefix), Path: workspace.Id})
+	}
+	if len(workspaces) == 0 {
+		return nil, fmt.Errorf("no Terraform workspace was found in %s, exiting", e.config.Path)
+	}
+	return workspaces, nil
+}
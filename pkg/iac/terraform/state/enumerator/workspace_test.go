@@ -0,0 +1,234 @@
+package enumerator
+
+import (
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/cloudskiff/driftctl/pkg/iac/config"
+	"github.com/cloudskiff/driftctl/pkg/iac/terraform/state/backend"
+	consultest "github.com/cloudskiff/driftctl/test/consul"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetWorkspaceEnumerator(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  config.SupplierConfig
+		opts    *backend.Options
+		pattern string
+		err     string
+	}{
+		{
+			name:    "file",
+			config:  config.SupplierConfig{Backend: backend.BackendKeyFile, Path: "infra/terraform.tfstate"},
+			pattern: "infra/terraform.tfstate.d/*/terraform.tfstate",
+		},
+		{
+			name:    "file directory",
+			config:  config.SupplierConfig{Backend: backend.BackendKeyFile, Path: "testdata/workspaces"},
+			pattern: "testdata/workspaces/terraform.tfstate.d/*/terraform.tfstate",
+		},
+		{
+			name:    "s3",
+			config:  config.SupplierConfig{Backend: backend.BackendKeyS3, Path: "bucket/infra/terraform.tfstate"},
+			pattern: "bucket/env:/*/infra/terraform.tfstate",
+		},
+		{
+			name:    "s3 with workspace key prefix",
+			config:  config.SupplierConfig{Backend: backend.BackendKeyS3, Path: "bucket/infra/terraform.tfstate"},
+			opts:    &backend.Options{WorkspaceKeyPrefix: "workspaces"},
+			pattern: "bucket/workspaces/*/infra/terraform.tfstate",
+		},
+		{
+			name:   "s3 invalid path",
+			config: config.SupplierConfig{Backend: backend.BackendKeyS3, Path: "bucket"},
+			err:    "Unable to parse S3 path: bucket. Must be BUCKET_NAME/PATH/TO/OBJECT",
+		},
+		{
+			name:    "gs",
+			config:  config.SupplierConfig{Backend: backend.BackendKeyGS, Path: "bucket/infra/default.tfstate"},
+			pattern: "bucket/infra/*.tfstate",
+		},
+		{
+			name:   "gs not default workspace",
+			config: config.SupplierConfig{Backend: backend.BackendKeyGS, Path: "bucket/infra/prod.tfstate"},
+			err:    "Unable to expand workspaces of bucket/infra/prod.tfstate, path must end with default.tfstate",
+		},
+		{
+			name:    "azurerm",
+			config:  config.SupplierConfig{Backend: backend.BackendKeyAzureRM, Path: "account/container/terraform.tfstate"},
+			pattern: "account/container/terraform.tfstateenv:*",
+		},
+		{
+			name:    "consul",
+			config:  config.SupplierConfig{Backend: backend.BackendKeyConsul, Path: "localhost:8500/states/infra"},
+			pattern: "localhost:8500/states/infra-env:*",
+		},
+		{
+			name:    "kubernetes",
+			config:  config.SupplierConfig{Backend: backend.BackendKeyKubernetes, Path: "default/tfstate-default-infra"},
+			pattern: "default/tfstate-*-infra",
+		},
+		{
+			name:   "kubernetes not default workspace",
+			config: config.SupplierConfig{Backend: backend.BackendKeyKubernetes, Path: "default/tfstate-prod-infra"},
+			err:    "Unable to expand workspaces of default/tfstate-prod-infra, secret name must start with tfstate-default-",
+		},
+		{
+			name:   "unsupported backend",
+			config: config.SupplierConfig{Backend: backend.BackendKeyHTTPS, Path: "example.com/terraform.tfstate"},
+			err:    "workspaces expansion is not supported by the https backend",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetWorkspaceEnumerator(tt.config, tt.opts)
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.pattern, got.(*globWorkspaceEnumerator).pattern)
+		})
+	}
+}
+
+func TestGlobWorkspaceEnumerator_File(t *testing.T) {
+	e, err := GetWorkspaceEnumerator(config.SupplierConfig{
+		Key:     "tfstate",
+		Backend: backend.BackendKeyFile,
+		Path:    "testdata/workspaces",
+	}, nil)
+	assert.NoError(t, err)
+
+	got, err := e.EnumerateWorkspaces()
+	assert.NoError(t, err)
+	assert.Equal(t, []Workspace{
+		{Name: "default", Path: "testdata/workspaces/terraform.tfstate"},
+		{Name: "prod", Path: "testdata/workspaces/terraform.tfstate.d/prod/terraform.tfstate"},
+		{Name: "staging", Path: "testdata/workspaces/terraform.tfstate.d/staging/terraform.tfstate"},
+	}, got)
+}
+
+func TestGlobWorkspaceEnumerator_NoWorkspace(t *testing.T) {
+	e, err := GetWorkspaceEnumerator(config.SupplierConfig{
+		Key:     "tfstate",
+		Backend: backend.BackendKeyFile,
+		Path:    "testdata/no_state_here",
+	}, nil)
+	assert.NoError(t, err)
+
+	_, err = e.EnumerateWorkspaces()
+	assert.EqualError(t, err, "no Terraform workspace was found in testdata/no_state_here/terraform.tfstate, exiting")
+}
+
+func TestGlobWorkspaceEnumerator_Consul(t *testing.T) {
+	server := consultest.NewFakeKVServer(map[string][]byte{
+		"states/infra":          []byte("{}"),
+		"states/infra-env:prod": []byte("{}"),
+		"states/infra-env:dev":  []byte("{}"),
+		"states/network":        []byte("{}"),
+	})
+	defer server.Close()
+	os.Setenv("CONSUL_HTTP_TOKEN", consultest.Token)
+	defer os.Unsetenv("CONSUL_HTTP_TOKEN")
+	address := strings.TrimPrefix(server.URL, "http://")
+
+	e, err := GetWorkspaceEnumerator(config.SupplierConfig{
+		Key:     "tfstate",
+		Backend: backend.BackendKeyConsul,
+		Path:    address + "/states/infra",
+	}, nil)
+	assert.NoError(t, err)
+
+	got, err := e.EnumerateWorkspaces()
+	assert.NoError(t, err)
+	assert.Equal(t, []Workspace{
+		{Name: "default", Path: address + "/states/infra"},
+		{Name: "dev", Path: address + "/states/infra-env:dev"},
+		{Name: "prod", Path: address + "/states/infra-env:prod"},
+	}, got)
+}
+
+func TestGlobWorkspaceEnumerator_WildcardValue(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		key     string
+		want    string
+		ok      bool
+	}{
+		{name: "match", pattern: "bucket/env:/*/terraform.tfstate", key: "bucket/env:/prod/terraform.tfstate", want: "prod", ok: true},
+		{name: "empty workspace", pattern: "bucket/env:/*/terraform.tfstate", key: "bucket/env://terraform.tfstate", ok: false},
+		{name: "other prefix", pattern: "bucket/env:/*/terraform.tfstate", key: "bucket/other/prod/terraform.tfstate", ok: false},
+		{name: "other suffix", pattern: "default/tfstate-*-infra", key: "default/tfstate-prod-network", ok: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newGlobWorkspaceEnumerator(config.SupplierConfig{Backend: backend.BackendKeyS3}, nil, true, tt.pattern)
+			got, ok := e.wildcardValue(tt.key)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestTFCloudWorkspaceEnumerator_EnumerateWorkspaces(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://app.terraform.io/api/v2/organizations/org/workspaces",
+		httpmock.NewStringResponder(http.StatusOK, `{"data":[{"id":"ws-1","attributes":{"name":"infra"}},{"id":"ws-2","attributes":{"name":"network"}}],"meta":{"pagination":{"next-page":null}}}`),
+	)
+
+	e, err := GetWorkspaceEnumerator(config.SupplierConfig{
+		Key:     "tfstate",
+		Backend: backend.BackendKeyTFCloud,
+		Path:    "org",
+	}, &backend.Options{
+		TFCloudToken:    "TOKEN",
+		TFCloudEndpoint: "https://app.terraform.io/api/v2",
+	})
+	assert.NoError(t, err)
+
+	got, err := e.EnumerateWorkspaces()
+	assert.NoError(t, err)
+	assert.Equal(t, []Workspace{
+		{Name: "infra", Path: "ws-1"},
+		{Name: "network", Path: "ws-2"},
+	}, got)
+}
+
+func TestTFCloudWorkspaceEnumerator_EnumerateWorkspaces_WithPrefix(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://app.terraform.io/api/v2/organizations/org/workspaces",
+		httpmock.NewStringResponder(http.StatusOK, `{"data":[{"id":"ws-1","attributes":{"name":"infra-prod"}},{"id":"ws-2","attributes":{"name":"network-prod"}},{"id":"ws-3","attributes":{"name":"infra-staging"}}],"meta":{"pagination":{"next-page":null}}}`),
+	)
+
+	e, err := GetWorkspaceEnumerator(config.SupplierConfig{
+		Key:     "tfstate",
+		Backend: backend.BackendKeyTFCloud,
+		Path:    "org",
+	}, &backend.Options{
+		TFCloudToken:           "TOKEN",
+		TFCloudEndpoint:        "https://app.terraform.io/api/v2",
+		TFCloudWorkspacePrefix: "infra-",
+	})
+	assert.NoError(t, err)
+
+	got, err := e.EnumerateWorkspaces()
+	assert.NoError(t, err)
+	assert.Equal(t, []Workspace{
+		{Name: "prod", Path: "ws-1"},
+		{Name: "staging", Path: "ws-3"},
+	}, got)
+}
@@ -2,6 +2,7 @@ package state
 
 import (
 	"fmt"
+	"io"
 	"strings"
 
 	"github.com/cloudskiff/driftctl/pkg/alerter"
@@ -19,6 +20,7 @@ import (
 
 	"github.com/cloudskiff/driftctl/pkg/iac/config"
 	"github.com/cloudskiff/driftctl/pkg/iac/terraform/state/backend"
+	"github.com/cloudskiff/driftctl/pkg/iac/terraform/state/decryption"
 	"github.com/cloudskiff/driftctl/pkg/iac/terraform/state/enumerator"
 	"github.com/cloudskiff/driftctl/pkg/resource"
 	"github.com/cloudskiff/driftctl/pkg/terraform"
@@ -36,6 +38,8 @@ type TerraformStateReader struct {
 	config         config.SupplierConfig
 	backend        backend.Backend
 	enumerator     enumerator.StateEnumerator
+	workspaces     enumerator.WorkspaceEnumerator
+	workspace      string
 	deserializer   *resource.Deserializer
 	backendOptions *backend.Options
 	progress       output.Progress
@@ -44,7 +48,15 @@ type TerraformStateReader struct {
 }
 
 func (r *TerraformStateReader) initReader() error {
-	r.enumerator = enumerator.GetEnumerator(r.config)
+	if r.backendOptions != nil && r.backendOptions.AllWorkspaces {
+		workspaces, err := enumerator.GetWorkspaceEnumerator(r.config, r.backendOptions)
+		if err != nil {
+			return err
+		}
+		r.workspaces = workspaces
+		return nil
+	}
+	r.enumerator = enumerator.GetEnumerator(r.config, r.backendOptions)
 	return nil
 }
 
@@ -64,7 +76,11 @@ func (r *TerraformStateReader) retrieve() (map[string][]decodedRes, error) {
 	}
 	r.backend = b
 
-	state, err := read(r.config.Path, r.backend)
+	var decryptionOpts *decryption.Options
+	if r.backendOptions != nil {
+		decryptionOpts = &r.backendOptions.StateDecryption
+	}
+	state, err := read(r.config.Path, r.backend, decryptionOpts)
 	defer r.backend.Close()
 	if err != nil {
 		return nil, err
@@ -113,7 +129,7 @@ func (r *TerraformStateReader) retrieve() (map[string][]decodedRes, error) {
 				continue
 			}
 			schema := provider.Schema()[stateRes.Addr.Resource.Type]
-			for _, instance := range stateRes.Instances {
+			for key, instance := range stateRes.Instances {
 				decodedVal, err := instance.Current.Decode(schema.Block.ImpliedType())
 				if err != nil {
 					// Try to do a manual type conversion if we got a path error
@@ -140,8 +156,12 @@ func (r *TerraformStateReader) retrieve() (map[string][]decodedRes, error) {
 					}
 				}
 				_, exists := resMap[stateRes.Addr.Resource.Type]
+				source := resource.NewTerraformStateWorkspaceSource(r.config.String(), r.workspace, moduleName, resName)
+				if key != addrs.NoKey {
+					source.InstanceKey = key.String()
+				}
 				val := decodedRes{
-					source: resource.NewTerraformStateSource(r.config.String(), moduleName, resName),
+					source: source,
 					val:    decodedVal.Value,
 				}
 				if !exists {
@@ -207,6 +227,10 @@ func (r *TerraformStateReader) decode(valFromState map[string][]decodedRes) ([]*
 }
 
 func (r *TerraformStateReader) Resources() ([]*resource.Resource, error) {
+	if r.workspaces != nil {
+		return r.retrieveWorkspaces()
+	}
+
 	if r.enumerator == nil {
 		return r.retrieveForState(r.config.Path)
 	}
@@ -217,8 +241,9 @@ func (r *TerraformStateReader) Resources() ([]*resource.Resource, error) {
 func (r *TerraformStateReader) retrieveForState(path string) ([]*resource.Resource, error) {
 	r.config.Path = path
 	logrus.WithFields(logrus.Fields{
-		"path":    r.config.Path,
-		"backend": r.config.Backend,
+		"path":      r.config.Path,
+		"backend":   r.config.Backend,
+		"workspace": r.workspace,
 	}).Debug("Reading resources from state")
 	r.progress.Inc()
 	values, err := r.retrieve()
@@ -240,15 +265,38 @@ func (r *TerraformStateReader) retrieveMultiplesStates() ([]*resource.Resource,
 		"keys": keys,
 	}).Debug("Enumerated keys")
 
+	workspaces := make([]enumerator.Workspace, 0, len(keys))
+	for _, key := range keys {
+		workspaces = append(workspaces, enumerator.Workspace{Path: key})
+	}
+	return r.retrieveStates(workspaces)
+}
+
+func (r *TerraformStateReader) retrieveWorkspaces() ([]*resource.Resource, error) {
+	workspaces, err := r.workspaces.EnumerateWorkspaces()
+	if err != nil {
+		r.alerter.SendAlert("", NewStateReadingAlert(r.workspaces.Origin(), err))
+		return nil, errors.Wrap(err, r.config.String())
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"workspaces": workspaces,
+	}).Debug("Enumerated workspaces")
+
+	return r.retrieveStates(workspaces)
+}
+
+func (r *TerraformStateReader) retrieveStates(workspaces []enumerator.Workspace) ([]*resource.Resource, error) {
 	results := make([]*resource.Resource, 0)
 	isSuccess := false
 	readingError := iac.NewStateReadingError()
 
-	for _, key := range keys {
-		resources, err := r.retrieveForState(key)
+	for _, workspace := range workspaces {
+		r.workspace = workspace.Name
+		resources, err := r.retrieveForState(workspace.Path)
 		if err != nil {
 			readingError.Add(err)
-			r.alerter.SendAlert("", NewStateReadingAlert(key, err))
+			r.alerter.SendAlert("", NewStateReadingAlert(workspace.Path, err))
 			continue
 		}
 		isSuccess = true
@@ -263,8 +311,13 @@ func (r *TerraformStateReader) retrieveMultiplesStates() ([]*resource.Resource,
 	return results, nil
 }
 
-func read(path string, reader backend.Backend) (*states.State, error) {
-	state, err := readState(path, reader)
+func read(path string, reader backend.Backend, decryptionOpts *decryption.Options) (*states.State, error) {
+	// Encrypted states are decrypted between the backend and the state parser
+	decrypted, err := decryption.NewReader(reader, decryptionOpts)
+	if err != nil {
+		return nil, err
+	}
+	state, err := readState(path, decrypted)
 	if err != nil {
 		if _, ok := reader.(*backend.HTTPBackend); ok && strings.Contains(err.Error(), "The state file could not be parsed as JSON") {
 			return nil, errors.Errorf("given url is not a valid state file")
@@ -274,7 +327,7 @@ func read(path string, reader backend.Backend) (*states.State, error) {
 	return state, nil
 }
 
-func readState(path string, reader backend.Backend) (*states.State, error) {
+func readState(path string, reader io.Reader) (*states.State, error) {
 	state, err := statefile.Read(reader)
 	if err != nil {
 		return nil, err
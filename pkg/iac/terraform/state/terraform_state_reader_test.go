@@ -21,6 +21,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/cloudskiff/driftctl/pkg/iac/config"
+	"github.com/cloudskiff/driftctl/pkg/iac/terraform/state/decryption"
 	"github.com/cloudskiff/driftctl/pkg/remote/aws"
 	"github.com/cloudskiff/driftctl/pkg/remote/github"
 	"github.com/cloudskiff/driftctl/pkg/resource"
@@ -48,6 +49,16 @@ func TestReadStateInvalid(t *testing.T) {
 	}
 }
 
+func TestReadEncryptedState(t *testing.T) {
+	reader, _ := os.Open("decryption/testdata/tofu.tfstate")
+	defer reader.Close()
+	state, err := read("terraform.tfstate", reader, &decryption.Options{TofuPassphrase: "correct-horse-battery-staple"})
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Len(t, state.RootModule().Resources, 1)
+}
+
 // Check that resource sources are properly set
 func TestTerraformStateReader_Source(t *testing.T) {
 	progress := &output.MockProgress{}
@@ -190,7 +201,7 @@ func TestTerraformStateReader_AWS_Resources(t *testing.T) {
 
 			if shouldUpdate {
 				var err error
-				realProvider, err = aws.NewAWSTerraformProvider("3.19.0", progress, os.TempDir())
+				realProvider, err = aws.NewAWSTerraformProvider("3.19.0", progress, os.TempDir(), nil)
 				if err != nil {
 					t.Fatal(err)
 				}
@@ -275,7 +286,7 @@ func TestTerraformStateReader_Github_Resources(t *testing.T) {
 
 			if shouldUpdate {
 				var err error
-				realProvider, err = github.NewGithubTerraformProvider("", progress, os.TempDir())
+				realProvider, err = github.NewGithubTerraformProvider("", progress, os.TempDir(), nil)
 				if err != nil {
 					t.Fatal(err)
 				}
@@ -373,7 +384,7 @@ func TestTerraformStateReader_Google_Resources(t *testing.T) {
 			var realProvider *google.GCPTerraformProvider
 			providerVersion := "3.78.0"
 			var err error
-			realProvider, err = google.NewGCPTerraformProvider(providerVersion, progress, os.TempDir())
+			realProvider, err = google.NewGCPTerraformProvider(providerVersion, progress, os.TempDir(), nil)
 			if err != nil {
 				t.Fatal(err)
 			}
@@ -467,7 +478,7 @@ func TestTerraformStateReader_Azure_Resources(t *testing.T) {
 			var realProvider *azurerm.AzureTerraformProvider
 			providerVersion := "2.71.0"
 			var err error
-			realProvider, err = azurerm.NewAzureTerraformProvider(providerVersion, progress, os.TempDir())
+			realProvider, err = azurerm.NewAzureTerraformProvider(providerVersion, progress, os.TempDir(), nil)
 			if err != nil {
 				t.Fatal(err)
 			}
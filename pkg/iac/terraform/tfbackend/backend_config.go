@@ -0,0 +1,204 @@
+package tfbackend
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/terraform/configs"
+	"github.com/pkg/errors"
+	"github.com/zclconf/go-cty/cty"
+	ctyconvert "github.com/zclconf/go-cty/cty/convert"
+)
+
+const (
+	defaultDataDir   = ".terraform"
+	defaultWorkspace = "default"
+)
+
+// Only the attributes locating the state are read, credentials are left to the environment
+var backendSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{Name: "path"},
+		{Name: "workspace_dir"},
+		{Name: "bucket"},
+		{Name: "key"},
+		{Name: "workspace_key_prefix"},
+		{Name: "prefix"},
+		{Name: "storage_account_name"},
+		{Name: "container_name"},
+		{Name: "address"},
+		{Name: "conn_str"},
+		{Name: "schema_name"},
+		{Name: "secret_suffix"},
+		{Name: "namespace"},
+		{Name: "hostname"},
+		{Name: "organization"},
+	},
+	Blocks: []hcl.BlockHeaderSchema{
+		{Type: "workspaces"},
+	},
+}
+
+var workspacesSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{Name: "name"},
+		{Name: "prefix"},
+	},
+}
+
+// BackendConfig is the backend configuration of a terraform working directory
+type BackendConfig struct {
+	Type       string
+	Attributes map[string]string
+	// Workspaces holds the nested workspaces block of remote backends
+	Workspaces map[string]string
+}
+
+// initializedBackend is the part of .terraform/terraform.tfstate written by "terraform init"
+type initializedBackend struct {
+	Backend *struct {
+		Type   string                 `json:"type"`
+		Config map[string]interface{} `json:"config"`
+	} `json:"backend"`
+}
+
+// ReadBackendConfig prefers the backend initialized in the data directory since it includes
+// values given with -backend-config, the backend block of the configuration is read otherwise.
+// Directories without backend use the local one like terraform does.
+func ReadBackendConfig(dir string) (*BackendConfig, error) {
+	config, err := readInitializedBackend(filepath.Join(dataDir(dir), "terraform.tfstate"))
+	if err != nil {
+		return nil, err
+	}
+	if config != nil {
+		return config, nil
+	}
+	return readConfiguredBackend(dir)
+}
+
+// CurrentWorkspace returns the workspace selected in a terraform working directory
+func CurrentWorkspace(dir string) string {
+	if workspace := os.Getenv("TF_WORKSPACE"); workspace != "" {
+		return workspace
+	}
+	content, err := ioutil.ReadFile(filepath.Join(dataDir(dir), "environment"))
+	if err != nil {
+		return defaultWorkspace
+	}
+	if workspace := strings.TrimSpace(string(content)); workspace != "" {
+		return workspace
+	}
+	return defaultWorkspace
+}
+
+func dataDir(dir string) string {
+	if env := os.Getenv("TF_DATA_DIR"); env != "" {
+		if filepath.IsAbs(env) {
+			return env
+		}
+		return filepath.Join(dir, env)
+	}
+	return filepath.Join(dir, defaultDataDir)
+}
+
+func readInitializedBackend(path string) (*BackendConfig, error) {
+	content, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	initialized := initializedBackend{}
+	if err := json.Unmarshal(content, &initialized); err != nil {
+		return nil, errors.Wrapf(err, "unable to parse %s", path)
+	}
+	if initialized.Backend == nil || initialized.Backend.Type == "" {
+		return nil, nil
+	}
+
+	config := &BackendConfig{
+		Type:       initialized.Backend.Type,
+		Attributes: stringValues(initialized.Backend.Config),
+	}
+	switch workspaces := initialized.Backend.Config["workspaces"].(type) {
+	case map[string]interface{}:
+		config.Workspaces = stringValues(workspaces)
+	case []interface{}:
+		if len(workspaces) > 0 {
+			if block, ok := workspaces[0].(map[string]interface{}); ok {
+				config.Workspaces = stringValues(block)
+			}
+		}
+	}
+	return config, nil
+}
+
+func stringValues(values map[string]interface{}) map[string]string {
+	result := make(map[string]string)
+	for key, value := range values {
+		if str, ok := value.(string); ok && str != "" {
+			result[key] = str
+		}
+	}
+	return result
+}
+
+func readConfiguredBackend(dir string) (*BackendConfig, error) {
+	module, diags := configs.NewParser(nil).LoadConfigDir(dir)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	if module.Backend == nil {
+		return &BackendConfig{Type: "local", Attributes: map[string]string{}}, nil
+	}
+
+	content, _, diags := module.Backend.Config.PartialContent(backendSchema)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	attributes, err := decodeAttributes(content.Attributes)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &BackendConfig{
+		Type:       module.Backend.Type,
+		Attributes: attributes,
+	}
+	for _, block := range content.Blocks {
+		workspaces, diags := block.Body.Content(workspacesSchema)
+		if diags.HasErrors() {
+			return nil, diags
+		}
+		config.Workspaces, err = decodeAttributes(workspaces.Attributes)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return config, nil
+}
+
+func decodeAttributes(attributes hcl.Attributes) (map[string]string, error) {
+	result := make(map[string]string)
+	for name, attr := range attributes {
+		value, diags := attr.Expr.Value(nil)
+		if diags.HasErrors() {
+			return nil, diags
+		}
+		value, err := ctyconvert.Convert(value, cty.String)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid value for backend attribute '%s'", name)
+		}
+		if value.IsNull() || value.AsString() == "" {
+			continue
+		}
+		result[name] = value.AsString()
+	}
+	return result, nil
+}
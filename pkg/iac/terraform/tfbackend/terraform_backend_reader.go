@@ -0,0 +1,226 @@
+package tfbackend
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/cloudskiff/driftctl/pkg/alerter"
+	"github.com/cloudskiff/driftctl/pkg/filter"
+	"github.com/cloudskiff/driftctl/pkg/iac/config"
+	"github.com/cloudskiff/driftctl/pkg/iac/terraform/state"
+	"github.com/cloudskiff/driftctl/pkg/iac/terraform/state/backend"
+	"github.com/cloudskiff/driftctl/pkg/output"
+	"github.com/cloudskiff/driftctl/pkg/resource"
+	"github.com/cloudskiff/driftctl/pkg/terraform"
+)
+
+const TerraformBackendReaderSupplier = "tfbackend"
+
+// NewReader reads the state of the current workspace of a terraform working directory,
+// the state location is resolved from the backend configured in the directory.
+func NewReader(cfg config.SupplierConfig, library *terraform.ProviderLibrary, backendOpts *backend.Options, progress output.Progress, alerter *alerter.Alerter, deserializer *resource.Deserializer, filter filter.Filter) (*state.TerraformStateReader, error) {
+	if cfg.Backend != "" {
+		return nil, errors.Errorf("backends are not supported by the %s IaC source", TerraformBackendReaderSupplier)
+	}
+
+	backendConfig, err := ReadBackendConfig(cfg.Path)
+	if err != nil {
+		return nil, errors.Wrap(err, cfg.String())
+	}
+
+	// Options are shared by every IaC source, the backend configuration must only apply to this one
+	opts := backend.Options{}
+	if backendOpts != nil {
+		opts = *backendOpts
+	}
+	workspace := CurrentWorkspace(cfg.Path)
+	if opts.AllWorkspaces {
+		workspace = defaultWorkspace
+	}
+
+	stateConfig, err := resolveStateConfig(backendConfig, cfg.Path, workspace, &opts)
+	if err != nil {
+		return nil, errors.Wrap(err, cfg.String())
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"path":      cfg.Path,
+		"backend":   backendConfig.Type,
+		"workspace": workspace,
+		"state":     stateConfig.String(),
+	}).Debug("Resolved terraform backend")
+
+	return state.NewReader(stateConfig, library, &opts, progress, alerter, deserializer, filter)
+}
+
+// resolveStateConfig translates a terraform backend configuration into the location of the workspace state
+func resolveStateConfig(c *BackendConfig, dir, workspace string, opts *backend.Options) (config.SupplierConfig, error) {
+	stateConfig := config.SupplierConfig{Key: state.TerraformStateReaderSupplier}
+	isDefault := workspace == defaultWorkspace
+
+	switch c.Type {
+	case "local":
+		stateConfig.Backend = backend.BackendKeyFile
+		if isDefault {
+			stateConfig.Path = c.attr("path", "terraform.tfstate")
+		} else {
+			stateConfig.Path = filepath.Join(c.attr("workspace_dir", "terraform.tfstate.d"), workspace, "terraform.tfstate")
+		}
+		if !filepath.IsAbs(stateConfig.Path) {
+			stateConfig.Path = filepath.Join(dir, stateConfig.Path)
+		}
+	case "s3":
+		values, err := c.requiredAttrs("bucket", "key")
+		if err != nil {
+			return stateConfig, err
+		}
+		bucket, key := values[0], values[1]
+		prefix := c.attr("workspace_key_prefix", "env:")
+		if opts.WorkspaceKeyPrefix == "" {
+			opts.WorkspaceKeyPrefix = prefix
+		}
+		stateConfig.Backend = backend.BackendKeyS3
+		stateConfig.Path = path.Join(bucket, key)
+		if !isDefault {
+			stateConfig.Path = path.Join(bucket, prefix, workspace, key)
+		}
+	case "gcs":
+		bucket, err := c.requiredAttr("bucket")
+		if err != nil {
+			return stateConfig, err
+		}
+		stateConfig.Backend = backend.BackendKeyGS
+		stateConfig.Path = path.Join(bucket, c.attr("prefix", ""), workspace+".tfstate")
+	case "azurerm":
+		values, err := c.requiredAttrs("storage_account_name", "container_name", "key")
+		if err != nil {
+			return stateConfig, err
+		}
+		stateConfig.Backend = backend.BackendKeyAzureRM
+		stateConfig.Path = path.Join(values...)
+		if !isDefault {
+			stateConfig.Path += "env:" + workspace
+		}
+	case "http":
+		address, err := c.requiredAttr("address")
+		if err != nil {
+			return stateConfig, err
+		}
+		if !isDefault {
+			return stateConfig, errors.Errorf("workspaces are not supported by the %s backend", c.Type)
+		}
+		u, err := url.Parse(address)
+		if err != nil || (u.Scheme != backend.BackendKeyHTTP && u.Scheme != backend.BackendKeyHTTPS) {
+			return stateConfig, errors.Errorf("invalid address '%s' in %s backend configuration", address, c.Type)
+		}
+		stateConfig.Backend = u.Scheme
+		stateConfig.Path = strings.TrimPrefix(address, u.Scheme+"://")
+	case "consul":
+		statePath, err := c.requiredAttr("path")
+		if err != nil {
+			return stateConfig, err
+		}
+		address := c.attr("address", os.Getenv("CONSUL_HTTP_ADDR"))
+		if address == "" {
+			address = "localhost:8500"
+		}
+		address = strings.TrimPrefix(strings.TrimPrefix(address, "http://"), "https://")
+		stateConfig.Backend = backend.BackendKeyConsul
+		stateConfig.Path = path.Join(address, statePath)
+		if !isDefault {
+			stateConfig.Path += "-env:" + workspace
+		}
+	case "pg":
+		connStr := c.attr("conn_str", os.Getenv("PG_CONN_STR"))
+		u, err := url.Parse(connStr)
+		if connStr == "" || err != nil || (u.Scheme != "postgres" && u.Scheme != "postgresql") {
+			return stateConfig, errors.Errorf("missing or invalid 'conn_str' in %s backend configuration, only URLs are supported", c.Type)
+		}
+		// Credentials are only given to the pg backend of this source, they must not show up in the state location
+		opts.PgCredentials = u.User
+		u.User = nil
+		query := u.Query()
+		if schema := c.attr("schema_name", ""); schema != "" {
+			query.Set("schema_name", schema)
+		}
+		query.Set("workspace", workspace)
+		u.RawQuery = query.Encode()
+		stateConfig.Backend = backend.BackendKeyPg
+		stateConfig.Path = strings.TrimPrefix(u.String(), u.Scheme+"://")
+	case "kubernetes":
+		suffix, err := c.requiredAttr("secret_suffix")
+		if err != nil {
+			return stateConfig, err
+		}
+		stateConfig.Backend = backend.BackendKeyKubernetes
+		stateConfig.Path = fmt.Sprintf("%s/tfstate-%s-%s", c.attr("namespace", "default"), workspace, suffix)
+	case "remote", "cloud":
+		organization, err := c.requiredAttr("organization")
+		if err != nil {
+			return stateConfig, err
+		}
+		if hostname := c.attr("hostname", ""); hostname != "" {
+			opts.TFCloudEndpoint = fmt.Sprintf("https://%s/api/v2", hostname)
+		}
+		stateConfig.Backend = backend.BackendKeyTFCloud
+		name := c.Workspaces["name"]
+		if name == "" {
+			prefix, ok := c.Workspaces["prefix"]
+			if !ok {
+				return stateConfig, errors.Errorf("missing workspaces name or prefix in %s backend configuration", c.Type)
+			}
+			// Only the workspaces of the prefix belong to this configuration, not every workspace of the organization
+			if opts.AllWorkspaces {
+				opts.TFCloudWorkspacePrefix = prefix
+				stateConfig.Path = organization
+				break
+			}
+			name = prefix + workspace
+		}
+		// A configuration with a workspace name has no other workspace
+		opts.AllWorkspaces = false
+		stateConfig.Path, err = backend.GetTFCloudWorkspaceId(&http.Client{}, organization, name, opts)
+		if err != nil {
+			return stateConfig, err
+		}
+	default:
+		return stateConfig, errors.Errorf("terraform backend '%s' is not supported", c.Type)
+	}
+
+	return stateConfig, nil
+}
+
+func (c *BackendConfig) attr(name, defaultValue string) string {
+	if value, ok := c.Attributes[name]; ok {
+		return value
+	}
+	return defaultValue
+}
+
+func (c *BackendConfig) requiredAttr(name string) (string, error) {
+	value, ok := c.Attributes[name]
+	if !ok {
+		return "", errors.Errorf("missing '%s' in %s backend configuration", name, c.Type)
+	}
+	return value, nil
+}
+
+func (c *BackendConfig) requiredAttrs(names ...string) ([]string, error) {
+	values := make([]string, 0, len(names))
+	for _, name := range names {
+		value, err := c.requiredAttr(name)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
@@ -0,0 +1,6 @@
+terraform {
+  backend "local" {
+    path          = "states/terraform.tfstate"
+    workspace_dir = "states"
+  }
+}
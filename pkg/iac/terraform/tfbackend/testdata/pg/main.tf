@@ -0,0 +1,6 @@
+terraform {
+  backend "pg" {
+    conn_str    = "postgres://localhost/terraform?sslmode=disable"
+    schema_name = "infra"
+  }
+}
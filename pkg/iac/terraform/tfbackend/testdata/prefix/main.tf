@@ -0,0 +1,9 @@
+terraform {
+  backend "remote" {
+    organization = "cloudskiff"
+
+    workspaces {
+      prefix = "infra-"
+    }
+  }
+}
@@ -0,0 +1,10 @@
+terraform {
+  backend "remote" {
+    hostname     = "tfe.example.com"
+    organization = "cloudskiff"
+
+    workspaces {
+      name = "infra"
+    }
+  }
+}
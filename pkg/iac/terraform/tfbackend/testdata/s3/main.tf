@@ -0,0 +1,9 @@
+terraform {
+  backend "s3" {
+    bucket               = "terraform-states"
+    key                  = "infra/terraform.tfstate"
+    region               = "us-east-1"
+    workspace_key_prefix = "workspaces"
+    encrypt              = true
+  }
+}
@@ -0,0 +1,6 @@
+terraform {
+  backend "etcdv3" {
+    endpoints = ["etcd-1:2379"]
+    prefix    = "terraform-state/"
+  }
+}
@@ -0,0 +1,122 @@
+package tfconfig
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform/configs"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/cloudskiff/driftctl/pkg/filter"
+	"github.com/cloudskiff/driftctl/pkg/iac/config"
+	"github.com/cloudskiff/driftctl/pkg/resource"
+)
+
+const TerraformConfigReaderSupplier = "tfconfig"
+
+// TerraformConfigReader reads resources declared in terraform configuration files.
+// Since those resources may not have been applied yet, their id is their terraform address.
+type TerraformConfigReader struct {
+	config config.SupplierConfig
+	parser *configs.Parser
+	filter filter.Filter
+}
+
+func NewReader(config config.SupplierConfig, filter filter.Filter) (*TerraformConfigReader, error) {
+	if config.Backend != "" {
+		return nil, errors.Errorf("backends are not supported by the %s IaC source", TerraformConfigReaderSupplier)
+	}
+	return &TerraformConfigReader{
+		config: config,
+		parser: configs.NewParser(nil),
+		filter: filter,
+	}, nil
+}
+
+func (r *TerraformConfigReader) Resources() ([]*resource.Resource, error) {
+	logrus.WithFields(logrus.Fields{
+		"path": r.config.Path,
+	}).Debug("Reading resources from terraform configuration")
+
+	resources, err := r.readModule(r.config.Path, "")
+	return resources, errors.Wrap(err, r.config.String())
+}
+
+// readModule reads resources of a configuration directory, local modules are read recursively
+func (r *TerraformConfigReader) readModule(dir, namespace string) ([]*resource.Resource, error) {
+	module, diags := r.parser.LoadConfigDir(dir)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	keys := make([]string, 0, len(module.ManagedResources))
+	for key := range module.ManagedResources {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	results := make([]*resource.Resource, 0, len(keys))
+	for _, key := range keys {
+		res := module.ManagedResources[key]
+		if !resource.IsResourceTypeSupported(res.Type) {
+			logrus.WithFields(logrus.Fields{
+				"name": res.Name,
+				"type": res.Type,
+			}).Debug("Ignored unsupported resource from terraform configuration")
+			continue
+		}
+
+		if r.filter != nil && r.filter.IsTypeIgnored(resource.ResourceType(res.Type)) {
+			logrus.WithFields(logrus.Fields{
+				"name": res.Name,
+				"type": res.Type,
+			}).Debug("Ignored resource from terraform configuration since it is ignored in filter")
+			continue
+		}
+
+		source := resource.NewTerraformConfigSource(r.config.String(), namespace, res.Name)
+		planned := &resource.Resource{
+			Type:   res.Type,
+			Attrs:  &resource.Attributes{},
+			Source: source,
+		}
+		planned.Id = planned.SourceString()
+		results = append(results, planned)
+	}
+
+	calls := make([]string, 0, len(module.ModuleCalls))
+	for name := range module.ModuleCalls {
+		calls = append(calls, name)
+	}
+	sort.Strings(calls)
+
+	for _, name := range calls {
+		call := module.ModuleCalls[name]
+		if !isLocalModule(call.SourceAddr) {
+			logrus.WithFields(logrus.Fields{
+				"module": name,
+				"source": call.SourceAddr,
+			}).Debug("Skipping non local module from terraform configuration")
+			continue
+		}
+
+		moduleNamespace := fmt.Sprintf("module.%s", name)
+		if namespace != "" {
+			moduleNamespace = fmt.Sprintf("%s.%s", namespace, moduleNamespace)
+		}
+		moduleResources, err := r.readModule(filepath.Join(dir, call.SourceAddr), moduleNamespace)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, moduleResources...)
+	}
+
+	return results, nil
+}
+
+func isLocalModule(source string) bool {
+	return strings.HasPrefix(source, "./") || strings.HasPrefix(source, "../")
+}
@@ -0,0 +1,103 @@
+package tfconfig
+
+import (
+	"testing"
+
+	"github.com/cloudskiff/driftctl/pkg/filter"
+	"github.com/cloudskiff/driftctl/pkg/iac/config"
+	"github.com/cloudskiff/driftctl/pkg/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestTerraformConfigReader_Resources(t *testing.T) {
+	cases := []struct {
+		name     string
+		path     string
+		mocks    func(f *filter.MockFilter)
+		expected []*resource.Resource
+		err      string
+	}{
+		{
+			name: "test resources from root and local modules",
+			path: "testdata/config",
+			mocks: func(f *filter.MockFilter) {
+				f.On("IsTypeIgnored", mock.Anything).Return(false)
+			},
+			expected: []*resource.Resource{
+				{
+					Id:     "aws_s3_bucket.logs",
+					Type:   "aws_s3_bucket",
+					Attrs:  &resource.Attributes{},
+					Source: resource.NewTerraformConfigSource("tfconfig://testdata/config", "", "logs"),
+				},
+				{
+					Id:     "github_repository.driftctl",
+					Type:   "github_repository",
+					Attrs:  &resource.Attributes{},
+					Source: resource.NewTerraformConfigSource("tfconfig://testdata/config", "", "driftctl"),
+				},
+				{
+					Id:     "module.bucket.aws_s3_bucket.bucket",
+					Type:   "aws_s3_bucket",
+					Attrs:  &resource.Attributes{},
+					Source: resource.NewTerraformConfigSource("tfconfig://testdata/config", "module.bucket", "bucket"),
+				},
+			},
+		},
+		{
+			name: "test filtered resource types",
+			path: "testdata/config",
+			mocks: func(f *filter.MockFilter) {
+				f.On("IsTypeIgnored", resource.ResourceType("aws_s3_bucket")).Return(true)
+				f.On("IsTypeIgnored", mock.Anything).Return(false)
+			},
+			expected: []*resource.Resource{
+				{
+					Id:     "github_repository.driftctl",
+					Type:   "github_repository",
+					Attrs:  &resource.Attributes{},
+					Source: resource.NewTerraformConfigSource("tfconfig://testdata/config", "", "driftctl"),
+				},
+			},
+		},
+		{
+			name:  "test invalid configuration",
+			path:  "testdata/invalid",
+			mocks: func(f *filter.MockFilter) {},
+			err:   "tfconfig://testdata/invalid: testdata/invalid/main.tf:2,1-1: Argument or block definition required; An argument or block definition is required here., and 1 other diagnostic(s)",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			f := &filter.MockFilter{}
+			c.mocks(f)
+
+			reader, err := NewReader(config.SupplierConfig{
+				Key:  TerraformConfigReaderSupplier,
+				Path: c.path,
+			}, f)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			got, err := reader.Resources()
+			if c.err != "" {
+				assert.EqualError(t, err, c.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, c.expected, got)
+		})
+	}
+}
+
+func TestNewReader_WithBackend(t *testing.T) {
+	_, err := NewReader(config.SupplierConfig{
+		Key:     TerraformConfigReaderSupplier,
+		Backend: "s3",
+		Path:    "bucket/infra",
+	}, nil)
+	assert.EqualError(t, err, "backends are not supported by the tfconfig IaC source")
+}
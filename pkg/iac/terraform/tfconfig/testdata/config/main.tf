@@ -0,0 +1,21 @@
+resource "aws_s3_bucket" "logs" {
+  bucket = "my-logs"
+}
+
+resource "github_repository" "driftctl" {
+  name = "driftctl"
+}
+
+resource "unsupported_resource" "foo" {
+}
+
+data "aws_caller_identity" "current" {}
+
+module "bucket" {
+  source = "./modules/bucket"
+}
+
+module "vpc" {
+  source  = "terraform-aws-modules/vpc/aws"
+  version = "3.0.0"
+}
@@ -0,0 +1,4 @@
+resource "aws_s3_bucket" "bucket" {
+  count  = 2
+  bucket = "my-bucket-${count.index}"
+}
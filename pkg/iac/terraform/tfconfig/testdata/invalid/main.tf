@@ -0,0 +1 @@
+resource "aws_s3_bucket" {
@@ -0,0 +1,346 @@
+package tfplan
+
+import (
+	"encoding/json"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/zclconf/go-cty/cty"
+	ctyconvert "github.com/zclconf/go-cty/cty/convert"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+
+	"github.com/cloudskiff/driftctl/pkg/filter"
+	"github.com/cloudskiff/driftctl/pkg/iac/config"
+	"github.com/cloudskiff/driftctl/pkg/iac/terraform/state/backend"
+	"github.com/cloudskiff/driftctl/pkg/output"
+	"github.com/cloudskiff/driftctl/pkg/resource"
+	"github.com/cloudskiff/driftctl/pkg/terraform"
+)
+
+const TerraformPlanReaderSupplier = "tfplan"
+
+const managedResourceMode = "managed"
+
+// plan holds the parts of the output of "terraform show -json" we rely on
+type plan struct {
+	FormatVersion string      `json:"format_version"`
+	PriorState    *planState  `json:"prior_state"`
+	PlannedValues *planValues `json:"planned_values"`
+}
+
+type planState struct {
+	Values *planValues `json:"values"`
+}
+
+type planValues struct {
+	RootModule *planModule `json:"root_module"`
+}
+
+type planModule struct {
+	Address      string         `json:"address"`
+	Resources    []planResource `json:"resources"`
+	ChildModules []planModule   `json:"child_modules"`
+}
+
+type planResource struct {
+	Address      string          `json:"address"`
+	Mode         string          `json:"mode"`
+	Type         string          `json:"type"`
+	Name         string          `json:"name"`
+	ProviderName string          `json:"provider_name"`
+	Values       json.RawMessage `json:"values"`
+	module       string
+}
+
+// TerraformPlanReader reads resources from a JSON plan.
+// Resources of the prior state are compared to the cloud provider with the values the plan is about to apply,
+// resources that the plan is about to create are reported as planned since they cannot exist yet.
+type TerraformPlanReader struct {
+	library        *terraform.ProviderLibrary
+	config         config.SupplierConfig
+	deserializer   *resource.Deserializer
+	backendOptions *backend.Options
+	progress       output.Progress
+	filter         filter.Filter
+}
+
+func NewReader(config config.SupplierConfig, library *terraform.ProviderLibrary, backendOpts *backend.Options, progress output.Progress, deserializer *resource.Deserializer, filter filter.Filter) (*TerraformPlanReader, error) {
+	if backend.IsStateStore(config.Backend) {
+		return nil, errors.Errorf("backend '%s' is not supported by the %s IaC source", config.Backend, TerraformPlanReaderSupplier)
+	}
+	return &TerraformPlanReader{
+		library:        library,
+		config:         config,
+		deserializer:   deserializer,
+		backendOptions: backendOpts,
+		progress:       progress,
+		filter:         filter,
+	}, nil
+}
+
+func (r *TerraformPlanReader) Resources() ([]*resource.Resource, error) {
+	logrus.WithFields(logrus.Fields{
+		"path":    r.config.Path,
+		"backend": r.config.Backend,
+	}).Debug("Reading resources from plan")
+	r.progress.Inc()
+
+	p, err := r.read()
+	if err != nil {
+		return nil, errors.Wrap(err, r.config.String())
+	}
+	resources, err := r.decode(p)
+	return resources, errors.Wrap(err, r.config.String())
+}
+
+func (r *TerraformPlanReader) read() (*plan, error) {
+	b, err := backend.GetBackend(r.config, r.backendOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer b.Close()
+
+	p := &plan{}
+	if err := json.NewDecoder(b).Decode(p); err != nil {
+		return nil, errors.Wrap(err, "unable to parse plan")
+	}
+	if p.FormatVersion == "" {
+		return nil, errors.New("given file is not a valid terraform JSON plan, use 'terraform show -json' to generate it")
+	}
+	return p, nil
+}
+
+func (r *TerraformPlanReader) decode(p *plan) ([]*resource.Resource, error) {
+	results := make([]*resource.Resource, 0)
+	applied := make(map[string]struct{})
+
+	var plannedResources []planResource
+	if p.PlannedValues != nil {
+		plannedResources = r.managedResources(p.PlannedValues.RootModule)
+	}
+	planned := make(map[string]planResource, len(plannedResources))
+	for _, planRes := range plannedResources {
+		planned[planRes.Address] = planRes
+	}
+
+	if p.PriorState != nil && p.PriorState.Values != nil {
+		for _, priorRes := range r.managedResources(p.PriorState.Values.RootModule) {
+			var res *resource.Resource
+			var err error
+			if planRes, exist := planned[priorRes.Address]; exist {
+				res, err = r.decodeAppliedResource(priorRes, planRes)
+			} else {
+				// Resources the plan is about to destroy have no planned values
+				res, err = r.decodeResource(priorRes)
+			}
+			if err != nil {
+				logrus.WithFields(logrus.Fields{
+					"type":    priorRes.Type,
+					"address": priorRes.Address,
+				}).Warnf("Could not read from plan: %+v", err)
+				continue
+			}
+			if res == nil {
+				continue
+			}
+			applied[priorRes.Address] = struct{}{}
+			source := resource.NewTerraformStateSource(r.config.String(), priorRes.module, priorRes.Name)
+			source.InstanceKey = priorRes.instanceKey()
+			res.Source = source
+			results = append(results, res)
+		}
+	}
+
+	for _, planRes := range plannedResources {
+		if _, exist := applied[planRes.Address]; exist {
+			continue
+		}
+		res, err := r.decodePlannedResource(planRes)
+		if err != nil {
+			logrus.WithFields(logrus.Fields{
+				"type":    planRes.Type,
+				"address": planRes.Address,
+			}).Debugf("Could not read planned values: %+v", err)
+		}
+		if res == nil {
+			res = &resource.Resource{
+				Id:    planRes.Address,
+				Type:  planRes.Type,
+				Attrs: &resource.Attributes{},
+			}
+		}
+		source := resource.NewTerraformConfigSource(r.config.String(), planRes.module, planRes.Name)
+		source.InstanceKey = planRes.instanceKey()
+		res.Source = source
+		results = append(results, res)
+	}
+
+	return results, nil
+}
+
+// instanceKey returns the count or for_each key ending the resource address, e.g. [0] or ["key"]
+func (r planResource) instanceKey() string {
+	prefix := r.Type + "." + r.Name
+	if r.module != "" {
+		prefix = r.module + "." + prefix
+	}
+	return strings.TrimPrefix(r.Address, prefix)
+}
+
+// managedResources flattens supported managed resources of a module and its children, sorted by address
+func (r *TerraformPlanReader) managedResources(module *planModule) []planResource {
+	if module == nil {
+		return nil
+	}
+
+	results := make([]planResource, 0, len(module.Resources))
+	for _, res := range module.Resources {
+		if res.Mode != managedResourceMode {
+			continue
+		}
+		if !resource.IsResourceTypeSupported(res.Type) {
+			logrus.WithFields(logrus.Fields{
+				"address": res.Address,
+				"type":    res.Type,
+			}).Debug("Ignored unsupported resource from plan")
+			continue
+		}
+		if r.filter != nil && r.filter.IsTypeIgnored(resource.ResourceType(res.Type)) {
+			logrus.WithFields(logrus.Fields{
+				"address": res.Address,
+				"type":    res.Type,
+			}).Debug("Ignored resource from plan since it is ignored in filter")
+			continue
+		}
+		res.module = module.Address
+		results = append(results, res)
+	}
+	for i := range module.ChildModules {
+		results = append(results, r.managedResources(&module.ChildModules[i])...)
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Address < results[j].Address
+	})
+	return results
+}
+
+// decodeResource converts plan values using the provider schema, like values read from a state
+func (r *TerraformPlanReader) decodeResource(planRes planResource) (*resource.Resource, error) {
+	val, err := r.decodeValues(planRes)
+	if err != nil || val == nil {
+		return nil, err
+	}
+
+	if !val.Type().HasAttribute("id") || val.GetAttr("id").IsNull() {
+		return nil, errors.Errorf("missing id for %s", planRes.Address)
+	}
+
+	return r.deserializer.DeserializeOne(planRes.Type, *val)
+}
+
+// decodeAppliedResource converts the planned values of a resource that already exists, so the cloud provider
+// is compared to the configuration the plan is about to apply rather than to the refreshed prior state.
+// The id comes from the prior state, values unknown until the plan is applied are omitted from planned values
+// and are left empty
+func (r *TerraformPlanReader) decodeAppliedResource(priorRes, planRes planResource) (*resource.Resource, error) {
+	prior, err := r.decodeValues(priorRes)
+	if err != nil || prior == nil {
+		return nil, err
+	}
+	if !prior.Type().IsObjectType() || !prior.Type().HasAttribute("id") || prior.GetAttr("id").IsNull() {
+		return nil, errors.Errorf("missing id for %s", priorRes.Address)
+	}
+
+	val, err := r.decodeValues(planRes)
+	if err != nil || val == nil {
+		return nil, err
+	}
+	if !val.Type().IsObjectType() || !val.Type().HasAttribute("id") {
+		return nil, errors.Errorf("unexpected planned values for %s", planRes.Address)
+	}
+
+	attributes := val.AsValueMap()
+	attributes["id"] = prior.GetAttr("id")
+	return r.deserializer.DeserializeOne(planRes.Type, cty.ObjectVal(attributes))
+}
+
+// decodePlannedResource converts the planned values of a resource about to be created,
+// its id is unknown until it is applied so the resource is identified by its address
+func (r *TerraformPlanReader) decodePlannedResource(planRes planResource) (*resource.Resource, error) {
+	val, err := r.decodeValues(planRes)
+	if err != nil || val == nil {
+		return nil, err
+	}
+	if !val.Type().IsObjectType() || !val.Type().HasAttribute("id") {
+		return nil, errors.Errorf("unexpected planned values for %s", planRes.Address)
+	}
+
+	attributes := val.AsValueMap()
+	attributes["id"] = cty.StringVal(planRes.Address)
+	res, err := r.deserializer.DeserializeOne(planRes.Type, cty.ObjectVal(attributes))
+	if err != nil {
+		return nil, err
+	}
+	res.Attributes().SafeDelete([]string{"id"})
+	return res, nil
+}
+
+// decodeValues converts plan values using the provider schema, it returns nil when
+// the provider of the resource is not supported
+func (r *TerraformPlanReader) decodeValues(planRes planResource) (*cty.Value, error) {
+	// Provider names are fully qualified since terraform 0.13 (e.g. registry.terraform.io/hashicorp/aws)
+	providerType := path.Base(planRes.ProviderName)
+	provider := r.library.Provider(providerType)
+	if provider == nil {
+		logrus.WithFields(logrus.Fields{
+			"providerKey": providerType,
+		}).Debug("Unsupported provider found in plan")
+		return nil, nil
+	}
+	schema, exist := provider.Schema()[planRes.Type]
+	if !exist {
+		return nil, errors.Errorf("no schema found for %s", planRes.Type)
+	}
+
+	val, err := ctyjson.Unmarshal(planRes.Values, schema.Block.ImpliedType())
+	if err != nil {
+		// Plans generated with a different provider version may contain unknown or missing fields,
+		// fallback to a type conversion ignoring them
+		val, err = convertValues(planRes.Values, schema.Block.ImpliedType())
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &val, nil
+}
+
+func convertValues(values []byte, ty cty.Type) (cty.Value, error) {
+	inputType, err := ctyjson.ImpliedType(values)
+	if err != nil {
+		return cty.NilVal, err
+	}
+	input, err := ctyjson.Unmarshal(values, inputType)
+	if err != nil {
+		return cty.NilVal, err
+	}
+	if !ty.IsObjectType() || !input.Type().IsObjectType() {
+		return ctyconvert.Convert(input, ty)
+	}
+
+	attributes := make(map[string]cty.Value, len(ty.AttributeTypes()))
+	for name, attributeType := range ty.AttributeTypes() {
+		if !input.Type().HasAttribute(name) {
+			attributes[name] = cty.NullVal(attributeType)
+			continue
+		}
+		attributes[name], err = ctyconvert.Convert(input.GetAttr(name), attributeType)
+		if err != nil {
+			return cty.NilVal, errors.Wrap(err, name)
+		}
+	}
+	return cty.ObjectVal(attributes), nil
+}
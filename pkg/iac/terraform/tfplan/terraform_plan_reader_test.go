@@ -0,0 +1,149 @@
+package tfplan
+
+import (
+	"testing"
+
+	"github.com/cloudskiff/driftctl/pkg/alerter"
+	"github.com/cloudskiff/driftctl/pkg/analyser"
+	"github.com/cloudskiff/driftctl/pkg/filter"
+	"github.com/cloudskiff/driftctl/pkg/iac/config"
+	"github.com/cloudskiff/driftctl/pkg/output"
+	"github.com/cloudskiff/driftctl/pkg/resource"
+	resourcegithub "github.com/cloudskiff/driftctl/pkg/resource/github"
+	"github.com/cloudskiff/driftctl/pkg/terraform"
+	testresource "github.com/cloudskiff/driftctl/test/resource"
+	"github.com/cloudskiff/driftctl/test/schemas"
+	"github.com/hashicorp/terraform/providers"
+	"github.com/stretchr/testify/assert"
+	"github.com/zclconf/go-cty/cty"
+)
+
+type fakeProvider struct {
+	schema map[string]providers.Schema
+}
+
+func (p *fakeProvider) Schema() map[string]providers.Schema {
+	return p.schema
+}
+
+func (p *fakeProvider) ReadResource(terraform.ReadResourceArgs) (*cty.Value, error) {
+	return nil, nil
+}
+
+func (p *fakeProvider) Cleanup()        {}
+func (p *fakeProvider) Name() string    { return terraform.GITHUB }
+func (p *fakeProvider) Version() string { return "4.4.0" }
+
+func TestTerraformPlanReader_Resources(t *testing.T) {
+	schema, err := schemas.ReadTestSchema(terraform.GITHUB, "4.4.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	library := terraform.NewProviderLibrary()
+	library.AddProvider(terraform.GITHUB, &fakeProvider{schema: schema})
+
+	repo := testresource.InitFakeSchemaRepository(terraform.GITHUB, "4.4.0")
+	resourcegithub.InitResourcesMetadata(repo)
+	factory := terraform.NewTerraformResourceFactory(repo)
+
+	progress := &output.MockProgress{}
+	progress.On("Inc").Return().Times(1)
+
+	reader, err := NewReader(config.SupplierConfig{
+		Key:  TerraformPlanReaderSupplier,
+		Path: "testdata/plan.json",
+	}, library, nil, progress, resource.NewDeserializer(factory), nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	got, err := reader.Resources()
+	assert.NoError(t, err)
+	if !assert.Len(t, got, 5) {
+		return
+	}
+	progress.AssertExpectations(t)
+
+	// Resources that already exist hold the values the plan is about to apply
+	assert.Equal(t, "existing", got[0].ResourceId())
+	assert.Equal(t, "github_repository", got[0].ResourceType())
+	assert.Equal(t, resource.NewTerraformStateSource("tfplan://testdata/plan.json", "", "existing"), got[0].Source)
+	description, _ := got[0].Attributes().Get("description")
+	assert.Equal(t, "updated description", description)
+
+	// Resources about to be destroyed hold the values of the prior state
+	assert.Equal(t, "removed", got[1].ResourceId())
+	assert.Equal(t, resource.NewTerraformStateSource("tfplan://testdata/plan.json", "", "removed"), got[1].Source)
+	description, _ = got[1].Attributes().Get("description")
+	assert.Equal(t, "about to be destroyed", description)
+
+	// The id comes from the prior state when it is unknown in planned values
+	assert.Equal(t, "4242", got[2].ResourceId())
+	assert.Equal(t, "github_team", got[2].ResourceType())
+	assert.Equal(t, &resource.TerraformStateSource{
+		State:       "tfplan://testdata/plan.json",
+		Module:      "module.teams",
+		Name:        "team",
+		InstanceKey: "[0]",
+	}, got[2].Source)
+
+	// Resources about to be created are identified by their address
+	assert.Equal(t, "github_repository.new", got[3].ResourceId())
+	assert.Equal(t, "github_repository", got[3].ResourceType())
+	assert.Equal(t, resource.NewTerraformConfigSource("tfplan://testdata/plan.json", "", "new"), got[3].Source)
+	assert.Equal(t, "new", *got[3].Attributes().GetString("name"))
+	assert.Nil(t, got[3].Attributes().GetString("id"))
+
+	assert.Equal(t, "module.teams.github_team.team[1]", got[4].ResourceId())
+	assert.Equal(t, "github_team", got[4].ResourceType())
+	assert.Equal(t, &resource.TerraformConfigSource{
+		Path:        "tfplan://testdata/plan.json",
+		Module:      "module.teams",
+		Name:        "team",
+		InstanceKey: "[1]",
+	}, got[4].Source)
+	assert.Equal(t, "team-2", *got[4].Attributes().GetString("name"))
+
+	// The cloud provider matches the refreshed prior state, the change the plan is about to apply is a drift
+	remote := []*resource.Resource{{
+		Id:    "existing",
+		Type:  "github_repository",
+		Attrs: &resource.Attributes{"id": "existing", "name": "existing", "description": "description"},
+	}}
+	analysis, err := analyser.NewAnalyzer(alerter.NewAlerter(), analyser.AnalyzerOptions{Deep: true}, filter.NewDriftIgnore("", alerter.NewAlerter())).
+		Analyze(remote, got[:1])
+	assert.NoError(t, err)
+	if assert.Len(t, analysis.Differences(), 1) {
+		changelog := analysis.Differences()[0].Changelog
+		if assert.Len(t, changelog, 1) {
+			assert.Equal(t, []string{"description"}, changelog[0].Path)
+			assert.Equal(t, "updated description", changelog[0].From)
+			assert.Equal(t, "description", changelog[0].To)
+		}
+	}
+}
+
+func TestTerraformPlanReader_InvalidPlan(t *testing.T) {
+	progress := &output.MockProgress{}
+	progress.On("Inc").Return()
+
+	reader, err := NewReader(config.SupplierConfig{
+		Key:  TerraformPlanReaderSupplier,
+		Path: "testdata/state.json",
+	}, terraform.NewProviderLibrary(), nil, progress, nil, nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	_, err = reader.Resources()
+	assert.EqualError(t, err, "tfplan://testdata/state.json: given file is not a valid terraform JSON plan, use 'terraform show -json' to generate it")
+}
+
+func TestNewReader_WithTFCloudBackend(t *testing.T) {
+	_, err := NewReader(config.SupplierConfig{
+		Key:     TerraformPlanReaderSupplier,
+		Backend: "tfcloud",
+		Path:    "workspace_id",
+	}, nil, nil, nil, nil, nil)
+	assert.EqualError(t, err, "backend 'tfcloud' is not supported by the tfplan IaC source")
+}
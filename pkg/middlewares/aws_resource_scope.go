@@ -0,0 +1,207 @@
+package middlewares
+
+import (
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/sirupsen/logrus"
+
+	"github.com/cloudskiff/driftctl/pkg/resource"
+	"github.com/cloudskiff/driftctl/pkg/resource/aws"
+)
+
+type scopeReference struct {
+	attribute string
+	ty        string
+}
+
+// scopeReferences locates resources without ARN nor region from a resource they belong to
+var scopeReferences = map[string][]scopeReference{
+	aws.AwsIamRolePolicyAttachmentResourceType: {{"role", aws.AwsIamRoleResourceType}},
+	aws.AwsIamUserPolicyAttachmentResourceType: {{"user", aws.AwsIamUserResourceType}},
+	aws.AwsSecurityGroupRuleResourceType:       {{"security_group_id", aws.AwsSecurityGroupResourceType}},
+	aws.AwsRouteResourceType:                   {{"route_table_id", aws.AwsRouteTableResourceType}},
+	aws.AwsRouteTableAssociationResourceType: {
+		{"route_table_id", aws.AwsRouteTableResourceType},
+		{"subnet_id", aws.AwsSubnetResourceType},
+	},
+}
+
+// AwsResourceScope locates state resources in the account and region of their ARN when several
+// accounts or regions are scanned, so they are only matched with resources found in the same place.
+// Resources without ARN are located from a resource they belong to, or from the other resources of their state
+// when all of them are in the same place. Resources that cannot be located do not match any remote resource.
+type AwsResourceScope struct{}
+
+func NewAwsResourceScope() AwsResourceScope {
+	return AwsResourceScope{}
+}
+
+func (m AwsResourceScope) Execute(remoteResources, resourcesFromState *[]*resource.Resource) error {
+	if !hasScopedResource(*remoteResources) {
+		return nil
+	}
+
+	located := make(map[string]*resource.Resource)
+	unlocated := make([]*resource.Resource, 0)
+	for _, res := range *resourcesFromState {
+		if !strings.HasPrefix(res.ResourceType(), "aws_") {
+			continue
+		}
+		if res.Scope == nil && res.Attrs != nil {
+			res.Scope = scopeFromAttributes(res)
+		}
+		if res.Scope == nil {
+			unlocated = append(unlocated, res)
+			continue
+		}
+		located[res.ResourceType()+"."+res.ResourceId()] = res
+	}
+
+	for _, res := range unlocated {
+		res.Scope = scopeFromReferences(res, located)
+	}
+
+	states := stateScopes(*resourcesFromState)
+	unknown := 0
+	for _, res := range unlocated {
+		if res.Scope != nil {
+			continue
+		}
+		if res.Source != nil {
+			if scope, ok := states[res.Source.Source()]; ok {
+				res.Scope = scope.of(res.ResourceType())
+			}
+		}
+		if res.Scope == nil {
+			res.Scope = &resource.Scope{Unknown: true}
+			unknown++
+			logrus.WithFields(logrus.Fields{
+				"type": res.ResourceType(),
+				"id":   res.ResourceId(),
+			}).Debug("Unable to locate state resource in an AWS account and region")
+		}
+	}
+	if unknown > 0 {
+		logrus.Warnf("%d resource(s) of your states could not be located in one of the scanned AWS accounts and regions, they will not match any remote resource", unknown)
+	}
+
+	return nil
+}
+
+func scopeFromAttributes(res *resource.Resource) *resource.Scope {
+	scope := resource.Scope{}
+	if value, ok := stringAttribute(res, "arn"); ok {
+		if parsed, err := arn.Parse(value); err == nil {
+			scope.Account = parsed.AccountID
+			scope.Region = parsed.Region
+		}
+	}
+	// S3 buckets ARN holds neither the account nor the region
+	if value, ok := stringAttribute(res, "region"); ok && scope.Region == "" {
+		scope.Region = value
+	}
+	// Policies managed by AWS belong to the aws account
+	if value, ok := stringAttribute(res, "policy_arn"); ok && scope.Account == "" {
+		if parsed, err := arn.Parse(value); err == nil && parsed.AccountID != "aws" {
+			scope.Account = parsed.AccountID
+		}
+	}
+	if scope == (resource.Scope{}) {
+		return nil
+	}
+	return &scope
+}
+
+func scopeFromReferences(res *resource.Resource, located map[string]*resource.Resource) *resource.Scope {
+	if res.Attrs == nil {
+		return nil
+	}
+	for _, ref := range scopeReferences[res.ResourceType()] {
+		id, ok := stringAttribute(res, ref.attribute)
+		if !ok {
+			continue
+		}
+		if parent, exists := located[ref.ty+"."+id]; exists {
+			scope := *parent.Scope
+			return &scope
+		}
+	}
+	return nil
+}
+
+// stateScope is the location shared by every located resource of a state, a field is empty when they differ
+type stateScope struct {
+	account string
+	region  string
+}
+
+func (s stateScope) of(ty string) *resource.Scope {
+	scope := resource.Scope{Account: s.account}
+	if !aws.IsGlobalResourceType(ty) {
+		scope.Region = s.region
+	}
+	if scope == (resource.Scope{}) {
+		return nil
+	}
+	return &scope
+}
+
+func stateScopes(resources []*resource.Resource) map[string]stateScope {
+	accounts := make(map[string]map[string]bool)
+	regions := make(map[string]map[string]bool)
+	for _, res := range resources {
+		if res.Source == nil || res.Scope == nil || res.Scope.Unknown {
+			continue
+		}
+		state := res.Source.Source()
+		if accounts[state] == nil {
+			accounts[state] = make(map[string]bool)
+			regions[state] = make(map[string]bool)
+		}
+		if res.Scope.Account != "" {
+			accounts[state][res.Scope.Account] = true
+		}
+		if res.Scope.Region != "" {
+			regions[state][res.Scope.Region] = true
+		}
+	}
+
+	scopes := make(map[string]stateScope, len(accounts))
+	for state := range accounts {
+		scopes[state] = stateScope{
+			account: single(accounts[state]),
+			region:  single(regions[state]),
+		}
+	}
+	return scopes
+}
+
+// single returns the only value of a set, or an empty string when it does not hold exactly one value
+func single(values map[string]bool) string {
+	if len(values) != 1 {
+		return ""
+	}
+	for value := range values {
+		return value
+	}
+	return ""
+}
+
+func hasScopedResource(resources []*resource.Resource) bool {
+	for _, res := range resources {
+		if res.Scope != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func stringAttribute(res *resource.Resource, name string) (string, bool) {
+	value, exists := res.Attrs.Get(name)
+	if !exists {
+		return "", false
+	}
+	str, ok := value.(string)
+	return str, ok && str != ""
+}
@@ -0,0 +1,228 @@
+package middlewares
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudskiff/driftctl/pkg/resource"
+)
+
+func TestAwsResourceScope_Execute(t *testing.T) {
+	tests := []struct {
+		name               string
+		remoteResources    []*resource.Resource
+		resourcesFromState []*resource.Resource
+		expected           []*resource.Scope
+	}{
+		{
+			name: "state resources are not scoped when remote resources are not",
+			remoteResources: []*resource.Resource{
+				{Id: "queue", Type: "aws_sqs_queue"},
+			},
+			resourcesFromState: []*resource.Resource{
+				{
+					Id:    "queue",
+					Type:  "aws_sqs_queue",
+					Attrs: &resource.Attributes{"arn": "arn:aws:sqs:us-east-1:123456789012:queue"},
+				},
+			},
+			expected: []*resource.Scope{nil},
+		},
+		{
+			name: "state resources are scoped from their arn and region",
+			remoteResources: []*resource.Resource{
+				{Id: "queue", Type: "aws_sqs_queue", Scope: &resource.Scope{Account: "123456789012", Region: "us-east-1"}},
+			},
+			resourcesFromState: []*resource.Resource{
+				{
+					Id:    "queue",
+					Type:  "aws_sqs_queue",
+					Attrs: &resource.Attributes{"arn": "arn:aws:sqs:us-east-1:123456789012:queue"},
+				},
+				{
+					Id:    "driftctl",
+					Type:  "aws_iam_user",
+					Attrs: &resource.Attributes{"arn": "arn:aws:iam::123456789012:user/driftctl"},
+				},
+				{
+					Id:    "bucket",
+					Type:  "aws_s3_bucket",
+					Attrs: &resource.Attributes{"arn": "arn:aws:s3:::bucket", "region": "eu-west-3"},
+				},
+				{
+					Id:    "rtbassoc-0123",
+					Type:  "aws_route_table_association",
+					Attrs: &resource.Attributes{"route_table_id": "rtb-0123"},
+				},
+				{
+					Id:    "driftctl",
+					Type:  "github_repository",
+					Attrs: &resource.Attributes{"region": "eu-west-3"},
+				},
+			},
+			expected: []*resource.Scope{
+				{Account: "123456789012", Region: "us-east-1"},
+				{Account: "123456789012"},
+				{Region: "eu-west-3"},
+				{Unknown: true},
+				nil,
+			},
+		},
+		{
+			name: "state resources without arn are scoped from the resource they belong to",
+			remoteResources: []*resource.Resource{
+				{Id: "rtb-0123", Type: "aws_route_table", Scope: &resource.Scope{Account: "123456789012", Region: "us-east-1"}},
+			},
+			resourcesFromState: []*resource.Resource{
+				{
+					Id:    "rtb-0123",
+					Type:  "aws_route_table",
+					Attrs: &resource.Attributes{"arn": "arn:aws:ec2:us-east-1:123456789012:route-table/rtb-0123"},
+				},
+				{
+					Id:    "rtbassoc-0123",
+					Type:  "aws_route_table_association",
+					Attrs: &resource.Attributes{"route_table_id": "rtb-0123"},
+				},
+				{
+					Id:    "r-rtb-01231080289494",
+					Type:  "aws_route",
+					Attrs: &resource.Attributes{"route_table_id": "rtb-0123"},
+				},
+				{
+					Id:    "deploy",
+					Type:  "aws_iam_role",
+					Attrs: &resource.Attributes{"arn": "arn:aws:iam::123456789012:role/deploy"},
+				},
+				{
+					Id:    "deploy-20210101000000000000000001",
+					Type:  "aws_iam_role_policy_attachment",
+					Attrs: &resource.Attributes{"role": "deploy", "policy_arn": "arn:aws:iam::aws:policy/ReadOnlyAccess"},
+				},
+				{
+					Id:    "ci-20210101000000000000000002",
+					Type:  "aws_iam_user_policy_attachment",
+					Attrs: &resource.Attributes{"user": "ci", "policy_arn": "arn:aws:iam::210987654321:policy/deploy"},
+				},
+			},
+			expected: []*resource.Scope{
+				{Account: "123456789012", Region: "us-east-1"},
+				{Account: "123456789012", Region: "us-east-1"},
+				{Account: "123456789012", Region: "us-east-1"},
+				{Account: "123456789012"},
+				{Account: "123456789012"},
+				{Account: "210987654321"},
+			},
+		},
+		{
+			name: "state resources without arn are scoped from the other resources of their state",
+			remoteResources: []*resource.Resource{
+				{Id: "queue", Type: "aws_sqs_queue", Scope: &resource.Scope{Account: "123456789012", Region: "us-east-1"}},
+			},
+			resourcesFromState: []*resource.Resource{
+				{
+					Id:     "queue",
+					Type:   "aws_sqs_queue",
+					Attrs:  &resource.Attributes{"arn": "arn:aws:sqs:us-east-1:123456789012:queue"},
+					Source: resource.NewTerraformStateSource("production.tfstate", "", "queue"),
+				},
+				{
+					Id:     "sgrule-0123",
+					Type:   "aws_security_group_rule",
+					Attrs:  &resource.Attributes{"security_group_id": "sg-0123"},
+					Source: resource.NewTerraformStateSource("production.tfstate", "", "rule"),
+				},
+				{
+					Id:     "Z0123_www.example.com_A",
+					Type:   "aws_route53_record",
+					Attrs:  &resource.Attributes{"zone_id": "Z0123"},
+					Source: resource.NewTerraformStateSource("production.tfstate", "", "www"),
+				},
+				{
+					Id:     "queue",
+					Type:   "aws_sqs_queue",
+					Attrs:  &resource.Attributes{"arn": "arn:aws:sqs:us-east-1:210987654321:queue"},
+					Source: resource.NewTerraformStateSource("regions.tfstate", "", "us"),
+				},
+				{
+					Id:     "queue",
+					Type:   "aws_sqs_queue",
+					Attrs:  &resource.Attributes{"arn": "arn:aws:sqs:eu-west-3:210987654321:queue"},
+					Source: resource.NewTerraformStateSource("regions.tfstate", "", "eu"),
+				},
+				{
+					Id:     "sgrule-4567",
+					Type:   "aws_security_group_rule",
+					Attrs:  &resource.Attributes{"security_group_id": "sg-4567"},
+					Source: resource.NewTerraformStateSource("regions.tfstate", "", "rule"),
+				},
+				{
+					Id:     "sgrule-89ab",
+					Type:   "aws_security_group_rule",
+					Attrs:  &resource.Attributes{"security_group_id": "sg-89ab"},
+					Source: resource.NewTerraformStateSource("rules.tfstate", "", "rule"),
+				},
+			},
+			expected: []*resource.Scope{
+				{Account: "123456789012", Region: "us-east-1"},
+				{Account: "123456789012", Region: "us-east-1"},
+				{Account: "123456789012"},
+				{Account: "210987654321", Region: "us-east-1"},
+				{Account: "210987654321", Region: "eu-west-3"},
+				{Account: "210987654321"},
+				{Unknown: true},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewAwsResourceScope()
+			if err := m.Execute(&tt.remoteResources, &tt.resourcesFromState); err != nil {
+				t.Fatal(err)
+			}
+			for i, res := range tt.resourcesFromState {
+				assert.Equal(t, tt.expected[i], res.Scope, res.ResourceId())
+			}
+		})
+	}
+}
+
+// A resource of a state read from an account must not match the resource with the same id in another account
+func TestAwsResourceScope_Execute_CrossAccount(t *testing.T) {
+	production := &resource.Scope{Account: "123456789012"}
+	staging := &resource.Scope{Account: "210987654321"}
+	remoteResources := []*resource.Resource{
+		{Id: "deploy-20210101000000000000000001", Type: "aws_iam_role_policy_attachment", Scope: production},
+		{Id: "deploy-20210101000000000000000001", Type: "aws_iam_role_policy_attachment", Scope: staging},
+		{Id: "sgrule-0123", Type: "aws_security_group_rule", Scope: &resource.Scope{Account: staging.Account, Region: "us-east-1"}},
+	}
+	resourcesFromState := []*resource.Resource{
+		{
+			Id:    "deploy",
+			Type:  "aws_iam_role",
+			Attrs: &resource.Attributes{"arn": "arn:aws:iam::210987654321:role/deploy"},
+		},
+		{
+			Id:    "deploy-20210101000000000000000001",
+			Type:  "aws_iam_role_policy_attachment",
+			Attrs: &resource.Attributes{"role": "deploy", "policy_arn": "arn:aws:iam::aws:policy/ReadOnlyAccess"},
+		},
+		{
+			Id:    "sgrule-0123",
+			Type:  "aws_security_group_rule",
+			Attrs: &resource.Attributes{"security_group_id": "sg-0123"},
+		},
+	}
+
+	m := NewAwsResourceScope()
+	if err := m.Execute(&remoteResources, &resourcesFromState); err != nil {
+		t.Fatal(err)
+	}
+
+	attachment := resourcesFromState[1]
+	assert.False(t, attachment.Equal(remoteResources[0]))
+	assert.True(t, attachment.Equal(remoteResources[1]))
+	// The rule cannot be located, it must not be taken for the one of the staging account
+	assert.False(t, resourcesFromState[2].Equal(remoteResources[2]))
+}
@@ -37,14 +37,21 @@ func (e *EC2RouteTableAssociationEnumerator) Enumerate() ([]*resource.Resource,
 			if e.shouldBeIgnored(assoc) {
 				continue
 			}
+			data := map[string]interface{}{
+				"route_table_id": *assoc.RouteTableId,
+			}
+			if assoc.SubnetId != nil {
+				data["subnet_id"] = *assoc.SubnetId
+			}
+			if assoc.GatewayId != nil {
+				data["gateway_id"] = *assoc.GatewayId
+			}
 			results = append(
 				results,
 				e.factory.CreateAbstractResource(
 					string(e.SupportedType()),
 					*assoc.RouteTableAssociationId,
-					map[string]interface{}{
-						"route_table_id": *assoc.RouteTableId,
-					},
+					data,
 				),
 			)
 		}
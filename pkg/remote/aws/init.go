@@ -1,6 +1,11 @@
 package aws
 
 import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
 	"github.com/cloudskiff/driftctl/pkg/alerter"
 	"github.com/cloudskiff/driftctl/pkg/output"
 	"github.com/cloudskiff/driftctl/pkg/remote/aws/client"
@@ -23,9 +28,15 @@ func Init(version string, alerter *alerter.Alerter,
 	progress output.Progress,
 	resourceSchemaRepository *resource.SchemaRepository,
 	factory resource.ResourceFactory,
-	configDir string) error {
+	configDir string,
+	installation *terraform.ProviderInstallation,
+	targets []Target) error {
 
-	provider, err := NewAWSTerraformProvider(version, progress, configDir)
+	if len(targets) > 0 {
+		return initTargets(version, alerter, providerLibrary, remoteLibrary, progress, resourceSchemaRepository, factory, configDir, installation, targets)
+	}
+
+	provider, err := NewAWSTerraformProvider(version, progress, configDir, installation)
 	if err != nil {
 		return err
 	}
@@ -33,7 +44,93 @@ func Init(version string, alerter *alerter.Alerter,
 	if err != nil {
 		return err
 	}
+	providerLibrary.AddProvider(terraform.AWS, provider)
+
+	initEnumerators(provider, alerter, remoteLibrary, factory)
+
+	return initSchemaRepository(provider, resourceSchemaRepository)
+}
+
+// initTargets starts a provider for each region of each target, resources of global services
+// are only enumerated in the first region of an account
+func initTargets(version string, alerter *alerter.Alerter,
+	providerLibrary *terraform.ProviderLibrary,
+	remoteLibrary *common.RemoteLibrary,
+	progress output.Progress,
+	resourceSchemaRepository *resource.SchemaRepository,
+	factory resource.ResourceFactory,
+	configDir string,
+	installation *terraform.ProviderInstallation,
+	targets []Target) error {
+
+	var provider *AWSTerraformProvider
+	scopes := make(map[resource.Scope]bool)
+	accounts := make(map[string]bool)
+	for i := range targets {
+		target := &targets[i]
+		regions := target.Regions
+		if len(regions) == 0 {
+			regions = []string{""}
+		}
+		for _, region := range regions {
+			sess, err := target.newSession(region)
+			if err != nil {
+				return err
+			}
+			if sess.Config.Region == nil || *sess.Config.Region == "" {
+				return errors.Errorf("no region configured for AWS target %d", i+1)
+			}
+			account, err := getAccountId(sess)
+			if err != nil {
+				return errors.Wrapf(err, "unable to get the account of AWS target %d", i+1)
+			}
+			scope := resource.Scope{Account: account, Region: *sess.Config.Region}
+			if scopes[scope] {
+				return errors.Errorf("region %s of account %s is listed by several AWS targets", scope.Region, scope.Account)
+			}
+			scopes[scope] = true
+
+			provider, err = newAWSTerraformProvider(version, progress, configDir, installation, sess, target)
+			if err != nil {
+				return err
+			}
+			if err := provider.Init(); err != nil {
+				return err
+			}
+			providerName := terraform.AWS
+			if len(scopes) > 1 {
+				providerName = fmt.Sprintf("%s.%s", terraform.AWS, scope.String())
+			}
+			providerLibrary.AddProvider(providerName, provider)
+
+			logrus.WithFields(logrus.Fields{
+				"account": scope.Account,
+				"region":  scope.Region,
+			}).Debug("Initialized AWS target")
+
+			initEnumerators(provider, alerter, &scopedRemoteLibrary{
+				library: remoteLibrary,
+				scope:   scope,
+				global:  !accounts[account],
+			}, factory)
+			accounts[account] = true
+		}
+	}
 
+	return initSchemaRepository(provider, resourceSchemaRepository)
+}
+
+func initSchemaRepository(provider *AWSTerraformProvider, resourceSchemaRepository *resource.SchemaRepository) error {
+	err := resourceSchemaRepository.Init(terraform.AWS, provider.Version(), provider.Schema())
+	if err != nil {
+		return err
+	}
+	aws.InitResourcesMetadata(resourceSchemaRepository)
+
+	return nil
+}
+
+func initEnumerators(provider *AWSTerraformProvider, alerter *alerter.Alerter, remoteLibrary enumeratorLibrary, factory resource.ResourceFactory) {
 	repositoryCache := cache.New(100)
 
 	s3Repository := repository.NewS3Repository(client.NewAWSClientFactory(provider.session), repositoryCache)
@@ -54,7 +151,6 @@ func Init(version string, alerter *alerter.Alerter,
 	apigatewayv2Repository := repository.NewApiGatewayV2Repository(provider.session, repositoryCache)
 
 	deserializer := resource.NewDeserializer(factory)
-	providerLibrary.AddProvider(terraform.AWS, provider)
 
 	remoteLibrary.AddEnumerator(NewS3BucketEnumerator(s3Repository, factory, provider.Config, alerter))
 	remoteLibrary.AddDetailsFetcher(aws.AwsS3BucketResourceType, common.NewGenericDetailsFetcher(aws.AwsS3BucketResourceType, provider, deserializer))
@@ -212,12 +308,4 @@ func Init(version string, alerter *alerter.Alerter,
 	remoteLibrary.AddDetailsFetcher(aws.AwsAppAutoscalingPolicyResourceType, common.NewGenericDetailsFetcher(aws.AwsAppAutoscalingPolicyResourceType, provider, deserializer))
 
 	remoteLibrary.AddEnumerator(NewAppAutoscalingScheduledActionEnumerator(appAutoScalingRepository, factory))
-
-	err = resourceSchemaRepository.Init(terraform.AWS, provider.Version(), provider.Schema())
-	if err != nil {
-		return err
-	}
-	aws.InitResourcesMetadata(resourceSchemaRepository)
-
-	return nil
 }
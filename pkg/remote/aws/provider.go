@@ -11,7 +11,7 @@ type awsConfig struct {
 	AccessKey     string
 	SecretKey     string
 	CredsFilename string
-	Profile       string
+	Profile       string `cty:"profile"`
 	Token         string
 	Region        string `cty:"region"`
 	MaxRetries    int
@@ -20,6 +20,7 @@ type awsConfig struct {
 	AssumeRoleExternalID  string
 	AssumeRoleSessionName string
 	AssumeRolePolicy      string
+	AssumeRole            []awsAssumeRoleConfig `cty:"assume_role"`
 
 	AllowedAccountIds   []string
 	ForbiddenAccountIds []string
@@ -36,6 +37,12 @@ type awsConfig struct {
 	S3ForcePathStyle        bool
 }
 
+type awsAssumeRoleConfig struct {
+	RoleARN     string `cty:"role_arn"`
+	ExternalID  string `cty:"external_id"`
+	SessionName string `cty:"session_name"`
+}
+
 type AWSTerraformProvider struct {
 	*terraform.TerraformProvider
 	session *session.Session
@@ -43,7 +50,16 @@ type AWSTerraformProvider struct {
 	version string
 }
 
-func NewAWSTerraformProvider(version string, progress output.Progress, configDir string) (*AWSTerraformProvider, error) {
+func NewAWSTerraformProvider(version string, progress output.Progress, configDir string, installation *tf.ProviderInstallation) (*AWSTerraformProvider, error) {
+	sess := session.Must(session.NewSessionWithOptions(session.Options{
+		SharedConfigState: session.SharedConfigEnable,
+	}))
+	return newAWSTerraformProvider(version, progress, configDir, installation, sess, nil)
+}
+
+// newAWSTerraformProvider returns a provider scanning the region of the session,
+// the terraform provider is configured with the credentials of the target when given
+func newAWSTerraformProvider(version string, progress output.Progress, configDir string, installation *tf.ProviderInstallation, sess *session.Session, target *Target) (*AWSTerraformProvider, error) {
 	if version == "" {
 		version = "3.19.0"
 	}
@@ -52,24 +68,34 @@ func NewAWSTerraformProvider(version string, progress output.Progress, configDir
 		name:    "aws",
 	}
 	installer, err := tf.NewProviderInstaller(tf.ProviderConfig{
-		Key:       p.name,
-		Version:   version,
-		ConfigDir: configDir,
+		Key:          p.name,
+		Version:      version,
+		ConfigDir:    configDir,
+		Installation: installation,
 	})
 	if err != nil {
 		return nil, err
 	}
-	p.session = session.Must(session.NewSessionWithOptions(session.Options{
-		SharedConfigState: session.SharedConfigEnable,
-	}))
+	p.session = sess
 	tfProvider, err := terraform.NewTerraformProvider(installer, terraform.TerraformProviderConfig{
 		Name:         p.name,
 		DefaultAlias: *p.session.Config.Region,
 		GetProviderConfig: func(alias string) interface{} {
-			return awsConfig{
+			config := awsConfig{
 				Region:     alias,
 				MaxRetries: 10, // TODO make this configurable
 			}
+			if target != nil {
+				config.Profile = target.Profile
+				if target.RoleARN != "" {
+					config.AssumeRole = []awsAssumeRoleConfig{{
+						RoleARN:     target.RoleARN,
+						ExternalID:  target.ExternalID,
+						SessionName: target.SessionName,
+					}}
+				}
+			}
+			return config
 		},
 	}, progress)
 	if err != nil {
@@ -0,0 +1,49 @@
+package aws
+
+import (
+	"github.com/cloudskiff/driftctl/pkg/remote/common"
+	"github.com/cloudskiff/driftctl/pkg/resource"
+	"github.com/cloudskiff/driftctl/pkg/resource/aws"
+)
+
+type enumeratorLibrary interface {
+	AddEnumerator(enumerator common.Enumerator)
+	AddDetailsFetcher(ty resource.ResourceType, detailsFetcher common.DetailsFetcher)
+}
+
+// scopedRemoteLibrary registers enumerators and details fetchers of a single account and region,
+// resources of global services are only registered when global is true
+type scopedRemoteLibrary struct {
+	library *common.RemoteLibrary
+	scope   resource.Scope
+	global  bool
+}
+
+func (l *scopedRemoteLibrary) AddEnumerator(enumerator common.Enumerator) {
+	scope, ok := l.scopeOf(enumerator.SupportedType())
+	if !ok {
+		return
+	}
+	l.library.AddEnumerator(common.NewScopedEnumerator(enumerator, scope))
+}
+
+func (l *scopedRemoteLibrary) AddDetailsFetcher(ty resource.ResourceType, detailsFetcher common.DetailsFetcher) {
+	scope, ok := l.scopeOf(ty)
+	if !ok {
+		return
+	}
+	fetcher, exists := l.library.GetDetailsFetcher(ty).(*common.ScopedDetailsFetcher)
+	if !exists {
+		fetcher = common.NewScopedDetailsFetcher()
+		l.library.AddDetailsFetcher(ty, fetcher)
+	}
+	fetcher.AddDetailsFetcher(scope, detailsFetcher)
+}
+
+// scopeOf returns the scope of resources of the given type, the region is left out for global services
+func (l *scopedRemoteLibrary) scopeOf(ty resource.ResourceType) (resource.Scope, bool) {
+	if !aws.IsGlobalResourceType(string(ty)) {
+		return l.scope, true
+	}
+	return resource.Scope{Account: l.scope.Account}, l.global
+}
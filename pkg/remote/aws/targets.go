@@ -0,0 +1,88 @@
+package aws
+
+import (
+	"io/ioutil"
+
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+)
+
+// Target is a set of credentials to scan in one or several regions, the role is assumed
+// with the credentials of the profile or of the environment when given
+type Target struct {
+	Profile     string   `json:"profile,omitempty"`
+	RoleARN     string   `json:"role_arn,omitempty"`
+	ExternalID  string   `json:"external_id,omitempty"`
+	SessionName string   `json:"session_name,omitempty"`
+	Regions     []string `json:"regions,omitempty"`
+}
+
+type targetsFile struct {
+	Targets []Target `json:"targets"`
+}
+
+// ReadTargets reads the list of accounts and regions to scan from a YAML file
+func ReadTargets(path string) ([]Target, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read AWS targets")
+	}
+	file := targetsFile{}
+	if err := yaml.Unmarshal(content, &file); err != nil {
+		return nil, errors.Wrapf(err, "unable to parse AWS targets %s", path)
+	}
+	if len(file.Targets) == 0 {
+		return nil, errors.Errorf("no target found in %s", path)
+	}
+	for i, target := range file.Targets {
+		if target.RoleARN == "" {
+			continue
+		}
+		if _, err := arn.Parse(target.RoleARN); err != nil {
+			return nil, errors.Errorf("invalid role_arn %s of target %d in %s", target.RoleARN, i+1, path)
+		}
+	}
+	return file.Targets, nil
+}
+
+// newSession returns a session using the credentials of the target in the given region,
+// the region of the profile or of the environment is used when empty
+func (t *Target) newSession(region string) (*session.Session, error) {
+	options := session.Options{
+		SharedConfigState: session.SharedConfigEnable,
+		Profile:           t.Profile,
+	}
+	if region != "" {
+		options.Config.Region = awssdk.String(region)
+	}
+	sess, err := session.NewSessionWithOptions(options)
+	if err != nil {
+		return nil, err
+	}
+	if t.RoleARN == "" {
+		return sess, nil
+	}
+	credentials := stscreds.NewCredentials(sess, t.RoleARN, func(p *stscreds.AssumeRoleProvider) {
+		if t.ExternalID != "" {
+			p.ExternalID = awssdk.String(t.ExternalID)
+		}
+		if t.SessionName != "" {
+			p.RoleSessionName = t.SessionName
+		}
+	})
+	return sess.Copy(&awssdk.Config{Credentials: credentials}), nil
+}
+
+// getAccountId returns the account the credentials of a session belong to
+func getAccountId(sess *session.Session) (string, error) {
+	identity, err := sts.New(sess).GetCallerIdentity(&sts.GetCallerIdentityInput{})
+	if err != nil {
+		return "", err
+	}
+	return *identity.Account, nil
+}
@@ -0,0 +1,113 @@
+package aws
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudskiff/driftctl/pkg/remote/common"
+	"github.com/cloudskiff/driftctl/pkg/resource"
+)
+
+func TestReadTargets(t *testing.T) {
+	cases := []struct {
+		name     string
+		path     string
+		expected []Target
+		err      string
+	}{
+		{
+			name: "valid targets",
+			path: "testdata/targets/valid.yml",
+			expected: []Target{
+				{Profile: "production", Regions: []string{"us-east-1", "eu-west-3"}},
+				{RoleARN: "arn:aws:iam::123456789012:role/driftctl", ExternalID: "driftctl", SessionName: "drift-report", Regions: []string{"us-east-1"}},
+				{Profile: "staging"},
+			},
+		},
+		{
+			name: "without targets",
+			path: "testdata/targets/empty.yml",
+			err:  "no target found in testdata/targets/empty.yml",
+		},
+		{
+			name: "with invalid role",
+			path: "testdata/targets/invalid_role.yml",
+			err:  "invalid role_arn driftctl of target 1 in testdata/targets/invalid_role.yml",
+		},
+		{
+			name: "missing file",
+			path: "testdata/targets/missing.yml",
+			err:  "unable to read AWS targets: open testdata/targets/missing.yml: no such file or directory",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			targets, err := ReadTargets(c.path)
+			if c.err != "" {
+				assert.EqualError(t, err, c.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, c.expected, targets)
+		})
+	}
+}
+
+type fakeDetailsFetcher struct {
+	name string
+}
+
+func (f *fakeDetailsFetcher) ReadDetails(res *resource.Resource) (*resource.Resource, error) {
+	if f.name == "" {
+		return nil, errors.New("unexpected details fetcher")
+	}
+	return &resource.Resource{Id: res.ResourceId(), Type: res.ResourceType(), Attrs: &resource.Attributes{"fetcher": f.name}}, nil
+}
+
+func TestScopedRemoteLibrary(t *testing.T) {
+	library := common.NewRemoteLibrary()
+	scopes := []resource.Scope{
+		{Account: "123456789012", Region: "us-east-1"},
+		{Account: "123456789012", Region: "eu-west-3"},
+	}
+	for i, scope := range scopes {
+		scoped := &scopedRemoteLibrary{library: library, scope: scope, global: i == 0}
+		for _, ty := range []string{"aws_sqs_queue", "aws_iam_user"} {
+			enumerator := &common.MockEnumerator{}
+			enumerator.On("SupportedType").Return(resource.ResourceType(ty))
+			enumerator.On("Enumerate").Return([]*resource.Resource{{Id: "driftctl", Type: ty}}, nil)
+			scoped.AddEnumerator(enumerator)
+			scoped.AddDetailsFetcher(resource.ResourceType(ty), &fakeDetailsFetcher{scope.Region})
+		}
+	}
+
+	// Global resources are only enumerated in the first region of the account
+	found := make([]*resource.Resource, 0)
+	for _, enumerator := range library.Enumerators() {
+		resources, err := enumerator.Enumerate()
+		assert.NoError(t, err)
+		found = append(found, resources...)
+	}
+	assert.Equal(t, []*resource.Resource{
+		{Id: "driftctl", Type: "aws_sqs_queue", Scope: &scopes[0]},
+		{Id: "driftctl", Type: "aws_iam_user", Scope: &resource.Scope{Account: "123456789012"}},
+		{Id: "driftctl", Type: "aws_sqs_queue", Scope: &scopes[1]},
+	}, found)
+
+	for _, res := range found {
+		details, err := library.GetDetailsFetcher(resource.ResourceType(res.ResourceType())).ReadDetails(res)
+		assert.NoError(t, err)
+		assert.Equal(t, res.Scope, details.Scope)
+		fetcher, _ := details.Attrs.Get("fetcher")
+		if res.ResourceType() == "aws_iam_user" {
+			assert.Equal(t, "us-east-1", fetcher)
+			continue
+		}
+		assert.Equal(t, res.Scope.Region, fetcher)
+	}
+
+	_, err := library.GetDetailsFetcher("aws_sqs_queue").ReadDetails(&resource.Resource{Id: "driftctl", Type: "aws_sqs_queue"})
+	assert.EqualError(t, err, "no details fetcher for aws_sqs_queue resources of scope ''")
+}
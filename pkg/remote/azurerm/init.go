@@ -21,9 +21,10 @@ func Init(
 	progress output.Progress,
 	resourceSchemaRepository *resource.SchemaRepository,
 	factory resource.ResourceFactory,
-	configDir string) error {
+	configDir string,
+	installation *terraform.ProviderInstallation) error {
 
-	provider, err := NewAzureTerraformProvider(version, progress, configDir)
+	provider, err := NewAzureTerraformProvider(version, progress, configDir, installation)
 	if err != nil {
 		return err
 	}
@@ -15,7 +15,7 @@ type AzureTerraformProvider struct {
 	version string
 }
 
-func NewAzureTerraformProvider(version string, progress output.Progress, configDir string) (*AzureTerraformProvider, error) {
+func NewAzureTerraformProvider(version string, progress output.Progress, configDir string, installation *tf.ProviderInstallation) (*AzureTerraformProvider, error) {
 	if version == "" {
 		version = "2.71.0"
 	}
@@ -26,9 +26,10 @@ func NewAzureTerraformProvider(version string, progress output.Progress, configD
 	}
 	// Use TerraformProviderInstaller to retrieve the provider if needed
 	installer, err := tf.NewProviderInstaller(tf.ProviderConfig{
-		Key:       p.name,
-		Version:   version,
-		ConfigDir: configDir,
+		Key:          p.name,
+		Version:      version,
+		ConfigDir:    configDir,
+		Installation: installation,
 	})
 	if err != nil {
 		return nil, err
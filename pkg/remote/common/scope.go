@@ -0,0 +1,56 @@
+package common
+
+import (
+	"github.com/cloudskiff/driftctl/pkg/resource"
+	"github.com/pkg/errors"
+)
+
+// ScopedEnumerator tags enumerated resources with the account and region they were found in
+type ScopedEnumerator struct {
+	Enumerator
+	scope resource.Scope
+}
+
+func NewScopedEnumerator(enumerator Enumerator, scope resource.Scope) *ScopedEnumerator {
+	return &ScopedEnumerator{enumerator, scope}
+}
+
+func (e *ScopedEnumerator) Enumerate() ([]*resource.Resource, error) {
+	resources, err := e.Enumerator.Enumerate()
+	for _, res := range resources {
+		if res != nil {
+			scope := e.scope
+			res.Scope = &scope
+		}
+	}
+	return resources, err
+}
+
+// ScopedDetailsFetcher reads details of a resource with the fetcher of the scope it was enumerated in
+type ScopedDetailsFetcher struct {
+	fetchers map[resource.Scope]DetailsFetcher
+}
+
+func NewScopedDetailsFetcher() *ScopedDetailsFetcher {
+	return &ScopedDetailsFetcher{make(map[resource.Scope]DetailsFetcher)}
+}
+
+func (f *ScopedDetailsFetcher) AddDetailsFetcher(scope resource.Scope, detailsFetcher DetailsFetcher) {
+	f.fetchers[scope] = detailsFetcher
+}
+
+func (f *ScopedDetailsFetcher) ReadDetails(res *resource.Resource) (*resource.Resource, error) {
+	scope := resource.Scope{}
+	if res.Scope != nil {
+		scope = *res.Scope
+	}
+	fetcher, exists := f.fetchers[scope]
+	if !exists {
+		return nil, errors.Errorf("no details fetcher for %s resources of scope '%s'", res.ResourceType(), scope.String())
+	}
+	details, err := fetcher.ReadDetails(res)
+	if details != nil {
+		details.Scope = res.Scope
+	}
+	return details, err
+}
@@ -21,9 +21,10 @@ func Init(version string, alerter *alerter.Alerter,
 	progress output.Progress,
 	resourceSchemaRepository *resource.SchemaRepository,
 	factory resource.ResourceFactory,
-	configDir string) error {
+	configDir string,
+	installation *terraform.ProviderInstallation) error {
 
-	provider, err := NewGithubTerraformProvider(version, progress, configDir)
+	provider, err := NewGithubTerraformProvider(version, progress, configDir, installation)
 	if err != nil {
 		return err
 	}
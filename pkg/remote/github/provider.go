@@ -20,7 +20,7 @@ type githubConfig struct {
 	Organization string
 }
 
-func NewGithubTerraformProvider(version string, progress output.Progress, configDir string) (*GithubTerraformProvider, error) {
+func NewGithubTerraformProvider(version string, progress output.Progress, configDir string, installation *tf.ProviderInstallation) (*GithubTerraformProvider, error) {
 	if version == "" {
 		version = "4.4.0"
 	}
@@ -29,9 +29,10 @@ func NewGithubTerraformProvider(version string, progress output.Progress, config
 		name:    "github",
 	}
 	installer, err := tf.NewProviderInstaller(tf.ProviderConfig{
-		Key:       p.name,
-		Version:   version,
-		ConfigDir: configDir,
+		Key:          p.name,
+		Version:      version,
+		ConfigDir:    configDir,
+		Installation: installation,
 	})
 	if err != nil {
 		return nil, err
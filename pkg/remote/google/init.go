@@ -22,9 +22,10 @@ func Init(version string, alerter *alerter.Alerter,
 	progress output.Progress,
 	resourceSchemaRepository *resource.SchemaRepository,
 	factory resource.ResourceFactory,
-	configDir string) error {
+	configDir string,
+	installation *terraform.ProviderInstallation) error {
 
-	provider, err := NewGCPTerraformProvider(version, progress, configDir)
+	provider, err := NewGCPTerraformProvider(version, progress, configDir, installation)
 	if err != nil {
 		return err
 	}
@@ -15,7 +15,7 @@ type GCPTerraformProvider struct {
 	version string
 }
 
-func NewGCPTerraformProvider(version string, progress output.Progress, configDir string) (*GCPTerraformProvider, error) {
+func NewGCPTerraformProvider(version string, progress output.Progress, configDir string, installation *tf.ProviderInstallation) (*GCPTerraformProvider, error) {
 	if version == "" {
 		version = "3.78.0"
 	}
@@ -24,9 +24,10 @@ func NewGCPTerraformProvider(version string, progress output.Progress, configDir
 		name:    tf.GOOGLE,
 	}
 	installer, err := tf.NewProviderInstaller(tf.ProviderConfig{
-		Key:       p.name,
-		Version:   version,
-		ConfigDir: configDir,
+		Key:          p.name,
+		Version:      version,
+		ConfigDir:    configDir,
+		Installation: installation,
 	})
 	if err != nil {
 		return nil, err
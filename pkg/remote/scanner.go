@@ -14,6 +14,8 @@ import (
 
 type ScannerOptions struct {
 	Deep bool
+	// ReportIgnored enumerates the types ignored by the filter, so the analysis can count their resources
+	ReportIgnored bool
 }
 
 type Scanner struct {
@@ -60,7 +62,8 @@ loop:
 
 func (s *Scanner) scan() ([]*resource.Resource, error) {
 	for _, enumerator := range s.remoteLibrary.Enumerators() {
-		if s.filter.IsTypeIgnored(enumerator.SupportedType()) {
+		ignored := s.filter.IsTypeIgnored(enumerator.SupportedType())
+		if ignored && !s.options.ReportIgnored {
 			logrus.WithFields(logrus.Fields{
 				"type": enumerator.SupportedType(),
 			}).Debug("Ignored enumeration of resources since it is ignored in filter")
@@ -69,6 +72,13 @@ func (s *Scanner) scan() ([]*resource.Resource, error) {
 		enumerator := enumerator
 		s.enumeratorRunner.Run(func() (interface{}, error) {
 			resources, err := enumerator.Enumerate()
+			if err != nil && ignored {
+				// Resources of ignored types are only counted, failing to list them must not raise alerts
+				logrus.WithFields(logrus.Fields{
+					"type": enumerator.SupportedType(),
+				}).Debugf("Unable to enumerate resources of ignored type: %s", err)
+				return []*resource.Resource{}, nil
+			}
 			if err != nil {
 				err := HandleResourceEnumerationError(err, s.alerter)
 				if err == nil {
@@ -102,7 +112,8 @@ func (s *Scanner) scan() ([]*resource.Resource, error) {
 		res := res
 		s.detailsFetcherRunner.Run(func() (interface{}, error) {
 			fetcher := s.remoteLibrary.GetDetailsFetcher(resource.ResourceType(res.ResourceType()))
-			if fetcher == nil {
+			// Details of ignored resources are not compared
+			if fetcher == nil || (s.options.ReportIgnored && s.filter.IsTypeIgnored(resource.ResourceType(res.ResourceType()))) {
 				return []*resource.Resource{res}, nil
 			}
 
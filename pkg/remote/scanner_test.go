@@ -7,7 +7,9 @@ import (
 	"github.com/cloudskiff/driftctl/pkg/filter"
 	"github.com/cloudskiff/driftctl/pkg/remote/common"
 	"github.com/cloudskiff/driftctl/pkg/resource"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestScannerShouldIgnoreType(t *testing.T) {
@@ -29,3 +31,28 @@ func TestScannerShouldIgnoreType(t *testing.T) {
 	assert.Nil(t, err)
 	fakeEnumerator.AssertExpectations(t)
 }
+
+func TestScannerShouldEnumerateIgnoredTypeWhenReportingIgnored(t *testing.T) {
+	alerter := alerter.NewAlerter()
+	ignoredEnumerator := &common.MockEnumerator{}
+	ignoredEnumerator.On("SupportedType").Return(resource.ResourceType("FakeType"))
+	ignoredEnumerator.On("Enumerate").Return([]*resource.Resource{{Id: "fake", Type: "FakeType"}}, nil)
+	failingEnumerator := &common.MockEnumerator{}
+	failingEnumerator.On("SupportedType").Return(resource.ResourceType("OtherFakeType"))
+	failingEnumerator.On("Enumerate").Return(nil, errors.New("access denied"))
+
+	remoteLibrary := common.NewRemoteLibrary()
+	remoteLibrary.AddEnumerator(ignoredEnumerator)
+	remoteLibrary.AddEnumerator(failingEnumerator)
+
+	testFilter := &filter.MockFilter{}
+	testFilter.On("IsTypeIgnored", mock.Anything).Return(true)
+
+	s := NewScanner(remoteLibrary, alerter, ScannerOptions{ReportIgnored: true}, testFilter)
+	resources, err := s.Resources()
+	assert.Nil(t, err)
+	assert.Equal(t, []*resource.Resource{{Id: "fake", Type: "FakeType"}}, resources)
+	assert.Empty(t, alerter.Retrieve())
+	ignoredEnumerator.AssertExpectations(t)
+	failingEnumerator.AssertExpectations(t)
+}
@@ -0,0 +1,73 @@
+package remote
+
+import (
+	"github.com/hashicorp/terraform/plugin/discovery"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/cloudskiff/driftctl/pkg/resource"
+	"github.com/cloudskiff/driftctl/pkg/resource/aws"
+	"github.com/cloudskiff/driftctl/pkg/resource/azurerm"
+	"github.com/cloudskiff/driftctl/pkg/resource/github"
+	"github.com/cloudskiff/driftctl/pkg/resource/google"
+	"github.com/cloudskiff/driftctl/pkg/terraform"
+)
+
+/**
+ * Initialize the schema repository from the terraform provider schemas without configuring it,
+ * no credentials are required since the provider is not used to read resources
+ */
+
+func InitSchemaRepository(providerName, providerVersion, configDir string, installation *terraform.ProviderInstallation, repo *resource.SchemaRepository) error {
+	var initMetadata func(resource.SchemaRepositoryInterface)
+	switch providerName {
+	case terraform.AWS:
+		initMetadata = aws.InitResourcesMetadata
+	case terraform.GITHUB:
+		initMetadata = github.InitResourcesMetadata
+	case terraform.GOOGLE:
+		initMetadata = google.InitResourcesMetadata
+	case terraform.AZURE:
+		initMetadata = azurerm.InitResourcesMetadata
+	default:
+		return errors.Errorf("unsupported provider '%s'", providerName)
+	}
+
+	installer, err := terraform.NewProviderInstaller(terraform.ProviderConfig{
+		Key:          providerName,
+		Version:      providerVersion,
+		ConfigDir:    configDir,
+		Installation: installation,
+	})
+	if err != nil {
+		return err
+	}
+	providerPath, err := installer.Install()
+	if err != nil {
+		return err
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"provider": providerName,
+		"version":  providerVersion,
+	}).Debug("Starting gRPC client to read provider schema")
+	provider, err := terraform.NewGRPCProvider(discovery.PluginMeta{
+		Path: providerPath,
+	})
+	if err != nil {
+		return err
+	}
+	defer provider.Close()
+
+	schema := provider.GetSchema()
+	if schema.Diagnostics.HasErrors() {
+		return errors.Wrap(schema.Diagnostics.Err(), "unable to read provider schema")
+	}
+
+	if err := repo.Init(providerName, providerVersion, schema.ResourceTypes); err != nil {
+		return err
+	}
+	initMetadata(repo)
+
+	return nil
+}
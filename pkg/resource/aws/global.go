@@ -0,0 +1,16 @@
+package aws
+
+import "strings"
+
+// globalResourceTypePrefixes are the services whose resources do not belong to a region
+var globalResourceTypePrefixes = []string{"aws_iam_", "aws_route53_", "aws_cloudfront_"}
+
+// IsGlobalResourceType returns true for resources located in an account only, not in one of its regions
+func IsGlobalResourceType(ty string) bool {
+	for _, prefix := range globalResourceTypePrefixes {
+		if strings.HasPrefix(ty, prefix) {
+			return true
+		}
+	}
+	return false
+}
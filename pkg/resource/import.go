@@ -0,0 +1,132 @@
+package resource
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// importIdFunc converts a resource to the id expected by terraform import
+type importIdFunc struct {
+	// attrs are the attributes read by fn, they are kept in scan results
+	// of unmanaged resources even without deep mode
+	attrs []string
+	fn    func(res *Resource) (string, error)
+}
+
+// importIdFuncs lists the types where the import id differs from the resource id
+var importIdFuncs = map[string]importIdFunc{
+	"aws_api_gateway_gateway_response":     {fn: splitIdImportFunc("aggr", 2)},
+	"aws_api_gateway_integration":          {fn: splitIdImportFunc("agi", 3)},
+	"aws_api_gateway_integration_response": {fn: splitIdImportFunc("agir", 4)},
+	"aws_api_gateway_method":               {fn: splitIdImportFunc("agm", 3)},
+	"aws_api_gateway_method_response":      {fn: splitIdImportFunc("agmr", 4)},
+	"aws_api_gateway_stage":                {fn: splitIdImportFunc("ags", 2)},
+	"aws_api_gateway_resource": {
+		attrs: []string{"rest_api_id"},
+		fn: func(res *Resource) (string, error) {
+			return joinAttributesImportId(res, "/", "rest_api_id", "#id")
+		},
+	},
+	"aws_iam_role_policy_attachment": {
+		attrs: []string{"role", "policy_arn"},
+		fn: func(res *Resource) (string, error) {
+			return joinAttributesImportId(res, "/", "role", "policy_arn")
+		},
+	},
+	"aws_iam_user_policy_attachment": {
+		attrs: []string{"user", "policy_arn"},
+		fn: func(res *Resource) (string, error) {
+			return joinAttributesImportId(res, "/", "user", "policy_arn")
+		},
+	},
+	"aws_route": {
+		attrs: []string{"route_table_id", "destination_cidr_block", "destination_ipv6_cidr_block", "destination_prefix_list_id"},
+		fn: func(res *Resource) (string, error) {
+			for _, destination := range []string{"destination_cidr_block", "destination_ipv6_cidr_block", "destination_prefix_list_id"} {
+				if v := attributeString(res, destination); v != "" {
+					return joinAttributesImportId(res, "_", "route_table_id", destination)
+				}
+			}
+			return "", errors.Errorf("missing route destination attribute")
+		},
+	},
+	"aws_route_table_association": {
+		attrs: []string{"route_table_id", "subnet_id", "gateway_id"},
+		fn: func(res *Resource) (string, error) {
+			if v := attributeString(res, "gateway_id"); v != "" {
+				return joinAttributesImportId(res, "/", "gateway_id", "route_table_id")
+			}
+			return joinAttributesImportId(res, "/", "subnet_id", "route_table_id")
+		},
+	},
+}
+
+// ImportAttributes returns the attributes of the resource needed to compute
+// its import id, or nil when the import id does not depend on attributes
+func ImportAttributes(res *Resource) *Attributes {
+	importId, exist := importIdFuncs[res.ResourceType()]
+	if !exist || res.Attributes() == nil {
+		return nil
+	}
+	attrs := Attributes{}
+	for _, field := range importId.attrs {
+		if v, exist := res.Attributes().Get(field); exist && v != nil {
+			attrs[field] = v
+		}
+	}
+	if len(attrs) == 0 {
+		return nil
+	}
+	return &attrs
+}
+
+// ImportId returns the id to use to import the resource in terraform
+func ImportId(res *Resource) (string, error) {
+	importId, exist := importIdFuncs[res.ResourceType()]
+	if !exist {
+		return res.ResourceId(), nil
+	}
+	return importId.fn(res)
+}
+
+// splitIdImportFunc handles ids built as "<prefix>-<part1>-...-<partN>" and
+// converts them to "<part1>/.../<partN>", the last part may contain dashes
+func splitIdImportFunc(prefix string, parts int) func(res *Resource) (string, error) {
+	return func(res *Resource) (string, error) {
+		split := strings.SplitN(res.ResourceId(), "-", parts+1)
+		if len(split) != parts+1 || split[0] != prefix {
+			return "", errors.Errorf("unexpected id format, expected %s-<%d parts>", prefix, parts)
+		}
+		return strings.Join(split[1:], "/"), nil
+	}
+}
+
+// joinAttributesImportId joins the given attributes values, "#id" refers to the resource id
+func joinAttributesImportId(res *Resource, sep string, fields ...string) (string, error) {
+	values := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if field == "#id" {
+			values = append(values, res.ResourceId())
+			continue
+		}
+		v := attributeString(res, field)
+		if v == "" {
+			return "", errors.Errorf("missing attribute %s", field)
+		}
+		values = append(values, v)
+	}
+	return strings.Join(values, sep), nil
+}
+
+func attributeString(res *Resource, field string) string {
+	if res.Attributes() == nil {
+		return ""
+	}
+	v, exist := res.Attributes().Get(field)
+	if !exist || v == nil {
+		return ""
+	}
+	return fmt.Sprintf("%v", v)
+}
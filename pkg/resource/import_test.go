@@ -0,0 +1,123 @@
+package resource
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImportId(t *testing.T) {
+	cases := []struct {
+		name     string
+		res      *Resource
+		expected string
+		err      string
+	}{
+		{
+			name:     "default to resource id",
+			res:      &Resource{Id: "my-bucket", Type: "aws_s3_bucket"},
+			expected: "my-bucket",
+		},
+		{
+			name:     "id based import id",
+			res:      &Resource{Id: "agm-foo-bar-GET", Type: "aws_api_gateway_method"},
+			expected: "foo/bar/GET",
+		},
+		{
+			name:     "id based import id with dashes in last part",
+			res:      &Resource{Id: "ags-foo-my-stage", Type: "aws_api_gateway_stage"},
+			expected: "foo/my-stage",
+		},
+		{
+			name: "id based import id with unexpected id",
+			res:  &Resource{Id: "foo-bar", Type: "aws_api_gateway_method"},
+			err:  "unexpected id format, expected agm-<3 parts>",
+		},
+		{
+			name: "attributes based import id",
+			res: &Resource{
+				Id:    "policy-role",
+				Type:  "aws_iam_role_policy_attachment",
+				Attrs: &Attributes{"role": "role", "policy_arn": "arn:aws:iam::aws:policy/ReadOnlyAccess"},
+			},
+			expected: "role/arn:aws:iam::aws:policy/ReadOnlyAccess",
+		},
+		{
+			name: "attributes based import id with resource id",
+			res: &Resource{
+				Id:    "abcdef",
+				Type:  "aws_api_gateway_resource",
+				Attrs: &Attributes{"rest_api_id": "foo"},
+			},
+			expected: "foo/abcdef",
+		},
+		{
+			name: "attributes based import id without attributes",
+			res:  &Resource{Id: "policy-role", Type: "aws_iam_role_policy_attachment"},
+			err:  "missing attribute role",
+		},
+		{
+			name: "route import id",
+			res: &Resource{
+				Id:    "r-rtb-123",
+				Type:  "aws_route",
+				Attrs: &Attributes{"route_table_id": "rtb-123", "destination_ipv6_cidr_block": "::/0"},
+			},
+			expected: "rtb-123_::/0",
+		},
+		{
+			name: "route import id without destination",
+			res: &Resource{
+				Id:    "r-rtb-123",
+				Type:  "aws_route",
+				Attrs: &Attributes{"route_table_id": "rtb-123"},
+			},
+			err: "missing route destination attribute",
+		},
+		{
+			name: "gateway route table association import id",
+			res: &Resource{
+				Id:    "rtbassoc-123",
+				Type:  "aws_route_table_association",
+				Attrs: &Attributes{"route_table_id": "rtb-123", "gateway_id": "igw-123"},
+			},
+			expected: "igw-123/rtb-123",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			id, err := ImportId(c.res)
+			if c.err != "" {
+				assert.EqualError(t, err, c.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, c.expected, id)
+		})
+	}
+}
+
+func TestImportIdFuncs_Attributes(t *testing.T) {
+	for ty, importId := range importIdFuncs {
+		ty, importId := ty, importId
+		t.Run(ty, func(t *testing.T) {
+			_, err := importId.fn(&Resource{Id: "id", Type: ty, Attrs: &Attributes{}})
+			if len(importId.attrs) == 0 {
+				if err != nil {
+					assert.NotContains(t, err.Error(), "attribute", "attributes read to compute the import id are not listed")
+				}
+				return
+			}
+
+			// The listed attributes are enough to compute the import id
+			attrs := Attributes{}
+			for _, attr := range importId.attrs {
+				attrs[attr] = "value"
+			}
+			res := &Resource{Id: "id", Type: ty, Attrs: &attrs}
+			_, err = ImportId(&Resource{Id: "id", Type: ty, Attrs: ImportAttributes(res)})
+			assert.NoError(t, err)
+		})
+	}
+}
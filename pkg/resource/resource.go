@@ -17,19 +17,40 @@ type Source interface {
 }
 
 type SerializableSource struct {
-	S    string `json:"source"`
-	Ns   string `json:"namespace"`
-	Name string `json:"internal_name"`
+	S         string `json:"source"`
+	Ns        string `json:"namespace"`
+	Name      string `json:"internal_name"`
+	Workspace string `json:"workspace,omitempty"`
+}
+
+func (s *SerializableSource) Source() string {
+	return s.S
+}
+
+func (s *SerializableSource) Namespace() string {
+	return s.Ns
+}
+
+func (s *SerializableSource) InternalName() string {
+	return s.Name
 }
 
 type TerraformStateSource struct {
 	State  string
 	Module string
 	Name   string
+	// Workspace is only known when states of every workspace are read
+	Workspace string
+	// InstanceKey is the count or for_each key of the resource instance, e.g. [0] or ["key"]
+	InstanceKey string
 }
 
 func NewTerraformStateSource(state, module, name string) *TerraformStateSource {
-	return &TerraformStateSource{state, module, name}
+	return &TerraformStateSource{State: state, Module: module, Name: name}
+}
+
+func NewTerraformStateWorkspaceSource(state, workspace, module, name string) *TerraformStateSource {
+	return &TerraformStateSource{State: state, Module: module, Name: name, Workspace: workspace}
 }
 
 func (s *TerraformStateSource) Source() string {
@@ -44,12 +65,127 @@ func (s *TerraformStateSource) InternalName() string {
 	return s.Name
 }
 
+// TerraformConfigSource is the source of resources declared in terraform configuration files or plans,
+// those resources may not have been applied yet
+type TerraformConfigSource struct {
+	Path   string
+	Module string
+	Name   string
+	// InstanceKey is only known for resources read from plans, configuration
+	// files declare every instance of a resource at once
+	InstanceKey string
+}
+
+func NewTerraformConfigSource(path, module, name string) *TerraformConfigSource {
+	return &TerraformConfigSource{Path: path, Module: module, Name: name}
+}
+
+func (s *TerraformConfigSource) Source() string {
+	return s.Path
+}
+
+func (s *TerraformConfigSource) Namespace() string {
+	return s.Module
+}
+
+func (s *TerraformConfigSource) InternalName() string {
+	return s.Name
+}
+
+// PulumiStackSource is the source of resources read from a pulumi stack export
+type PulumiStackSource struct {
+	Path  string
+	Stack string
+	Name  string
+}
+
+func NewPulumiStackSource(path, stack, name string) *PulumiStackSource {
+	return &PulumiStackSource{path, stack, name}
+}
+
+func (s *PulumiStackSource) Source() string {
+	return s.Path
+}
+
+func (s *PulumiStackSource) Namespace() string {
+	return s.Stack
+}
+
+func (s *PulumiStackSource) InternalName() string {
+	return s.Name
+}
+
+// CloudformationStackSource is the source of resources read from a cloudformation stack resources listing,
+// the internal name is the logical id of the resource in the template
+type CloudformationStackSource struct {
+	Path      string
+	Stack     string
+	LogicalId string
+}
+
+func NewCloudformationStackSource(path, stack, logicalId string) *CloudformationStackSource {
+	return &CloudformationStackSource{path, stack, logicalId}
+}
+
+func (s *CloudformationStackSource) Source() string {
+	return s.Path
+}
+
+func (s *CloudformationStackSource) Namespace() string {
+	return s.Stack
+}
+
+func (s *CloudformationStackSource) InternalName() string {
+	return s.LogicalId
+}
+
+// Scope locates a cloud resource when several accounts or regions are scanned at once,
+// an empty field is unknown and matches any value
+type Scope struct {
+	Account string `json:"account,omitempty"`
+	Region  string `json:"region,omitempty"`
+	// Unknown is set on resources that could not be located, they do not match any scoped resource
+	Unknown bool `json:"unknown,omitempty"`
+}
+
+// Matches returns false when both scopes know the account or the region and they differ,
+// or when one of them is unknown
+func (s *Scope) Matches(other *Scope) bool {
+	if s == nil || other == nil {
+		return true
+	}
+	if s.Unknown || other.Unknown {
+		return false
+	}
+	if s.Account != "" && other.Account != "" && s.Account != other.Account {
+		return false
+	}
+	if s.Region != "" && other.Region != "" && s.Region != other.Region {
+		return false
+	}
+	return true
+}
+
+func (s *Scope) String() string {
+	if s.Unknown {
+		return "unknown"
+	}
+	if s.Region == "" {
+		return s.Account
+	}
+	if s.Account == "" {
+		return s.Region
+	}
+	return fmt.Sprintf("%s/%s", s.Account, s.Region)
+}
+
 type Resource struct {
 	Id     string
 	Type   string
 	Attrs  *Attributes
 	Sch    *Schema `json:"-" diff:"-"`
 	Source Source  `json:"-"`
+	Scope  *Scope  `json:"-" diff:"-"`
 }
 
 func (r *Resource) Schema() *Schema {
@@ -87,6 +223,10 @@ func (r *Resource) Equal(res *Resource) bool {
 		return false
 	}
 
+	if !r.Scope.Matches(res.Scope) {
+		return false
+	}
+
 	if r.Schema() != nil && r.Schema().DiscriminantFunc != nil {
 		return r.Schema().DiscriminantFunc(r, res)
 	}
@@ -102,6 +242,9 @@ type SerializableResource struct {
 	Id     string              `json:"id"`
 	Type   string              `json:"type"`
 	Source *SerializableSource `json:"source,omitempty"`
+	Scope  *Scope              `json:"scope,omitempty"`
+	// Attributes are only serialized for resources not covered by IaC in deep mode
+	Attributes *Attributes `json:"attributes,omitempty"`
 }
 
 func NewSerializableResource(res *Resource) *SerializableResource {
@@ -112,12 +255,24 @@ func NewSerializableResource(res *Resource) *SerializableResource {
 			Ns:   res.Src().Namespace(),
 			Name: res.Src().InternalName(),
 		}
+		if stateSource, ok := res.Src().(*TerraformStateSource); ok {
+			src.Workspace = stateSource.Workspace
+		}
 	}
 	return &SerializableResource{
 		Id:     res.ResourceId(),
 		Type:   res.ResourceType(),
 		Source: src,
+		Scope:  res.Scope,
+	}
+}
+
+// Src returns the source of a resource read back from a scan result, nil when it has none
+func (r *SerializableResource) Src() Source {
+	if r.Source == nil {
+		return nil
 	}
+	return r.Source
 }
 
 type NormalizedResource interface {
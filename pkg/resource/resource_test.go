@@ -73,3 +73,77 @@ func TestSanitizeDefaults(t *testing.T) {
 		})
 	}
 }
+
+func TestNewSerializableResource(t *testing.T) {
+	cases := []struct {
+		name     string
+		input    *Resource
+		expected *SerializableResource
+	}{
+		{
+			name:     "without source",
+			input:    &Resource{Id: "id", Type: "type"},
+			expected: &SerializableResource{Id: "id", Type: "type"},
+		},
+		{
+			name: "with state source",
+			input: &Resource{Id: "id", Type: "type", Source: NewTerraformStateSource(
+				"tfstate://terraform.tfstate", "module", "name",
+			)},
+			expected: &SerializableResource{Id: "id", Type: "type", Source: &SerializableSource{
+				S:    "tfstate://terraform.tfstate",
+				Ns:   "module",
+				Name: "name",
+			}},
+		},
+		{
+			name: "with workspace state source",
+			input: &Resource{Id: "id", Type: "type", Source: NewTerraformStateWorkspaceSource(
+				"tfstate://terraform.tfstate.d/prod/terraform.tfstate", "prod", "module", "name",
+			)},
+			expected: &SerializableResource{Id: "id", Type: "type", Source: &SerializableSource{
+				S:         "tfstate://terraform.tfstate.d/prod/terraform.tfstate",
+				Ns:        "module",
+				Name:      "name",
+				Workspace: "prod",
+			}},
+		},
+		{
+			name:     "with scope",
+			input:    &Resource{Id: "id", Type: "type", Scope: &Scope{Account: "123456789012", Region: "us-east-1"}},
+			expected: &SerializableResource{Id: "id", Type: "type", Scope: &Scope{Account: "123456789012", Region: "us-east-1"}},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert.Equal(t, c.expected, NewSerializableResource(c.input))
+		})
+	}
+}
+
+func TestResource_EqualScope(t *testing.T) {
+	cases := []struct {
+		name     string
+		scope    *Scope
+		other    *Scope
+		expected bool
+	}{
+		{name: "without scopes", expected: true},
+		{name: "with a single scope", scope: &Scope{Account: "123456789012", Region: "us-east-1"}, expected: true},
+		{name: "with same scopes", scope: &Scope{Account: "123456789012", Region: "us-east-1"}, other: &Scope{Account: "123456789012", Region: "us-east-1"}, expected: true},
+		{name: "with different accounts", scope: &Scope{Account: "123456789012", Region: "us-east-1"}, other: &Scope{Account: "210987654321", Region: "us-east-1"}, expected: false},
+		{name: "with different regions", scope: &Scope{Account: "123456789012", Region: "us-east-1"}, other: &Scope{Account: "123456789012", Region: "eu-west-3"}, expected: false},
+		{name: "with unknown region", scope: &Scope{Account: "123456789012", Region: "us-east-1"}, other: &Scope{Account: "123456789012"}, expected: true},
+		{name: "with unknown account", scope: &Scope{Account: "123456789012", Region: "us-east-1"}, other: &Scope{Region: "us-east-1"}, expected: true},
+		{name: "with unknown scope", scope: &Scope{Account: "123456789012", Region: "us-east-1"}, other: &Scope{Unknown: true}, expected: false},
+		{name: "with unknown scopes", scope: &Scope{Unknown: true}, other: &Scope{Unknown: true}, expected: false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			res := &Resource{Id: "id", Type: "type", Scope: c.scope}
+			other := &Resource{Id: "id", Type: "type", Scope: c.other}
+			assert.Equal(t, c.expected, res.Equal(other))
+			assert.Equal(t, c.expected, other.Equal(res))
+		})
+	}
+}
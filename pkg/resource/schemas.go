@@ -29,6 +29,7 @@ func (f *Flags) AddFlag(flag Flags) {
 }
 
 type Schema struct {
+	ProviderName                string
 	ProviderVersion             *version.Version
 	Flags                       Flags
 	SchemaVersion               int64
@@ -66,14 +67,15 @@ type SchemaRepositoryInterface interface {
 }
 
 type SchemaRepository struct {
-	schemas         map[string]*Schema
-	ProviderName    string
-	ProviderVersion *version.Version
+	schemas map[string]*Schema
+	// Providers maps the name of each initialized provider to its version
+	Providers map[string]*version.Version
 }
 
 func NewSchemaRepository() *SchemaRepository {
 	return &SchemaRepository{
-		schemas: make(map[string]*Schema),
+		schemas:   make(map[string]*Schema),
+		Providers: make(map[string]*version.Version),
 	}
 }
 
@@ -103,8 +105,7 @@ func (r *SchemaRepository) Init(providerName, providerVersion string, schema map
 	if err != nil {
 		return err
 	}
-	r.ProviderVersion = v
-	r.ProviderName = providerName
+	r.Providers[providerName] = v
 	for typ, sch := range schema {
 		attributeMetas := map[string]AttributeSchema{}
 		for s, attribute := range sch.Block.Attributes {
@@ -116,7 +117,8 @@ func (r *SchemaRepository) Init(providerName, providerVersion string, schema map
 		r.fetchNestedBlocks("", attributeMetas, sch.Block.BlockTypes)
 
 		r.schemas[typ] = &Schema{
-			ProviderVersion: r.ProviderVersion,
+			ProviderName:    providerName,
+			ProviderVersion: v,
 			SchemaVersion:   sch.Version,
 			Attributes:      attributeMetas,
 		}
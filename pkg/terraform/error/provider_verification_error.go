@@ -0,0 +1,14 @@
+package error
+
+import "fmt"
+
+// ProviderVerificationError is returned when a provider package does not match its checksums or signature
+type ProviderVerificationError struct {
+	Provider string
+	Version  string
+	Reason   string
+}
+
+func (p ProviderVerificationError) Error() string {
+	return fmt.Sprintf("Provider %s version %s could not be verified: %s", p.Provider, p.Version, p.Reason)
+}
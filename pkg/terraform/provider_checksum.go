@@ -0,0 +1,197 @@
+package terraform
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	error2 "github.com/cloudskiff/driftctl/pkg/terraform/error"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/crypto/openpgp" //nolint:staticcheck
+	"golang.org/x/mod/sumdb/dirhash"
+)
+
+const (
+	// hashSchemeZip is the sha256 of a provider archive
+	hashSchemeZip = "zh:"
+	// hashSchemeV1 is the dirhash of the content of a provider package
+	hashSchemeV1 = "h1:"
+
+	knownChecksumsMismatch = "checksum does not match any of the checksums of the lock file or mirror"
+)
+
+// verifyArchive checks a provider archive matches one of the given checksums, nothing is checked without checksums
+func (p *ProviderInstaller) verifyArchive(archive string, hashes []string) error {
+	if len(hashes) == 0 {
+		return nil
+	}
+	var zh, h1 string
+	for _, hash := range hashes {
+		var err error
+		switch {
+		case strings.HasPrefix(hash, hashSchemeZip):
+			if zh == "" {
+				zh, err = hashArchive(archive)
+			}
+			if hash == zh {
+				return nil
+			}
+		case strings.HasPrefix(hash, hashSchemeV1):
+			if h1 == "" {
+				h1, err = dirhash.HashZip(archive, dirhash.Hash1)
+			}
+			if hash == h1 {
+				return nil
+			}
+		}
+		if err != nil {
+			return errors.Wrapf(err, "unable to compute checksum of %s", archive)
+		}
+	}
+	return p.checksumMismatch(hashes, []string{zh, h1}, knownChecksumsMismatch)
+}
+
+// verifyDirectory checks an unpacked provider package, only h1: checksums can be used in that case
+func (p *ProviderInstaller) verifyDirectory(dir string, hashes []string) error {
+	if len(hashes) == 0 {
+		return nil
+	}
+	files, err := dirhash.DirFiles(dir, "")
+	if err != nil {
+		return errors.Wrapf(err, "unable to compute checksum of %s", dir)
+	}
+	return p.verifyFiles(dir, files, hashes, knownChecksumsMismatch)
+}
+
+// verifyInstalledBinary checks a provider installed by a previous run against the h1: checksums of the lock file,
+// the binary is hashed as a package of its own as the plugins directory is shared by every provider
+func (p *ProviderInstaller) verifyInstalledBinary(binary string, hashes []string) error {
+	h1Hashes := make([]string, 0, len(hashes))
+	for _, hash := range hashes {
+		if strings.HasPrefix(hash, hashSchemeV1) {
+			h1Hashes = append(h1Hashes, hash)
+		}
+	}
+	if len(h1Hashes) == 0 {
+		logrus.WithFields(logrus.Fields{
+			"path": binary,
+		}).Debug("No checksum to verify the installed provider against")
+		return nil
+	}
+	return p.verifyFiles(filepath.Dir(binary), []string{filepath.Base(binary)}, h1Hashes, fmt.Sprintf("installed binary %s does not match any of the checksums of the lock file", binary))
+}
+
+func (p *ProviderInstaller) verifyFiles(dir string, files, hashes []string, reason string) error {
+	h1, err := dirhash.Hash1(files, func(name string) (io.ReadCloser, error) {
+		return os.Open(filepath.Join(dir, name))
+	})
+	if err != nil {
+		return errors.Wrapf(err, "unable to compute checksum of %s", dir)
+	}
+	for _, hash := range hashes {
+		if hash == h1 {
+			return nil
+		}
+	}
+	return p.checksumMismatch(hashes, []string{h1}, reason)
+}
+
+// warnUnverified tells the user a provider is installed without checksums coming from the lock file
+func (p *ProviderInstaller) warnUnverified(location, message string) {
+	logrus.WithFields(logrus.Fields{
+		"provider": p.config.Key,
+		"version":  p.config.Version,
+		"location": location,
+	}).Warnf("%s, add the provider to .terraform.lock.hcl with terraform providers lock to verify it", message)
+}
+
+func (p *ProviderInstaller) checksumMismatch(expected, got []string, reason string) error {
+	logrus.WithFields(logrus.Fields{
+		"expected": expected,
+		"got":      got,
+	}).Debug("Provider checksum mismatch")
+	return p.verificationError(reason)
+}
+
+// verifySignedChecksums checks the archive against the SHA256SUMS file of the release once its signature is verified
+func (p *ProviderInstaller) verifySignedChecksums(archive string) error {
+	sums, err := p.readFile(p.config.GetChecksumsUrl())
+	if err != nil {
+		return err
+	}
+	signature, err := p.readFile(p.config.GetChecksumsUrl() + ".sig")
+	if err != nil {
+		return err
+	}
+
+	keyring, err := openpgp.ReadArmoredKeyRing(strings.NewReader(p.signingKey))
+	if err != nil {
+		return errors.Wrap(err, "unable to read provider signing key")
+	}
+	signer, err := openpgp.CheckDetachedSignature(keyring, bytes.NewReader(sums), bytes.NewReader(signature))
+	if err != nil {
+		logrus.WithField("err", err).Debug("Invalid provider checksums signature")
+		return p.verificationError("invalid signature of the release checksums")
+	}
+	logrus.WithFields(logrus.Fields{
+		"key": signer.PrimaryKey.KeyIdString(),
+	}).Debug("Verified signature of provider checksums")
+
+	expected := ""
+	for _, line := range strings.Split(string(sums), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 && fields[1] == p.config.GetArchiveName() {
+			expected = hashSchemeZip + fields[0]
+			break
+		}
+	}
+	if expected == "" {
+		return p.verificationError(fmt.Sprintf("no checksum found for %s in the release checksums", p.config.GetArchiveName()))
+	}
+
+	got, err := hashArchive(archive)
+	if err != nil {
+		return errors.Wrapf(err, "unable to compute checksum of %s", archive)
+	}
+	if got != expected {
+		return p.checksumMismatch([]string{expected}, []string{got}, "checksum does not match the release checksums")
+	}
+	return nil
+}
+
+func (p *ProviderInstaller) readFile(url string) ([]byte, error) {
+	path, err := p.download(url)
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(path)
+	return ioutil.ReadFile(path)
+}
+
+func (p *ProviderInstaller) verificationError(reason string) error {
+	return error2.ProviderVerificationError{
+		Provider: p.config.Key,
+		Version:  p.config.Version,
+		Reason:   reason,
+	}
+}
+
+func hashArchive(archive string) (string, error) {
+	f, err := os.Open(archive)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hashSchemeZip + hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -3,30 +3,60 @@ package terraform
 import (
 	"fmt"
 	"runtime"
+
+	"github.com/cloudskiff/driftctl/pkg/terraform/lock"
 )
 
 type ProviderConfig struct {
 	Key       string
 	Version   string
 	ConfigDir string
+	// Installation configures the sources the provider is installed from, it is downloaded from the registry when nil
+	Installation *ProviderInstallation
 }
 
 func (c *ProviderConfig) GetDownloadUrl() string {
-	arch := runtime.GOARCH
-	if runtime.GOOS == "darwin" && runtime.GOARCH == "arm64" {
-		arch = "amd64"
-	}
 	return fmt.Sprintf(
-		"https://releases.hashicorp.com/terraform-provider-%s/%s/terraform-provider-%s_%s_%s_%s.zip",
+		"https://releases.hashicorp.com/terraform-provider-%s/%s/%s",
+		c.Key,
+		c.Version,
+		c.GetArchiveName(),
+	)
+}
+
+// GetChecksumsUrl returns the SHA256SUMS file of the release, its signature is found by appending .sig
+func (c *ProviderConfig) GetChecksumsUrl() string {
+	return fmt.Sprintf(
+		"https://releases.hashicorp.com/terraform-provider-%s/%s/terraform-provider-%s_%s_SHA256SUMS",
 		c.Key,
 		c.Version,
 		c.Key,
 		c.Version,
-		runtime.GOOS,
-		arch,
 	)
 }
 
+// GetArchiveName returns the file name of the provider package for the current platform
+func (c *ProviderConfig) GetArchiveName() string {
+	return fmt.Sprintf("terraform-provider-%s_%s_%s.zip", c.Key, c.Version, c.GetPlatform())
+}
+
 func (c *ProviderConfig) GetBinaryName() string {
 	return fmt.Sprintf("terraform-provider-%s_v%s", c.Key, c.Version)
 }
+
+// GetPlatform returns the os_arch pair of the package to install, amd64 packages are used on apple silicon
+func (c *ProviderConfig) GetPlatform() string {
+	arch := runtime.GOARCH
+	if runtime.GOOS == "darwin" && runtime.GOARCH == "arm64" {
+		arch = "amd64"
+	}
+	return fmt.Sprintf("%s_%s", runtime.GOOS, arch)
+}
+
+func (c *ProviderConfig) GetAddress() *lock.ProviderAddress {
+	return &lock.ProviderAddress{
+		Hostname:  "registry.terraform.io",
+		Namespace: "hashicorp",
+		Type:      c.Key,
+	}
+}
@@ -2,7 +2,6 @@ package terraform
 
 import (
 	"context"
-	"io/ioutil"
 	"net/http"
 	"os"
 
@@ -13,19 +12,18 @@ import (
 )
 
 type ProviderDownloaderInterface interface {
+	// Download writes the provider archive found at url to path
 	Download(url, path string) error
 }
 
 type ProviderDownloader struct {
 	httpclient *http.Client
-	unzip      getter.ZipDecompressor
 	context    context.Context
 }
 
 func NewProviderDownloader() *ProviderDownloader {
 	return &ProviderDownloader{
 		httpclient: http.DefaultClient,
-		unzip:      getter.ZipDecompressor{},
 		context:    context.Background(),
 	}
 }
@@ -51,12 +49,11 @@ func (p *ProviderDownloader) Download(url, path string) error {
 	if resp.StatusCode != http.StatusOK {
 		return errors.Errorf("unsuccessful request to %s: %s", url, resp.Status)
 	}
-	f, err := ioutil.TempFile("", "terraform-provider")
+	f, err := os.Create(path)
 	if err != nil {
-		return errors.Errorf("failed to open temporary file to download from %s", url)
+		return errors.Errorf("failed to open %s to download from %s", path, url)
 	}
 	defer f.Close()
-	defer os.Remove(f.Name())
 	n, err := getter.Copy(p.context, f, resp.Body)
 	if err == nil && n < resp.ContentLength {
 		err = errors.Errorf(
@@ -65,16 +62,5 @@ func (p *ProviderDownloader) Download(url, path string) error {
 			n,
 		)
 	}
-	if err != nil {
-		return err
-	}
-	logrus.WithFields(logrus.Fields{
-		"src": f.Name(),
-		"dst": path,
-	}).Debug("Decompressing archive")
-	err = p.unzip.Decompress(path, f.Name(), true, 0)
-	if err != nil {
-		return err
-	}
-	return nil
+	return err
 }
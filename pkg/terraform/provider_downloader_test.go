@@ -55,24 +55,16 @@ func TestProviderDownloader_Download(t *testing.T) {
 				assert.Contains(err.Error(), "test error")
 			},
 		},
-		{
-			name:     "TestInvalidZip",
-			testFile: aws.String("invalid.zip"),
-			assert: func(assert *assert.Assertions, tmpDir string, err error) {
-				assert.NotNil(err)
-				infos, err := ioutil.ReadDir(tmpDir)
-				assert.Nil(err)
-				assert.Len(infos, 0)
-			},
-		},
 		{
 			name:     "TestValidZip",
 			testFile: aws.String("terraform-provider-aws_3.5.0_linux_amd64.zip"),
 			assert: func(assert *assert.Assertions, tmpDir string, err error) {
 				assert.Nil(err)
-				file, err := ioutil.ReadFile(path.Join(tmpDir, "terraform-provider-aws_v3.5.0_x5"))
+				expected, err := ioutil.ReadFile("./testdata/terraform-provider-aws_3.5.0_linux_amd64.zip")
+				assert.Nil(err)
+				file, err := ioutil.ReadFile(path.Join(tmpDir, "provider.zip"))
 				assert.Nil(err)
-				assert.Equal([]byte{0x74, 0x65, 0x73, 0x74, 0xa}, file)
+				assert.Equal(expected, file)
 			},
 		},
 	}
@@ -101,7 +93,7 @@ func TestProviderDownloader_Download(t *testing.T) {
 				}
 			}
 
-			err := downloader.Download(url, tmpDir)
+			err := downloader.Download(url, path.Join(tmpDir, "provider.zip"))
 
 			c.assert(assert, tmpDir, err)
 		})
@@ -0,0 +1,221 @@
+package terraform
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/hashicorp/hcl"
+	"github.com/hashicorp/hcl/hcl/ast"
+	"github.com/mitchellh/go-homedir"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/cloudskiff/driftctl/pkg/terraform/lock"
+)
+
+const (
+	InstallationMethodDirect           = "direct"
+	InstallationMethodFilesystemMirror = "filesystem_mirror"
+	InstallationMethodNetworkMirror    = "network_mirror"
+)
+
+type installationMethodBlock struct {
+	Path    string   `hcl:"path"`
+	URL     string   `hcl:"url"`
+	Include []string `hcl:"include"`
+	Exclude []string `hcl:"exclude"`
+}
+
+// ProviderInstallationMethod is a source providers are installed from
+type ProviderInstallationMethod struct {
+	Type string
+	// Location is the directory of a filesystem mirror or the base URL of a network mirror
+	Location string
+	// Include and Exclude are provider address patterns like registry.terraform.io/hashicorp/*
+	Include []string
+	Exclude []string
+}
+
+// Matches returns true when the method applies to the given provider
+func (m *ProviderInstallationMethod) Matches(addr *lock.ProviderAddress) bool {
+	included := len(m.Include) == 0
+	for _, pattern := range m.Include {
+		if matchProviderPattern(pattern, addr) {
+			included = true
+			break
+		}
+	}
+	if !included {
+		return false
+	}
+	for _, pattern := range m.Exclude {
+		if matchProviderPattern(pattern, addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// ProviderInstallation lists the methods tried in order to install a provider,
+// it follows the provider_installation block of the terraform CLI configuration
+type ProviderInstallation struct {
+	Methods []ProviderInstallationMethod
+	// Lockfile records the checksums mirrored packages are verified against
+	Lockfile *lock.Lockfile
+}
+
+// GetMethods returns the methods applying to the given provider, the registry is used by default
+func (i *ProviderInstallation) GetMethods(addr *lock.ProviderAddress) []ProviderInstallationMethod {
+	if i == nil || len(i.Methods) == 0 {
+		return []ProviderInstallationMethod{{Type: InstallationMethodDirect}}
+	}
+	methods := make([]ProviderInstallationMethod, 0, len(i.Methods))
+	for _, method := range i.Methods {
+		if method.Matches(addr) {
+			methods = append(methods, method)
+		}
+	}
+	return methods
+}
+
+// GetHashes returns the checksums recorded in the lock file for the given provider version
+func (i *ProviderInstallation) GetHashes(addr *lock.ProviderAddress, version string) []string {
+	if i == nil || i.Lockfile == nil {
+		return nil
+	}
+	provider := i.Lockfile.GetProviderByAddress(addr)
+	if provider == nil || provider.Version != version {
+		return nil
+	}
+	return provider.Hashes
+}
+
+// GetProviderInstallation returns the mirrors given on the command line, URLs being network mirrors,
+// or the installation methods of the terraform CLI configuration when there is none
+func GetProviderInstallation(mirrors []string) (*ProviderInstallation, error) {
+	if len(mirrors) > 0 {
+		installation := &ProviderInstallation{}
+		for _, mirror := range mirrors {
+			method := ProviderInstallationMethod{Type: InstallationMethodFilesystemMirror, Location: mirror}
+			if strings.HasPrefix(mirror, "https://") || strings.HasPrefix(mirror, "http://") {
+				method.Type = InstallationMethodNetworkMirror
+			}
+			installation.Methods = append(installation.Methods, method)
+		}
+		return installation, nil
+	}
+
+	path, err := getCLIConfigFile()
+	if err != nil {
+		return nil, err
+	}
+	return ReadProviderInstallation(path)
+}
+
+// ReadProviderInstallation reads the provider_installation block of a terraform CLI configuration file,
+// a missing file results in an empty installation
+func ReadProviderInstallation(path string) (*ProviderInstallation, error) {
+	installation := &ProviderInstallation{}
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return installation, nil
+	}
+
+	src, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to read terraform CLI configuration %s", path)
+	}
+	// Like in terraform the CLI configuration is HCL 1, e.g. dev_overrides blocks use quoted provider addresses as keys
+	file, err := hcl.ParseBytes(src)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to parse terraform CLI configuration %s", path)
+	}
+	root, ok := file.Node.(*ast.ObjectList)
+	if !ok {
+		return nil, errors.Errorf("unable to parse terraform CLI configuration %s", path)
+	}
+
+	for _, block := range root.Filter("provider_installation").Items {
+		body, ok := block.Val.(*ast.ObjectType)
+		if !ok {
+			return nil, errors.Errorf("invalid provider_installation block in %s", path)
+		}
+		for _, methodBlock := range body.List.Items {
+			if len(methodBlock.Keys) != 1 {
+				return nil, errors.Errorf("invalid provider_installation block in %s", path)
+			}
+			methodType, _ := methodBlock.Keys[0].Token.Value().(string)
+			switch methodType {
+			case InstallationMethodDirect, InstallationMethodFilesystemMirror, InstallationMethodNetworkMirror:
+			default:
+				// dev_overrides only apply to terraform commands
+				logrus.WithFields(logrus.Fields{
+					"path":  path,
+					"block": methodType,
+				}).Debug("Ignoring provider_installation block")
+				continue
+			}
+
+			decoded := installationMethodBlock{}
+			if err := hcl.DecodeObject(&decoded, methodBlock.Val); err != nil {
+				return nil, errors.Wrapf(err, "invalid provider_installation block in %s", path)
+			}
+			method := ProviderInstallationMethod{
+				Type:    methodType,
+				Include: decoded.Include,
+				Exclude: decoded.Exclude,
+			}
+			switch methodType {
+			case InstallationMethodFilesystemMirror:
+				method.Location = decoded.Path
+			case InstallationMethodNetworkMirror:
+				method.Location = decoded.URL
+			}
+			if method.Type != InstallationMethodDirect && method.Location == "" {
+				return nil, errors.Errorf("missing location of %s in %s", method.Type, path)
+			}
+			installation.Methods = append(installation.Methods, method)
+		}
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"path":    path,
+		"methods": len(installation.Methods),
+	}).Debug("Read provider installation from terraform CLI configuration")
+
+	return installation, nil
+}
+
+// matchProviderPattern matches an address against a pattern where each part may be a wildcard,
+// the hostname defaults to the public registry as in terraform
+func matchProviderPattern(pattern string, addr *lock.ProviderAddress) bool {
+	parts := strings.Split(pattern, "/")
+	if len(parts) == 2 {
+		parts = append([]string{"registry.terraform.io"}, parts...)
+	}
+	if len(parts) != 3 {
+		return false
+	}
+	for i, value := range []string{addr.Hostname, addr.Namespace, addr.Type} {
+		if parts[i] != "*" && !strings.EqualFold(parts[i], value) {
+			return false
+		}
+	}
+	return true
+}
+
+func getCLIConfigFile() (string, error) {
+	if path := os.Getenv("TF_CLI_CONFIG_FILE"); path != "" {
+		return path, nil
+	}
+	if runtime.GOOS == "windows" {
+		return filepath.Join(os.Getenv("APPDATA"), "terraform.rc"), nil
+	}
+	homeDir, err := homedir.Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, ".terraformrc"), nil
+}
@@ -0,0 +1,119 @@
+package terraform
+
+import (
+	"os"
+	"testing"
+
+	"github.com/cloudskiff/driftctl/pkg/terraform/lock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadProviderInstallation(t *testing.T) {
+	cases := []struct {
+		name     string
+		path     string
+		expected *ProviderInstallation
+		err      string
+	}{
+		{
+			name: "test installation methods",
+			path: "testdata/cli_config/mirrors.tfrc",
+			expected: &ProviderInstallation{Methods: []ProviderInstallationMethod{
+				{
+					Type:     InstallationMethodFilesystemMirror,
+					Location: "/usr/share/terraform/providers",
+					Include:  []string{"registry.terraform.io/hashicorp/*"},
+				},
+				{
+					Type:     InstallationMethodNetworkMirror,
+					Location: "https://mirror.example.com/providers/",
+					Exclude:  []string{"hashicorp/github"},
+				},
+				{
+					Type:    InstallationMethodDirect,
+					Exclude: []string{"registry.terraform.io/*/*"},
+				},
+			}},
+		},
+		{
+			name: "test dev overrides",
+			path: "testdata/cli_config/dev_overrides.tfrc",
+			expected: &ProviderInstallation{Methods: []ProviderInstallationMethod{
+				{Type: InstallationMethodDirect},
+			}},
+		},
+		{
+			name:     "test without provider installation",
+			path:     "testdata/cli_config/no_installation.tfrc",
+			expected: &ProviderInstallation{},
+		},
+		{
+			name:     "test missing file",
+			path:     "testdata/cli_config/missing.tfrc",
+			expected: &ProviderInstallation{},
+		},
+		{
+			name: "test missing mirror path",
+			path: "testdata/cli_config/missing_path.tfrc",
+			err:  "missing location of filesystem_mirror in testdata/cli_config/missing_path.tfrc",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := ReadProviderInstallation(c.path)
+			if c.err != "" {
+				assert.EqualError(t, err, c.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, c.expected, got)
+		})
+	}
+}
+
+func TestGetProviderInstallation(t *testing.T) {
+	got, err := GetProviderInstallation([]string{"/opt/providers", "https://mirror.example.com/providers"})
+	assert.NoError(t, err)
+	assert.Equal(t, &ProviderInstallation{Methods: []ProviderInstallationMethod{
+		{Type: InstallationMethodFilesystemMirror, Location: "/opt/providers"},
+		{Type: InstallationMethodNetworkMirror, Location: "https://mirror.example.com/providers"},
+	}}, got)
+
+	os.Setenv("TF_CLI_CONFIG_FILE", "testdata/cli_config/mirrors.tfrc")
+	defer os.Unsetenv("TF_CLI_CONFIG_FILE")
+	got, err = GetProviderInstallation(nil)
+	assert.NoError(t, err)
+	assert.Len(t, got.Methods, 3)
+}
+
+func TestProviderInstallation_GetMethods(t *testing.T) {
+	installation, err := ReadProviderInstallation("testdata/cli_config/mirrors.tfrc")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	aws := &lock.ProviderAddress{Hostname: "registry.terraform.io", Namespace: "hashicorp", Type: "aws"}
+	methods := installation.GetMethods(aws)
+	assert.Len(t, methods, 2)
+	assert.Equal(t, InstallationMethodFilesystemMirror, methods[0].Type)
+	assert.Equal(t, InstallationMethodNetworkMirror, methods[1].Type)
+
+	github := &lock.ProviderAddress{Hostname: "registry.terraform.io", Namespace: "hashicorp", Type: "github"}
+	methods = installation.GetMethods(github)
+	assert.Len(t, methods, 1)
+	assert.Equal(t, InstallationMethodFilesystemMirror, methods[0].Type)
+
+	var empty *ProviderInstallation
+	assert.Equal(t, []ProviderInstallationMethod{{Type: InstallationMethodDirect}}, empty.GetMethods(aws))
+}
+
+func TestProviderInstallation_GetHashes(t *testing.T) {
+	installation := &ProviderInstallation{Lockfile: &lock.Lockfile{Providers: []lock.ProviderBlock{
+		{Address: "registry.terraform.io/hashicorp/aws", Version: "3.19.0", Hashes: []string{"h1:abc"}},
+	}}}
+	aws := &lock.ProviderAddress{Hostname: "registry.terraform.io", Namespace: "hashicorp", Type: "aws"}
+
+	assert.Equal(t, []string{"h1:abc"}, installation.GetHashes(aws, "3.19.0"))
+	assert.Nil(t, installation.GetHashes(aws, "3.20.0"))
+}
@@ -3,6 +3,7 @@ package terraform
 import (
 	"fmt"
 	"io/fs"
+	"io/ioutil"
 	"os"
 	"path"
 	"path/filepath"
@@ -10,6 +11,7 @@ import (
 	"strings"
 
 	error2 "github.com/cloudskiff/driftctl/pkg/terraform/error"
+	"github.com/hashicorp/go-getter"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 
@@ -24,6 +26,8 @@ type ProviderInstaller struct {
 	downloader ProviderDownloaderInterface
 	config     ProviderConfig
 	homeDir    string
+	// signingKey is the armored public key the checksums of downloaded providers are signed with
+	signingKey string
 }
 
 func NewProviderInstaller(config ProviderConfig) (*ProviderInstaller, error) {
@@ -31,6 +35,7 @@ func NewProviderInstaller(config ProviderConfig) (*ProviderInstaller, error) {
 		NewProviderDownloader(),
 		config,
 		config.ConfigDir,
+		HashicorpPublicKey,
 	}, nil
 }
 
@@ -43,12 +48,8 @@ func (p *ProviderInstaller) Install() (string, error) {
 	if err != nil && os.IsNotExist(err) {
 		logrus.WithFields(logrus.Fields{
 			"path": providerPath,
-		}).Debug("provider not found, downloading ...")
-		output.Printf("Downloading terraform provider: %s\n", p.config.Key)
-		err := p.downloader.Download(
-			p.config.GetDownloadUrl(),
-			providerDir,
-		)
+		}).Debug("provider not found, installing ...")
+		err := p.install(providerDir)
 		if err != nil {
 			if notFoundErr, ok := err.(error2.ProviderNotFoundError); ok {
 				notFoundErr.Version = p.config.Version
@@ -56,7 +57,7 @@ func (p *ProviderInstaller) Install() (string, error) {
 			}
 			return "", err
 		}
-		logrus.Debug("Download successful")
+		logrus.Debug("Installation successful")
 	}
 
 	if info != nil && info.IsDir() {
@@ -70,11 +71,102 @@ func (p *ProviderInstaller) Install() (string, error) {
 		logrus.WithFields(logrus.Fields{
 			"path": providerPath,
 		}).Debug("Found existing provider")
+		if err := p.verifyInstalledBinary(providerPath, p.config.Installation.GetHashes(p.config.GetAddress(), p.config.Version)); err != nil {
+			return "", err
+		}
 	}
 
 	return p.getBinaryPath(), nil
 }
 
+// install tries each installation method applying to the provider until one of them has the requested version
+func (p *ProviderInstaller) install(providerDir string) error {
+	for _, method := range p.config.Installation.GetMethods(p.config.GetAddress()) {
+		logrus.WithFields(logrus.Fields{
+			"method":   method.Type,
+			"location": method.Location,
+		}).Debug("Installing provider")
+
+		var err error
+		switch method.Type {
+		case InstallationMethodDirect:
+			output.Printf("Downloading terraform provider: %s\n", p.config.Key)
+			err = p.installFromRegistry(providerDir)
+		case InstallationMethodFilesystemMirror:
+			err = p.installFromFilesystemMirror(method.Location, providerDir)
+		case InstallationMethodNetworkMirror:
+			err = p.installFromNetworkMirror(method.Location, providerDir)
+		default:
+			err = errors.Errorf("unsupported provider installation method %s", method.Type)
+		}
+		if _, ok := err.(error2.ProviderNotFoundError); ok {
+			logrus.WithFields(logrus.Fields{
+				"method":   method.Type,
+				"location": method.Location,
+			}).Debug("Provider not found")
+			continue
+		}
+		return err
+	}
+	return error2.ProviderNotFoundError{}
+}
+
+// installFromRegistry downloads the provider from the HashiCorp releases, the archive is checked against
+// the signed checksums of the release and the checksums of the lock file before being unpacked
+func (p *ProviderInstaller) installFromRegistry(providerDir string) error {
+	archive, err := p.download(p.config.GetDownloadUrl())
+	if err != nil {
+		return err
+	}
+	defer os.Remove(archive)
+
+	if err := p.verifySignedChecksums(archive); err != nil {
+		return err
+	}
+	if err := p.verifyArchive(archive, p.config.Installation.GetHashes(p.config.GetAddress(), p.config.Version)); err != nil {
+		return err
+	}
+	return p.unpack(archive, providerDir)
+}
+
+// installFromURL downloads a provider archive and verifies it against the given checksums if any
+func (p *ProviderInstaller) installFromURL(url string, hashes []string, providerDir string) error {
+	archive, err := p.download(url)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(archive)
+
+	if err := p.verifyArchive(archive, hashes); err != nil {
+		return err
+	}
+	return p.unpack(archive, providerDir)
+}
+
+// download writes the file found at url in a temporary file the caller has to remove
+func (p *ProviderInstaller) download(url string) (string, error) {
+	f, err := ioutil.TempFile("", "terraform-provider")
+	if err != nil {
+		return "", errors.Errorf("failed to open temporary file to download from %s", url)
+	}
+	f.Close()
+
+	if err := p.downloader.Download(url, f.Name()); err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
+
+func (p *ProviderInstaller) unpack(archive, providerDir string) error {
+	logrus.WithFields(logrus.Fields{
+		"src": archive,
+		"dst": providerDir,
+	}).Debug("Decompressing archive")
+	unzip := getter.ZipDecompressor{}
+	return unzip.Decompress(providerDir, archive, true, 0)
+}
+
 func (p ProviderInstaller) getProviderDirectory() string {
 	return path.Join(p.homeDir, fmt.Sprintf(".driftctl/plugins/%s_%s/", runtime.GOOS, runtime.GOARCH))
 }
@@ -2,6 +2,8 @@ package terraform
 
 import (
 	"fmt"
+	"io/ioutil"
+	"net/http"
 	"os"
 	"path"
 	"runtime"
@@ -9,6 +11,10 @@ import (
 
 	"github.com/cloudskiff/driftctl/mocks"
 	terraformError "github.com/cloudskiff/driftctl/pkg/terraform/error"
+	"github.com/cloudskiff/driftctl/pkg/terraform/lock"
+	"github.com/jarcoal/httpmock"
+	"github.com/sirupsen/logrus"
+	logrustest "github.com/sirupsen/logrus/hooks/test"
 	"github.com/stretchr/testify/mock"
 
 	"github.com/stretchr/testify/assert"
@@ -27,12 +33,13 @@ func TestProviderInstallerInstallDoesNotExist(t *testing.T) {
 	}
 
 	mockDownloader := mocks.ProviderDownloaderInterface{}
-	mockDownloader.On("Download", config.GetDownloadUrl(), path.Join(fakeTmpHome, expectedSubFolder)).Return(nil)
+	mockRelease(t, &mockDownloader, config, "testdata/terraform-provider-aws_3.5.0_linux_amd64.zip")
 
 	installer := ProviderInstaller{
 		downloader: &mockDownloader,
 		config:     config,
 		homeDir:    fakeTmpHome,
+		signingKey: readTestSigningKey(t),
 	}
 
 	providerPath, err := installer.Install()
@@ -156,6 +163,68 @@ func TestProviderInstallerInstallPostfixIsHandler(t *testing.T) {
 
 }
 
+func TestProviderInstallerInstallAlreadyExistIsVerified(t *testing.T) {
+	cases := []struct {
+		name       string
+		content    string
+		lockHashes []string
+		err        string
+	}{
+		{
+			name:       "test binary matching lock file",
+			content:    "test\n",
+			lockHashes: []string{"zh:2db5345840993edb9bd17ba5715f6bcdca87613dc150b9590f2da14d34aa5b52", "h1:7Ca6K4lpDjeZE6QeTlna6tjY0tgrtODWO6KXgoAplgM="},
+		},
+		{
+			name:       "test binary without content checksum in lock file",
+			content:    "tampered\n",
+			lockHashes: []string{"zh:2db5345840993edb9bd17ba5715f6bcdca87613dc150b9590f2da14d34aa5b52"},
+		},
+		{
+			name:       "test binary not matching lock file",
+			content:    "tampered\n",
+			lockHashes: []string{"h1:7Ca6K4lpDjeZE6QeTlna6tjY0tgrtODWO6KXgoAplgM="},
+			err:        "Provider aws version 3.5.0 could not be verified: installed binary %s does not match any of the checksums of the lock file",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			config := ProviderConfig{
+				Key:     "aws",
+				Version: "3.5.0",
+				Installation: &ProviderInstallation{
+					Lockfile: &lock.Lockfile{Providers: []lock.ProviderBlock{
+						{Address: "registry.terraform.io/hashicorp/aws", Version: "3.5.0", Hashes: c.lockHashes},
+					}},
+				},
+			}
+			mockDownloader := mocks.ProviderDownloaderInterface{}
+			installer := ProviderInstaller{
+				downloader: &mockDownloader,
+				config:     config,
+				homeDir:    t.TempDir(),
+			}
+
+			binary := path.Join(installer.getProviderDirectory(), "terraform-provider-aws_v3.5.0_x5")
+			_ = os.MkdirAll(installer.getProviderDirectory(), 0755)
+			if err := ioutil.WriteFile(binary, []byte(c.content), 0755); err != nil {
+				t.Fatal(err)
+			}
+
+			providerPath, err := installer.Install()
+			mockDownloader.AssertExpectations(t)
+			if c.err != "" {
+				assert.EqualError(t, err, fmt.Sprintf(c.err, binary))
+				assert.IsType(t, terraformError.ProviderVerificationError{}, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, binary, providerPath)
+		})
+	}
+}
+
 func TestProviderInstallerVersionDoesNotExist(t *testing.T) {
 
 	assert := assert.New(t)
@@ -192,10 +261,11 @@ func TestProviderInstallerWithConfigDirectory(t *testing.T) {
 	}
 
 	mockDownloader := mocks.ProviderDownloaderInterface{}
-	mockDownloader.On("Download", config.GetDownloadUrl(), path.Join(fakeTmpHome, expectedSubFolder)).Return(nil)
+	mockRelease(t, &mockDownloader, config, "testdata/terraform-provider-aws_3.5.0_linux_amd64.zip")
 
 	installer, _ := NewProviderInstaller(config)
 	installer.downloader = &mockDownloader
+	installer.signingKey = readTestSigningKey(t)
 
 	providerPath, err := installer.Install()
 	mockDownloader.AssertExpectations(t)
@@ -204,3 +274,366 @@ func TestProviderInstallerWithConfigDirectory(t *testing.T) {
 	assert.Equal(path.Join(fakeTmpHome, expectedSubFolder, config.GetBinaryName()), providerPath)
 
 }
+
+func copyTestFile(t *testing.T, src string) func(args mock.Arguments) {
+	return func(args mock.Arguments) {
+		content, err := ioutil.ReadFile(src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(args.String(1), content, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// mockRelease serves the archive along with checksums signed by the test signing key
+func mockRelease(t *testing.T, downloader *mocks.ProviderDownloaderInterface, config ProviderConfig, archive string) {
+	downloader.On("Download", config.GetDownloadUrl(), mock.Anything).Run(copyTestFile(t, archive)).Return(nil)
+	downloader.On("Download", config.GetChecksumsUrl(), mock.Anything).Run(copyTestFile(t, "testdata/terraform-provider-aws_SHA256SUMS")).Return(nil)
+	downloader.On("Download", config.GetChecksumsUrl()+".sig", mock.Anything).Run(copyTestFile(t, "testdata/terraform-provider-aws_SHA256SUMS.sig")).Return(nil)
+}
+
+func readTestSigningKey(t *testing.T) string {
+	key, err := ioutil.ReadFile("testdata/signing_key.asc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(key)
+}
+
+func TestProviderInstallerVerification(t *testing.T) {
+	const zipHash = "zh:2db5345840993edb9bd17ba5715f6bcdca87613dc150b9590f2da14d34aa5b52"
+
+	cases := []struct {
+		name       string
+		version    string
+		archive    string
+		signingKey string
+		lockHashes []string
+		err        string
+	}{
+		{
+			name:       "test valid release",
+			version:    "3.19.0",
+			archive:    "testdata/terraform-provider-aws_3.5.0_linux_amd64.zip",
+			lockHashes: []string{zipHash},
+		},
+		{
+			name:       "test checksums signed by another key",
+			version:    "3.19.0",
+			archive:    "testdata/terraform-provider-aws_3.5.0_linux_amd64.zip",
+			signingKey: HashicorpPublicKey,
+			err:        "Provider aws version 3.19.0 could not be verified: invalid signature of the release checksums",
+		},
+		{
+			name:    "test archive not matching release checksums",
+			version: "3.19.0",
+			archive: "testdata/invalid.zip",
+			err:     "Provider aws version 3.19.0 could not be verified: checksum does not match the release checksums",
+		},
+		{
+			name:    "test archive missing from release checksums",
+			version: "3.20.0",
+			archive: "testdata/terraform-provider-aws_3.5.0_linux_amd64.zip",
+			err:     "Provider aws version 3.20.0 could not be verified: no checksum found for terraform-provider-aws_3.20.0_" + (&ProviderConfig{}).GetPlatform() + ".zip in the release checksums",
+		},
+		{
+			name:       "test archive not matching lock file",
+			version:    "3.19.0",
+			archive:    "testdata/terraform-provider-aws_3.5.0_linux_amd64.zip",
+			lockHashes: []string{"zh:0000"},
+			err:        "Provider aws version 3.19.0 could not be verified: checksum does not match any of the checksums of the lock file or mirror",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			config := ProviderConfig{
+				Key:     "aws",
+				Version: c.version,
+				Installation: &ProviderInstallation{
+					Lockfile: &lock.Lockfile{Providers: []lock.ProviderBlock{
+						{Address: "registry.terraform.io/hashicorp/aws", Version: c.version, Hashes: c.lockHashes},
+					}},
+				},
+			}
+			mockDownloader := mocks.ProviderDownloaderInterface{}
+			mockRelease(t, &mockDownloader, config, c.archive)
+
+			signingKey := c.signingKey
+			if signingKey == "" {
+				signingKey = readTestSigningKey(t)
+			}
+			installer := ProviderInstaller{
+				downloader: &mockDownloader,
+				config:     config,
+				homeDir:    t.TempDir(),
+				signingKey: signingKey,
+			}
+
+			_, err := installer.Install()
+			if c.err != "" {
+				assert.EqualError(t, err, c.err)
+				assert.IsType(t, terraformError.ProviderVerificationError{}, err)
+				infos, _ := ioutil.ReadDir(installer.getProviderDirectory())
+				assert.Len(t, infos, 0)
+				return
+			}
+			assert.NoError(t, err)
+			assert.FileExists(t, path.Join(installer.getProviderDirectory(), "terraform-provider-aws_v3.5.0_x5"))
+		})
+	}
+}
+
+func TestProviderInstallerFilesystemMirror(t *testing.T) {
+	const (
+		zipHash = "zh:2db5345840993edb9bd17ba5715f6bcdca87613dc150b9590f2da14d34aa5b52"
+		h1Hash  = "h1:7Ca6K4lpDjeZE6QeTlna6tjY0tgrtODWO6KXgoAplgM="
+	)
+
+	cases := []struct {
+		name         string
+		layout       string
+		hashes       []string
+		methods      []ProviderInstallationMethod
+		mockDownload bool
+		warning      string
+		err          string
+	}{
+		{
+			name:    "test packed layout without lock file",
+			layout:  "packed",
+			warning: "Provider installed from a filesystem mirror without checksum verification, add the provider to .terraform.lock.hcl with terraform providers lock to verify it",
+		},
+		{
+			name:   "test packed layout with matching zip checksum",
+			layout: "packed",
+			hashes: []string{"zh:0000", zipHash},
+		},
+		{
+			name:   "test packed layout with matching content checksum",
+			layout: "packed",
+			hashes: []string{h1Hash},
+		},
+		{
+			name:   "test packed layout with checksum mismatch",
+			layout: "packed",
+			hashes: []string{"zh:0000", "h1:0000"},
+			err:    "Provider aws version 3.5.0 could not be verified: checksum does not match any of the checksums of the lock file or mirror",
+		},
+		{
+			name:   "test unpacked layout with matching content checksum",
+			layout: "unpacked",
+			hashes: []string{zipHash, h1Hash},
+		},
+		{
+			name:   "test unpacked layout with zip checksums only",
+			layout: "unpacked",
+			hashes: []string{zipHash},
+			err:    "Provider aws version 3.5.0 could not be verified: checksum does not match any of the checksums of the lock file or mirror",
+		},
+		{
+			name: "test provider missing from mirror",
+			err:  "Provider version 3.5.0 does not exist",
+		},
+		{
+			name:   "test excluded provider falls back to registry",
+			layout: "packed",
+			methods: []ProviderInstallationMethod{
+				{Type: InstallationMethodFilesystemMirror, Exclude: []string{"hashicorp/aws"}},
+				{Type: InstallationMethodDirect},
+			},
+			mockDownload: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			mirror := t.TempDir()
+			config := ProviderConfig{
+				Key:     "aws",
+				Version: "3.5.0",
+			}
+
+			base := path.Join(mirror, "registry.terraform.io", "hashicorp", "aws")
+			switch c.layout {
+			case "packed":
+				content, err := ioutil.ReadFile("testdata/terraform-provider-aws_3.5.0_linux_amd64.zip")
+				if err != nil {
+					t.Fatal(err)
+				}
+				_ = os.MkdirAll(base, 0755)
+				if err := ioutil.WriteFile(path.Join(base, fmt.Sprintf("terraform-provider-aws_3.5.0_%s.zip", config.GetPlatform())), content, 0644); err != nil {
+					t.Fatal(err)
+				}
+			case "unpacked":
+				dir := path.Join(base, "3.5.0", config.GetPlatform())
+				_ = os.MkdirAll(dir, 0755)
+				if err := ioutil.WriteFile(path.Join(dir, "terraform-provider-aws_v3.5.0_x5"), []byte("test\n"), 0755); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			methods := c.methods
+			if methods == nil {
+				methods = []ProviderInstallationMethod{{Type: InstallationMethodFilesystemMirror}}
+			}
+			for i := range methods {
+				if methods[i].Type == InstallationMethodFilesystemMirror {
+					methods[i].Location = mirror
+				}
+			}
+			config.Installation = &ProviderInstallation{
+				Methods: methods,
+				Lockfile: &lock.Lockfile{Providers: []lock.ProviderBlock{
+					{Address: "registry.terraform.io/hashicorp/aws", Version: "3.5.0", Hashes: c.hashes},
+				}},
+			}
+
+			mockDownloader := mocks.ProviderDownloaderInterface{}
+			if c.mockDownload {
+				mockRelease(t, &mockDownloader, config, "testdata/terraform-provider-aws_3.5.0_linux_amd64.zip")
+			}
+
+			installer := ProviderInstaller{
+				downloader: &mockDownloader,
+				config:     config,
+				homeDir:    t.TempDir(),
+				signingKey: readTestSigningKey(t),
+			}
+
+			hook := logrustest.NewGlobal()
+			defer logrus.StandardLogger().ReplaceHooks(make(logrus.LevelHooks))
+			providerPath, err := installer.Install()
+			mockDownloader.AssertExpectations(t)
+			assertWarning(t, hook, c.warning)
+			if c.err != "" {
+				assert.EqualError(t, err, c.err)
+				return
+			}
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, path.Join(installer.getProviderDirectory(), "terraform-provider-aws_v3.5.0_x5"), providerPath)
+			content, err := ioutil.ReadFile(providerPath)
+			assert.NoError(t, err)
+			assert.Equal(t, "test\n", string(content))
+		})
+	}
+}
+
+func TestProviderInstallerNetworkMirror(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	archive, err := ioutil.ReadFile("testdata/terraform-provider-aws_3.5.0_linux_amd64.zip")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name       string
+		version    string
+		lockHashes []string
+		warning    string
+		err        string
+	}{
+		{
+			name:    "test install with mirror checksums",
+			version: "3.5.0",
+			warning: "Provider only verified against the checksums given by the network mirror, add the provider to .terraform.lock.hcl with terraform providers lock to verify it",
+		},
+		{
+			name:       "test lock file checksums take precedence",
+			version:    "3.5.0",
+			lockHashes: []string{"zh:0000"},
+			err:        "Provider aws version 3.5.0 could not be verified: checksum does not match any of the checksums of the lock file or mirror",
+		},
+		{
+			name:       "test install with lock file checksums",
+			version:    "3.5.0",
+			lockHashes: []string{"zh:2db5345840993edb9bd17ba5715f6bcdca87613dc150b9590f2da14d34aa5b52"},
+		},
+		{
+			name:    "test version missing from mirror",
+			version: "3.6.0",
+			err:     "Provider version 3.6.0 does not exist",
+		},
+		{
+			name:    "test platform missing from mirror",
+			version: "3.7.0",
+			err:     "Provider version 3.7.0 does not exist",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			httpmock.Reset()
+			config := ProviderConfig{
+				Key:     "aws",
+				Version: c.version,
+				Installation: &ProviderInstallation{
+					Methods: []ProviderInstallationMethod{
+						{Type: InstallationMethodNetworkMirror, Location: "https://mirror.example.com/providers"},
+					},
+					Lockfile: &lock.Lockfile{Providers: []lock.ProviderBlock{
+						{Address: "registry.terraform.io/hashicorp/aws", Version: c.version, Hashes: c.lockHashes},
+					}},
+				},
+			}
+
+			httpmock.RegisterResponder(
+				"GET",
+				"https://mirror.example.com/providers/registry.terraform.io/hashicorp/aws/3.5.0.json",
+				httpmock.NewStringResponder(http.StatusOK, fmt.Sprintf(
+					`{"archives":{"%s":{"url":"terraform-provider-aws_3.5.0.zip","hashes":["h1:7Ca6K4lpDjeZE6QeTlna6tjY0tgrtODWO6KXgoAplgM="]}}}`,
+					config.GetPlatform(),
+				)),
+			)
+			httpmock.RegisterResponder(
+				"GET",
+				"https://mirror.example.com/providers/registry.terraform.io/hashicorp/aws/3.7.0.json",
+				httpmock.NewStringResponder(http.StatusOK, `{"archives":{"plan9_386":{"url":"terraform-provider-aws_3.7.0.zip"}}}`),
+			)
+			httpmock.RegisterResponder(
+				"GET",
+				"https://mirror.example.com/providers/registry.terraform.io/hashicorp/aws/terraform-provider-aws_3.5.0.zip",
+				httpmock.NewBytesResponder(http.StatusOK, archive),
+			)
+			httpmock.RegisterNoResponder(httpmock.NewStringResponder(http.StatusNotFound, ""))
+
+			installer := ProviderInstaller{
+				downloader: NewProviderDownloader(),
+				config:     config,
+				homeDir:    t.TempDir(),
+			}
+
+			hook := logrustest.NewGlobal()
+			defer logrus.StandardLogger().ReplaceHooks(make(logrus.LevelHooks))
+			providerPath, err := installer.Install()
+			assertWarning(t, hook, c.warning)
+			if c.err != "" {
+				assert.EqualError(t, err, c.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, path.Join(installer.getProviderDirectory(), "terraform-provider-aws_v3.5.0_x5"), providerPath)
+		})
+	}
+}
+
+// assertWarning checks the given warning is the only one logged, or that nothing is when empty
+func assertWarning(t *testing.T, hook *logrustest.Hook, expected string) {
+	warnings := make([]string, 0)
+	for _, entry := range hook.AllEntries() {
+		if entry.Level == logrus.WarnLevel {
+			warnings = append(warnings, entry.Message)
+		}
+	}
+	if expected == "" {
+		assert.Empty(t, warnings)
+		return
+	}
+	assert.Equal(t, []string{expected}, warnings)
+}
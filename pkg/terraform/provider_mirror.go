@@ -0,0 +1,157 @@
+package terraform
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	error2 "github.com/cloudskiff/driftctl/pkg/terraform/error"
+	"github.com/pkg/errors"
+
+	"github.com/cloudskiff/driftctl/pkg/output"
+)
+
+// networkMirrorVersion is the <version>.json document of the provider network mirror protocol
+type networkMirrorVersion struct {
+	Archives map[string]struct {
+		URL    string   `json:"url"`
+		Hashes []string `json:"hashes"`
+	} `json:"archives"`
+}
+
+// installFromFilesystemMirror looks for the provider in a directory using the packed or the unpacked layout
+// of terraform providers mirror
+func (p *ProviderInstaller) installFromFilesystemMirror(dir, providerDir string) error {
+	addr := p.config.GetAddress()
+	base := filepath.Join(dir, addr.Hostname, addr.Namespace, addr.Type)
+	hashes := p.config.Installation.GetHashes(addr, p.config.Version)
+	warnIfUnverified := func() {
+		if len(hashes) == 0 {
+			p.warnUnverified(dir, "Provider installed from a filesystem mirror without checksum verification")
+		}
+	}
+
+	archive := filepath.Join(base, p.config.GetArchiveName())
+	if info, err := os.Stat(archive); err == nil && !info.IsDir() {
+		output.Printf("Installing terraform provider %s from %s\n", p.config.Key, dir)
+		warnIfUnverified()
+		if err := p.verifyArchive(archive, hashes); err != nil {
+			return err
+		}
+		return p.unpack(archive, providerDir)
+	}
+
+	unpacked := filepath.Join(base, p.config.Version, p.config.GetPlatform())
+	if info, err := os.Stat(unpacked); err == nil && info.IsDir() {
+		output.Printf("Installing terraform provider %s from %s\n", p.config.Key, dir)
+		warnIfUnverified()
+		if err := p.verifyDirectory(unpacked, hashes); err != nil {
+			return err
+		}
+		return copyPackage(unpacked, providerDir)
+	}
+
+	return error2.ProviderNotFoundError{}
+}
+
+// installFromNetworkMirror implements the provider network mirror protocol, checksums of the lock file
+// take precedence over the ones given by the mirror
+func (p *ProviderInstaller) installFromNetworkMirror(mirrorURL, providerDir string) error {
+	addr := p.config.GetAddress()
+	base, err := url.Parse(strings.TrimSuffix(mirrorURL, "/") + "/")
+	if err != nil {
+		return errors.Wrapf(err, "invalid network mirror url %s", mirrorURL)
+	}
+	versionURL, err := base.Parse(fmt.Sprintf("%s/%s/%s/%s.json", addr.Hostname, addr.Namespace, addr.Type, p.config.Version))
+	if err != nil {
+		return err
+	}
+
+	resp, err := http.Get(versionURL.String())
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return error2.ProviderNotFoundError{}
+	}
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("unsuccessful request to %s: %s", versionURL, resp.Status)
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	version := networkMirrorVersion{}
+	if err := json.Unmarshal(body, &version); err != nil {
+		return errors.Wrapf(err, "invalid response from network mirror %s", versionURL)
+	}
+
+	archive, exists := version.Archives[p.config.GetPlatform()]
+	if !exists {
+		return error2.ProviderNotFoundError{}
+	}
+	archiveURL, err := versionURL.Parse(archive.URL)
+	if err != nil {
+		return errors.Wrapf(err, "invalid archive url %s", archive.URL)
+	}
+
+	hashes := p.config.Installation.GetHashes(addr, p.config.Version)
+	if len(hashes) == 0 {
+		hashes = archive.Hashes
+		if len(hashes) == 0 {
+			p.warnUnverified(mirrorURL, "Provider installed from a network mirror without checksum verification")
+		} else {
+			p.warnUnverified(mirrorURL, "Provider only verified against the checksums given by the network mirror")
+		}
+	}
+
+	output.Printf("Installing terraform provider %s from %s\n", p.config.Key, mirrorURL)
+	return p.installFromURL(archiveURL.String(), hashes, providerDir)
+}
+
+// copyPackage copies the files of an unpacked provider package in the plugins directory
+func copyPackage(src, dst string) error {
+	files, err := ioutil.ReadDir(src)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dst, 0755); err != nil {
+		return err
+	}
+	for _, file := range files {
+		// Follow symlinks as terraform links packages of its plugin cache
+		info, err := os.Stat(filepath.Join(src, file.Name()))
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			continue
+		}
+		if err := copyFile(filepath.Join(src, file.Name()), filepath.Join(dst, file.Name()), info.Mode()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func copyFile(src, dst string, mode os.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, mode)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	_, err = io.Copy(out, in)
+	return err
+}
@@ -0,0 +1,117 @@
+package azurerm
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// Well known Azurite development account and key
+	AzuriteAccount = "devstoreaccount1"
+	AzuriteKey     = "Eby8vdM02xNOcqFlqUwJPLlmEtlCDXJ1OUzFT50uSRZ6IFsuFq2UVErCz4I6tq/K1SZFPTOtr/KBHBeksoGMGw=="
+
+	fakeBlobPageSize = 2
+)
+
+// FakeBlobServer serves blobs like Azurite does with path style urls (http://host/ACCOUNT/CONTAINER/BLOB),
+// blobs are indexed by container then by blob name. Listings are paginated by fakeBlobPageSize blobs.
+type FakeBlobServer struct {
+	Blobs map[string]map[string][]byte
+}
+
+type fakeBlobList struct {
+	XMLName xml.Name        `xml:"EnumerationResults"`
+	Blobs   []fakeBlobEntry `xml:"Blobs>Blob"`
+	Marker  string          `xml:"NextMarker"`
+}
+
+type fakeBlobEntry struct {
+	Name          string `xml:"Name"`
+	ContentLength int    `xml:"Properties>Content-Length"`
+	Etag          string `xml:"Properties>Etag"`
+	LastModified  string `xml:"Properties>Last-Modified"`
+}
+
+const (
+	fakeBlobEtag         = `"0x8D984A6B4B4C0F2"`
+	fakeBlobLastModified = "Fri, 01 Oct 2021 10:00:00 GMT"
+)
+
+// NewFakeBlobServer starts a fake blob storage server and returns it along with the blob endpoint of the account
+func NewFakeBlobServer(blobs map[string]map[string][]byte) (*httptest.Server, string) {
+	server := httptest.NewServer(&FakeBlobServer{Blobs: blobs})
+	return server, server.URL + "/" + AzuriteAccount
+}
+
+func (s *FakeBlobServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("x-ms-version") == "" || !strings.HasPrefix(r.Header.Get("Authorization"), "SharedKey "+AzuriteAccount+":") {
+		s.error(w, http.StatusForbidden, "AuthorizationFailure")
+		return
+	}
+
+	parts := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/"), "/", 3)
+	if len(parts) < 2 || parts[0] != AzuriteAccount {
+		s.error(w, http.StatusBadRequest, "InvalidUri")
+		return
+	}
+	container, exist := s.Blobs[parts[1]]
+	if !exist {
+		s.error(w, http.StatusNotFound, "ContainerNotFound")
+		return
+	}
+
+	if len(parts) == 2 && r.URL.Query().Get("comp") == "list" {
+		s.list(w, r, container)
+		return
+	}
+	if len(parts) == 3 {
+		content, exist := container[parts[2]]
+		if !exist {
+			s.error(w, http.StatusNotFound, "BlobNotFound")
+			return
+		}
+		w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+		w.Header().Set("ETag", fakeBlobEtag)
+		w.Header().Set("Last-Modified", fakeBlobLastModified)
+		_, _ = w.Write(content)
+		return
+	}
+	s.error(w, http.StatusBadRequest, "InvalidQueryParameterValue")
+}
+
+func (s *FakeBlobServer) list(w http.ResponseWriter, r *http.Request, container map[string][]byte) {
+	prefix := r.URL.Query().Get("prefix")
+	names := make([]string, 0, len(container))
+	for name := range container {
+		if strings.HasPrefix(name, prefix) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	start, _ := strconv.Atoi(r.URL.Query().Get("marker"))
+	list := fakeBlobList{}
+	for i := start; i < len(names) && i < start+fakeBlobPageSize; i++ {
+		list.Blobs = append(list.Blobs, fakeBlobEntry{
+			Name:          names[i],
+			ContentLength: len(container[names[i]]),
+			Etag:          fakeBlobEtag,
+			LastModified:  fakeBlobLastModified,
+		})
+	}
+	if start+fakeBlobPageSize < len(names) {
+		list.Marker = strconv.Itoa(start + fakeBlobPageSize)
+	}
+
+	w.Header().Set("Content-Type", "application/xml")
+	_ = xml.NewEncoder(w).Encode(list)
+}
+
+func (s *FakeBlobServer) error(w http.ResponseWriter, status int, code string) {
+	w.Header().Set("x-ms-error-code", code)
+	w.WriteHeader(status)
+}
@@ -0,0 +1,46 @@
+package consul
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+)
+
+const Token = "consul-token"
+
+// NewFakeKVServer serves the given keys through the consul KV HTTP API, requests must use the Token ACL token
+func NewFakeKVServer(kv map[string][]byte) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Consul-Token") != Token {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte("Permission denied"))
+			return
+		}
+
+		key := strings.TrimPrefix(r.URL.Path, "/v1/kv/")
+		if _, list := r.URL.Query()["keys"]; list {
+			keys := make([]string, 0)
+			for k := range kv {
+				if strings.HasPrefix(k, key) {
+					keys = append(keys, k)
+				}
+			}
+			if len(keys) == 0 {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			sort.Strings(keys)
+			_ = json.NewEncoder(w).Encode(keys)
+			return
+		}
+
+		value, exist := kv[key]
+		if !exist {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(value)
+	}))
+}
@@ -0,0 +1,96 @@
+package google
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+
+	"google.golang.org/api/option"
+)
+
+// FakeStorageServer serves objects through the parts of the GCS JSON and XML APIs used by driftctl,
+// objects are indexed by bucket then by object name
+type FakeStorageServer struct {
+	Objects map[string]map[string][]byte
+}
+
+type fakeStorageObject struct {
+	Kind   string `json:"kind"`
+	Bucket string `json:"bucket"`
+	Name   string `json:"name"`
+	Size   string `json:"size"`
+}
+
+// NewFakeStorageServer starts a fake GCS server, returned options must be given to storage.NewClient
+func NewFakeStorageServer(objects map[string]map[string][]byte) (*httptest.Server, []option.ClientOption) {
+	fake := &FakeStorageServer{Objects: objects}
+	server := httptest.NewTLSServer(fake)
+	return server, []option.ClientOption{
+		option.WithEndpoint(server.URL + "/storage/v1/"),
+		option.WithHTTPClient(server.Client()),
+	}
+}
+
+func (s *FakeStorageServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if strings.HasPrefix(r.URL.Path, "/storage/v1/b/") {
+		s.list(w, r)
+		return
+	}
+	s.read(w, r)
+}
+
+// list handles GET /storage/v1/b/BUCKET/o?prefix=PREFIX
+func (s *FakeStorageServer) list(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/storage/v1/b/"), "/")
+	if len(parts) != 2 || parts[1] != "o" {
+		http.Error(w, "not implemented", http.StatusNotImplemented)
+		return
+	}
+	bucket, exist := s.Objects[parts[0]]
+	if !exist {
+		http.Error(w, "bucket not found", http.StatusNotFound)
+		return
+	}
+
+	prefix := r.URL.Query().Get("prefix")
+	items := make([]fakeStorageObject, 0, len(bucket))
+	for name, content := range bucket {
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		items = append(items, fakeStorageObject{
+			Kind:   "storage#object",
+			Bucket: parts[0],
+			Name:   name,
+			Size:   strconv.Itoa(len(content)),
+		})
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Name < items[j].Name
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"kind":  "storage#objects",
+		"items": items,
+	})
+}
+
+// read handles GET /BUCKET/OBJECT
+func (s *FakeStorageServer) read(w http.ResponseWriter, r *http.Request) {
+	parts := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/"), "/", 2)
+	if len(parts) != 2 {
+		http.NotFound(w, r)
+		return
+	}
+	content, exist := s.Objects[parts[0]][parts[1]]
+	if !exist {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+	_, _ = w.Write(content)
+}
@@ -0,0 +1,111 @@
+package pg
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// FakeStates holds the states table of a terraform pg backend schema
+type FakeStates struct {
+	Schema string
+	States map[string][]byte
+	// Err is returned by every query when set
+	Err error
+}
+
+// NewFakeStatesDB serves the states through database/sql, only answering the queries of the pg backend
+func NewFakeStatesDB(states FakeStates) *sql.DB {
+	return sql.OpenDB(connector{states: &states})
+}
+
+type connector struct {
+	states *FakeStates
+}
+
+func (c connector) Connect(context.Context) (driver.Conn, error) {
+	return conn(c), nil
+}
+
+func (c connector) Driver() driver.Driver {
+	return fakeDriver{}
+}
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(string) (driver.Conn, error) {
+	return nil, errors.New("fake pg databases are opened with NewFakeStatesDB")
+}
+
+type conn struct {
+	states *FakeStates
+}
+
+func (c conn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("prepared statements are not supported")
+}
+
+func (c conn) Close() error {
+	return nil
+}
+
+func (c conn) Begin() (driver.Tx, error) {
+	return nil, errors.New("transactions are not supported")
+}
+
+func (c conn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if c.states.Err != nil {
+		return nil, c.states.Err
+	}
+
+	switch query {
+	case fmt.Sprintf(`SELECT name FROM "%s".states ORDER BY name`, c.states.Schema):
+		names := make([]string, 0, len(c.states.States))
+		for name := range c.states.States {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		values := make([]driver.Value, 0, len(names))
+		for _, name := range names {
+			values = append(values, name)
+		}
+		return &rows{column: "name", values: values}, nil
+	case fmt.Sprintf(`SELECT data FROM "%s".states WHERE name = $1`, c.states.Schema):
+		if len(args) != 1 {
+			return nil, errors.Errorf("expected 1 argument, got %d", len(args))
+		}
+		values := make([]driver.Value, 0, 1)
+		if data, exists := c.states.States[fmt.Sprint(args[0].Value)]; exists {
+			values = append(values, data)
+		}
+		return &rows{column: "data", values: values}, nil
+	}
+
+	return nil, errors.Errorf("unexpected query: %s", query)
+}
+
+type rows struct {
+	column string
+	values []driver.Value
+}
+
+func (r *rows) Columns() []string {
+	return []string{r.column}
+}
+
+func (r *rows) Close() error {
+	return nil
+}
+
+func (r *rows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	dest[0], r.values = r.values[0], r.values[1:]
+	return nil
+}